@@ -2,18 +2,42 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/postgres"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/app"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/logger"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/observability"
+	rbacservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/rbac"
 	"github.com/sirupsen/logrus"
 )
 
+const usage = `Usage: app <command> [options]
+
+Commands:
+  serve                         Start the HTTP server (default)
+  migrate up [n]                Apply all or the next n pending migrations
+  migrate down [n]              Roll back the last n migrations (default 1)
+  migrate status                Show the current and pending migrations
+  seed                          Grant the admin role every permission and create SEED_ADMIN_EMAIL
+  create-admin --email <email>  Create an admin user (--password, or a generated one)
+  rotate-jwt-key                Write a new signing key to JWT_KEYS_DIR
+  rbac export [--format json|yaml] [--output <file>]
+                                Write the RBAC policy to stdout or a file
+  rbac import --file <file> [--dry-run] [--prune]
+                                Apply an RBAC policy (YAML for .yaml/.yml, JSON otherwise)
+`
+
 // @title Boilerplate Go Fiber API
 // @version 0.1.0
 // @description API boilerplate with Go + Fiber.
@@ -23,6 +47,16 @@ import (
 // @in header
 // @name Authorization
 func main() {
+	command := "serve"
+	args := os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+	if command == "help" || command == "-h" || command == "--help" {
+		fmt.Print(usage)
+		return
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		logrus.WithError(err).Fatal("failed to load config")
@@ -30,6 +64,50 @@ func main() {
 
 	log := logger.Init(cfg)
 
+	switch command {
+	case "serve":
+		serve(cfg, log)
+		return
+	case "rotate-jwt-key":
+		kid, path, err := app.RotateJWTKey(cfg)
+		if err != nil {
+			log.WithError(err).Fatal("failed to rotate jwt key")
+		}
+		fmt.Printf("wrote signing key %s to %s\n", kid, path)
+		return
+	case "migrate", "seed", "create-admin", "rbac":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	admin, err := app.NewAdmin(cfg)
+	if err != nil {
+		log.WithError(err).Fatal("failed to connect to postgres")
+	}
+	defer admin.Close()
+
+	var runErr error
+	switch command {
+	case "migrate":
+		runErr = runMigrate(ctx, admin, args)
+	case "seed":
+		runErr = runSeed(ctx, admin)
+	case "create-admin":
+		runErr = runCreateAdmin(ctx, admin, args)
+	case "rbac":
+		runErr = runRBAC(ctx, admin, args)
+	}
+	if runErr != nil {
+		admin.Close()
+		log.WithError(runErr).Fatalf("%s failed", command)
+	}
+}
+
+func serve(cfg config.Config, log *logrus.Logger) {
 	shutdownTracing, err := observability.InitTracing(context.Background(), cfg)
 	if err != nil {
 		log.WithError(err).Fatal("failed to initialize tracing")
@@ -69,3 +147,164 @@ func main() {
 
 	log.Info("shutdown complete")
 }
+
+func runMigrate(ctx context.Context, admin *app.Admin, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate subcommand (up, down or status)")
+	}
+
+	steps := 0
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid step count %q", args[1])
+		}
+		steps = n
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := admin.MigrateUp(ctx, steps)
+		printMigrations("applied", applied)
+		return err
+	case "down":
+		reverted, err := admin.MigrateDown(ctx, steps)
+		printMigrations("reverted", reverted)
+		return err
+	case "status":
+		status, err := admin.MigrationStatus(ctx)
+		if err != nil {
+			return err
+		}
+		dirty := ""
+		if status.Dirty {
+			dirty = " (dirty)"
+		}
+		fmt.Printf("version: %d%s\n", status.Version, dirty)
+		printMigrations("pending", status.Pending)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q", args[0])
+	}
+}
+
+func printMigrations(verb string, migrations []postgres.Migration) {
+	if len(migrations) == 0 {
+		fmt.Printf("%s: none\n", verb)
+		return
+	}
+	for _, migration := range migrations {
+		fmt.Printf("%s: %06d_%s\n", verb, migration.Version, migration.Name)
+	}
+}
+
+func runSeed(ctx context.Context, admin *app.Admin) error {
+	result, err := admin.Seed(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("admin role: %d permissions granted\n", result.GrantedPermissions)
+	if result.AdminCreated {
+		fmt.Println("admin user created")
+	}
+	return nil
+}
+
+func runCreateAdmin(ctx context.Context, admin *app.Admin, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "email address of the new admin")
+	password := flags.String("password", "", "password (generated when empty)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("--email is required")
+	}
+
+	user, generated, err := admin.CreateAdmin(ctx, *email, *password)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created admin %s (%s)\n", user.Email, user.ID)
+	if *password == "" {
+		fmt.Printf("password: %s\n", generated)
+	}
+	return nil
+}
+
+func runRBAC(ctx context.Context, admin *app.Admin, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing rbac subcommand (export or import)")
+	}
+
+	switch args[0] {
+	case "export":
+		flags := flag.NewFlagSet("rbac export", flag.ContinueOnError)
+		format := flags.String("format", rbacdomain.PolicyFormatYAML, "document format (json or yaml)")
+		output := flags.String("output", "", "file to write (stdout when empty)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		policy, err := admin.ExportPolicy(ctx)
+		if err != nil {
+			return err
+		}
+		data, err := rbacdomain.EncodePolicy(policy, *format)
+		if err != nil {
+			return err
+		}
+		if *output == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		return os.WriteFile(*output, data, 0o644)
+	case "import":
+		flags := flag.NewFlagSet("rbac import", flag.ContinueOnError)
+		file := flags.String("file", "", "policy document to apply")
+		dryRun := flags.Bool("dry-run", false, "only print the changes")
+		prune := flags.Bool("prune", false, "delete roles and permissions missing from the document")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *file == "" {
+			return fmt.Errorf("--file is required")
+		}
+
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		format := rbacdomain.PolicyFormatJSON
+		if ext := strings.ToLower(filepath.Ext(*file)); ext == ".yaml" || ext == ".yml" {
+			format = rbacdomain.PolicyFormatYAML
+		}
+		policy, err := rbacdomain.ParsePolicy(data, format)
+		if err != nil {
+			return err
+		}
+
+		result, err := admin.ImportPolicy(ctx, policy, rbacservice.PolicyImportOptions{DryRun: *dryRun, Prune: *prune})
+		if err != nil {
+			return err
+		}
+		for _, change := range result.Changes {
+			line := fmt.Sprintf("%s %s %s", change.Action, change.Kind, change.Name)
+			if change.Target != "" {
+				line += " -> " + change.Target
+			}
+			fmt.Println(line)
+		}
+		switch {
+		case len(result.Changes) == 0:
+			fmt.Println("policy is up to date")
+		case result.Applied:
+			fmt.Printf("applied %d changes\n", len(result.Changes))
+		default:
+			fmt.Printf("dry run: %d changes not applied\n", len(result.Changes))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown rbac subcommand %q", args[0])
+	}
+}
@@ -0,0 +1,246 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	authservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/auth"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Key is a verification key identified by kid. Private is nil for keys that
+// were loaded from a public PEM and can only verify.
+type Key struct {
+	ID      string
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+func signingMethod(alg string) (jwt.SigningMethod, error) {
+	switch alg {
+	case AlgHS256:
+		return jwt.SigningMethodHS256, nil
+	case AlgRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgES256:
+		return jwt.SigningMethodES256, nil
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("jwt: unsupported signing algorithm %q", alg)
+	}
+}
+
+// LoadKeys reads every *.pem file in dir. The file name without extension is
+// the kid; "<kid>.pub.pem" files hold public keys of retired signing keys that
+// must keep verifying until the tokens they signed expire.
+func LoadKeys(dir, alg string) ([]Key, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: read keys dir: %w", err)
+	}
+
+	keys := make([]Key, 0, len(entries))
+	seen := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".pem") {
+			continue
+		}
+		kid := strings.TrimSuffix(strings.TrimSuffix(name, ".pem"), ".pub")
+		if kid == "" {
+			continue
+		}
+		if _, ok := seen[kid]; ok {
+			return nil, fmt.Errorf("jwt: duplicate key id %q", kid)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("jwt: read key %s: %w", name, err)
+		}
+		key, err := ParsePEMKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: parse key %s: %w", name, err)
+		}
+		if err := checkKeyAlgorithm(key, alg); err != nil {
+			return nil, fmt.Errorf("jwt: key %s: %w", name, err)
+		}
+		seen[kid] = struct{}{}
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+// GenerateKey creates a private key for alg: RSA-2048 for RS256, P-256 for
+// ES256 and Ed25519 for EdDSA.
+func GenerateKey(alg string) (crypto.Signer, error) {
+	switch alg {
+	case AlgRS256:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case AlgES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return private, nil
+	default:
+		return nil, fmt.Errorf("jwt: cannot generate a key for %q", alg)
+	}
+}
+
+// NewKeyID returns a timestamp kid that sorts after date-based names such as
+// "2026-10-16", so a freshly written key becomes the active one.
+func NewKeyID(now time.Time) string {
+	return now.UTC().Format("2006-01-02T150405Z")
+}
+
+// WriteKey stores key as "<kid>.pem" in PKCS#8 form and returns the path.
+// Existing files are never overwritten.
+func WriteKey(dir, kid string, key crypto.Signer) (string, error) {
+	if strings.TrimSpace(kid) == "" || strings.ContainsAny(kid, `/\`) {
+		return "", fmt.Errorf("jwt: invalid key id %q", kid)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("jwt: marshal key: %w", err)
+	}
+
+	path := filepath.Join(dir, kid+".pem")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("jwt: create key file: %w", err)
+	}
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		_ = file.Close()
+		return "", fmt.Errorf("jwt: write key file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("jwt: write key file: %w", err)
+	}
+	return path, nil
+}
+
+// ParsePEMKey parses a PKCS#8, PKCS#1 or SEC1 private key, or a PKIX public key.
+func ParsePEMKey(kid string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, err
+		}
+		return Key{ID: kid, Public: public}, nil
+	case "RSA PRIVATE KEY":
+		private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, err
+		}
+		return Key{ID: kid, Private: private, Public: private.Public()}, nil
+	case "EC PRIVATE KEY":
+		private, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, err
+		}
+		return Key{ID: kid, Private: private, Public: private.Public()}, nil
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, err
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return Key{}, errors.New("unsupported private key type")
+		}
+		return Key{ID: kid, Private: signer, Public: signer.Public()}, nil
+	default:
+		return Key{}, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func checkKeyAlgorithm(key Key, alg string) error {
+	switch public := key.Public.(type) {
+	case *rsa.PublicKey:
+		if alg != AlgRS256 {
+			return fmt.Errorf("RSA key cannot be used with %s", alg)
+		}
+		if public.N.BitLen() < 2048 {
+			return errors.New("RSA key must be at least 2048 bits")
+		}
+	case *ecdsa.PublicKey:
+		if alg != AlgES256 {
+			return fmt.Errorf("ECDSA key cannot be used with %s", alg)
+		}
+		if public.Curve != elliptic.P256() {
+			return errors.New("ES256 requires a P-256 key")
+		}
+	case ed25519.PublicKey:
+		if alg != AlgEdDSA {
+			return fmt.Errorf("Ed25519 key cannot be used with %s", alg)
+		}
+	default:
+		return errors.New("unsupported key type")
+	}
+	return nil
+}
+
+func toJSONWebKey(key Key, alg string) (authservice.JSONWebKey, bool) {
+	jwk := authservice.JSONWebKey{
+		Kid: key.ID,
+		Alg: alg,
+		Use: "sig",
+	}
+	switch public := key.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeBase64URL(public.N.Bytes())
+		jwk.E = encodeBase64URL(big.NewInt(int64(public.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (public.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = public.Curve.Params().Name
+		jwk.X = encodeBase64URL(public.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeBase64URL(public.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encodeBase64URL(public)
+	default:
+		return authservice.JSONWebKey{}, false
+	}
+	return jwk, true
+}
+
+func encodeBase64URL(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
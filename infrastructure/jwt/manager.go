@@ -2,21 +2,45 @@ package jwt
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 	authservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/auth"
 )
 
 var ErrInvalidToken = errors.New("jwt: invalid token")
 
 type Manager struct {
+	method    jwt.SigningMethod
 	secret    []byte
+	keys      map[string]Key
+	activeKey Key
 	issuer    string
 	accessTTL time.Duration
 }
 
+// NewManagerFromConfig builds an HS256 manager from JWT_SECRET, or an
+// asymmetric manager from the PEM keys in JWT_KEYS_DIR.
+func NewManagerFromConfig(cfg config.Config) (*Manager, error) {
+	alg := strings.TrimSpace(cfg.JWTSigningAlg)
+	if alg == "" || alg == AlgHS256 {
+		return NewManager(cfg.JWTSecret, cfg.JWTIssuer, cfg.AccessTokenTTL)
+	}
+	if strings.TrimSpace(cfg.JWTKeysDir) == "" {
+		return nil, fmt.Errorf("jwt: JWT_KEYS_DIR is required for %s", alg)
+	}
+	keys, err := LoadKeys(cfg.JWTKeysDir, alg)
+	if err != nil {
+		return nil, err
+	}
+	return NewManagerWithKeys(alg, keys, cfg.JWTActiveKeyID, cfg.JWTIssuer, cfg.AccessTokenTTL)
+}
+
 func NewManager(secret, issuer string, accessTTL time.Duration) (*Manager, error) {
 	if strings.TrimSpace(secret) == "" {
 		return nil, errors.New("jwt: secret is empty")
@@ -25,14 +49,71 @@ func NewManager(secret, issuer string, accessTTL time.Duration) (*Manager, error
 		return nil, errors.New("jwt: access token ttl must be positive")
 	}
 	return &Manager{
+		method:    jwt.SigningMethodHS256,
 		secret:    []byte(secret),
 		issuer:    strings.TrimSpace(issuer),
 		accessTTL: accessTTL,
 	}, nil
 }
 
-func (m *Manager) GenerateAccessToken(userID string, roles, permissions []string, tokenVersion int) (string, int64, error) {
-	if m == nil || len(m.secret) == 0 {
+// NewManagerWithKeys signs with the key identified by activeKID and verifies
+// with every key. When activeKID is empty the lexicographically greatest
+// private key is used, so date-prefixed kids rotate by adding a file.
+func NewManagerWithKeys(alg string, keys []Key, activeKID, issuer string, accessTTL time.Duration) (*Manager, error) {
+	if alg == AlgHS256 {
+		return nil, errors.New("jwt: HS256 uses a shared secret, not keys")
+	}
+	method, err := signingMethod(alg)
+	if err != nil {
+		return nil, err
+	}
+	if accessTTL <= 0 {
+		return nil, errors.New("jwt: access token ttl must be positive")
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwt: no keys configured")
+	}
+
+	byID := make(map[string]Key, len(keys))
+	for _, key := range keys {
+		if strings.TrimSpace(key.ID) == "" {
+			return nil, errors.New("jwt: key id is empty")
+		}
+		if err := checkKeyAlgorithm(key, alg); err != nil {
+			return nil, fmt.Errorf("jwt: key %s: %w", key.ID, err)
+		}
+		byID[key.ID] = key
+	}
+
+	activeKID = strings.TrimSpace(activeKID)
+	if activeKID == "" {
+		ids := make([]string, 0, len(byID))
+		for id, key := range byID {
+			if key.Private != nil {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		if len(ids) > 0 {
+			activeKID = ids[len(ids)-1]
+		}
+	}
+	active, ok := byID[activeKID]
+	if !ok || active.Private == nil {
+		return nil, fmt.Errorf("jwt: active key %q has no private key", activeKID)
+	}
+
+	return &Manager{
+		method:    method,
+		keys:      byID,
+		activeKey: active,
+		issuer:    strings.TrimSpace(issuer),
+		accessTTL: accessTTL,
+	}, nil
+}
+
+func (m *Manager) GenerateAccessToken(userID, sessionID string, roles, permissions []string, grants []rbacdomain.ResourceGrant, tokenVersion int) (string, int64, error) {
+	if m == nil || m.method == nil {
 		return "", 0, ErrInvalidToken
 	}
 	if strings.TrimSpace(userID) == "" {
@@ -47,13 +128,20 @@ func (m *Manager) GenerateAccessToken(userID string, roles, permissions []string
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(m.accessTTL)),
 		},
+		SessionID:    sessionID,
 		Roles:        roles,
 		Permissions:  permissions,
+		Grants:       toGrantClaims(grants),
 		TokenVersion: tokenVersion,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString(m.secret)
+	token := jwt.NewWithClaims(m.method, claims)
+	var signingKey interface{} = m.secret
+	if m.secret == nil {
+		token.Header["kid"] = m.activeKey.ID
+		signingKey = m.activeKey.Private
+	}
+	signed, err := token.SignedString(signingKey)
 	if err != nil {
 		return "", 0, err
 	}
@@ -62,7 +150,7 @@ func (m *Manager) GenerateAccessToken(userID string, roles, permissions []string
 }
 
 func (m *Manager) ParseAccessToken(tokenString string) (authservice.AccessClaims, error) {
-	if m == nil || len(m.secret) == 0 {
+	if m == nil || m.method == nil {
 		return authservice.AccessClaims{}, ErrInvalidToken
 	}
 	if strings.TrimSpace(tokenString) == "" {
@@ -70,7 +158,7 @@ func (m *Manager) ParseAccessToken(tokenString string) (authservice.AccessClaims
 	}
 
 	options := []jwt.ParserOption{
-		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithValidMethods([]string{m.method.Alg()}),
 	}
 	if m.issuer != "" {
 		options = append(options, jwt.WithIssuer(m.issuer))
@@ -78,18 +166,18 @@ func (m *Manager) ParseAccessToken(tokenString string) (authservice.AccessClaims
 
 	claims := accessClaims{}
 	parser := jwt.NewParser(options...)
-	token, err := parser.ParseWithClaims(tokenString, &claims, func(_ *jwt.Token) (interface{}, error) {
-		return m.secret, nil
-	})
+	token, err := parser.ParseWithClaims(tokenString, &claims, m.verificationKey)
 	if err != nil || token == nil || !token.Valid {
 		return authservice.AccessClaims{}, ErrInvalidToken
 	}
 
 	result := authservice.AccessClaims{
 		Subject:      claims.Subject,
+		SessionID:    claims.SessionID,
 		Issuer:       claims.Issuer,
 		Roles:        claims.Roles,
 		Permissions:  claims.Permissions,
+		Grants:       fromGrantClaims(claims.Grants),
 		TokenVersion: claims.TokenVersion,
 	}
 	if claims.ExpiresAt != nil {
@@ -98,9 +186,89 @@ func (m *Manager) ParseAccessToken(tokenString string) (authservice.AccessClaims
 	return result, nil
 }
 
+// PublicKeys returns the JWKS entries for every verification key. HS256
+// managers return nothing since the shared secret must never be published.
+func (m *Manager) PublicKeys() []authservice.JSONWebKey {
+	if m == nil || m.secret != nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(m.keys))
+	for id := range m.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := make([]authservice.JSONWebKey, 0, len(ids))
+	for _, id := range ids {
+		if jwk, ok := toJSONWebKey(m.keys[id], m.method.Alg()); ok {
+			result = append(result, jwk)
+		}
+	}
+	return result
+}
+
+func (m *Manager) ActiveKeyID() string {
+	if m == nil {
+		return ""
+	}
+	return m.activeKey.ID
+}
+
+func (m *Manager) verificationKey(token *jwt.Token) (interface{}, error) {
+	if m.secret != nil {
+		return m.secret, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return key.Public, nil
+}
+
 type accessClaims struct {
 	jwt.RegisteredClaims
-	Roles        []string `json:"roles,omitempty"`
-	Permissions  []string `json:"perms,omitempty"`
-	TokenVersion int      `json:"token_version,omitempty"`
+	SessionID    string       `json:"sid,omitempty"`
+	Roles        []string     `json:"roles,omitempty"`
+	Permissions  []string     `json:"perms,omitempty"`
+	Grants       []grantClaim `json:"grants,omitempty"`
+	TokenVersion int          `json:"token_version,omitempty"`
+}
+
+// grantClaim is the compact JSON form of a resource-scoped grant.
+type grantClaim struct {
+	ResourceType string   `json:"type"`
+	ResourceID   string   `json:"id"`
+	Permissions  []string `json:"perms"`
+}
+
+func toGrantClaims(grants []rbacdomain.ResourceGrant) []grantClaim {
+	if len(grants) == 0 {
+		return nil
+	}
+	result := make([]grantClaim, 0, len(grants))
+	for _, grant := range grants {
+		result = append(result, grantClaim{
+			ResourceType: grant.ResourceType,
+			ResourceID:   grant.ResourceID,
+			Permissions:  grant.Permissions,
+		})
+	}
+	return result
+}
+
+func fromGrantClaims(claims []grantClaim) []rbacdomain.ResourceGrant {
+	if len(claims) == 0 {
+		return nil
+	}
+	result := make([]rbacdomain.ResourceGrant, 0, len(claims))
+	for _, claim := range claims {
+		result = append(result, rbacdomain.ResourceGrant{
+			ResourceType: claim.ResourceType,
+			ResourceID:   claim.ResourceID,
+			Permissions:  claim.Permissions,
+		})
+	}
+	return result
 }
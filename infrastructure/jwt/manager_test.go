@@ -1,8 +1,20 @@
 package jwt
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
+
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 )
 
 func TestManagerGenerateParse(t *testing.T) {
@@ -11,7 +23,9 @@ func TestManagerGenerateParse(t *testing.T) {
 		t.Fatalf("expected manager, got error: %v", err)
 	}
 
-	token, expiresIn, err := manager.GenerateAccessToken("user-1", []string{"admin"}, []string{"user.read"}, 2)
+	token, expiresIn, err := manager.GenerateAccessToken("user-1", "session-1", []string{"admin"}, []string{"user.read"}, []rbacdomain.ResourceGrant{
+		{ResourceType: "merchant", ResourceID: "m-1", Permissions: []string{"invoice.read"}},
+	}, 2)
 	if err != nil {
 		t.Fatalf("expected token, got error: %v", err)
 	}
@@ -26,6 +40,9 @@ func TestManagerGenerateParse(t *testing.T) {
 	if claims.Subject != "user-1" {
 		t.Fatalf("expected subject user-1, got %s", claims.Subject)
 	}
+	if claims.SessionID != "session-1" {
+		t.Fatalf("expected session session-1, got %s", claims.SessionID)
+	}
 	if claims.Issuer != "issuer" {
 		t.Fatalf("expected issuer issuer, got %s", claims.Issuer)
 	}
@@ -38,6 +55,9 @@ func TestManagerGenerateParse(t *testing.T) {
 	if len(claims.Permissions) != 1 || claims.Permissions[0] != "user.read" {
 		t.Fatalf("expected permission user.read, got %v", claims.Permissions)
 	}
+	if len(claims.Grants) != 1 || claims.Grants[0].ResourceID != "m-1" || claims.Grants[0].Permissions[0] != "invoice.read" {
+		t.Fatalf("expected merchant grant, got %+v", claims.Grants)
+	}
 }
 
 func TestManagerParseInvalidToken(t *testing.T) {
@@ -50,3 +70,187 @@ func TestManagerParseInvalidToken(t *testing.T) {
 		t.Fatal("expected error for invalid token")
 	}
 }
+
+func TestManagerAsymmetricAlgorithms(t *testing.T) {
+	cases := []struct {
+		alg string
+		key func(t *testing.T) crypto.Signer
+	}{
+		{AlgRS256, func(t *testing.T) crypto.Signer {
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				t.Fatalf("generate rsa key: %v", err)
+			}
+			return key
+		}},
+		{AlgES256, func(t *testing.T) crypto.Signer {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			if err != nil {
+				t.Fatalf("generate ecdsa key: %v", err)
+			}
+			return key
+		}},
+		{AlgEdDSA, func(t *testing.T) crypto.Signer {
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatalf("generate ed25519 key: %v", err)
+			}
+			return key
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.alg, func(t *testing.T) {
+			dir := t.TempDir()
+			writePrivateKey(t, dir, "2026-01-01", tc.key(t))
+
+			keys, err := LoadKeys(dir, tc.alg)
+			if err != nil {
+				t.Fatalf("expected keys, got error: %v", err)
+			}
+			manager, err := NewManagerWithKeys(tc.alg, keys, "", "issuer", time.Minute)
+			if err != nil {
+				t.Fatalf("expected manager, got error: %v", err)
+			}
+
+			token, _, err := manager.GenerateAccessToken("user-1", "session-1", nil, nil, nil, 1)
+			if err != nil {
+				t.Fatalf("expected token, got error: %v", err)
+			}
+			claims, err := manager.ParseAccessToken(token)
+			if err != nil {
+				t.Fatalf("expected claims, got error: %v", err)
+			}
+			if claims.Subject != "user-1" {
+				t.Fatalf("expected subject user-1, got %s", claims.Subject)
+			}
+
+			jwks := manager.PublicKeys()
+			if len(jwks) != 1 || jwks[0].Kid != "2026-01-01" || jwks[0].Alg != tc.alg {
+				t.Fatalf("unexpected jwks: %+v", jwks)
+			}
+		})
+	}
+}
+
+func TestManagerKeyRotation(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ecdsa key: %v", err)
+	}
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ecdsa key: %v", err)
+	}
+
+	dir := t.TempDir()
+	writePrivateKey(t, dir, "2026-01-01", oldKey)
+	keys, err := LoadKeys(dir, AlgES256)
+	if err != nil {
+		t.Fatalf("expected keys, got error: %v", err)
+	}
+	before, err := NewManagerWithKeys(AlgES256, keys, "", "issuer", time.Minute)
+	if err != nil {
+		t.Fatalf("expected manager, got error: %v", err)
+	}
+	oldToken, _, err := before.GenerateAccessToken("user-1", "session-1", nil, nil, nil, 1)
+	if err != nil {
+		t.Fatalf("expected token, got error: %v", err)
+	}
+
+	// Retire the old private key, keeping only its public half.
+	if err := os.Remove(filepath.Join(dir, "2026-01-01.pem")); err != nil {
+		t.Fatalf("remove old key: %v", err)
+	}
+	writePublicKey(t, dir, "2026-01-01", oldKey.Public())
+	writePrivateKey(t, dir, "2026-02-01", newKey)
+
+	keys, err = LoadKeys(dir, AlgES256)
+	if err != nil {
+		t.Fatalf("expected keys, got error: %v", err)
+	}
+	after, err := NewManagerWithKeys(AlgES256, keys, "", "issuer", time.Minute)
+	if err != nil {
+		t.Fatalf("expected manager, got error: %v", err)
+	}
+	if after.ActiveKeyID() != "2026-02-01" {
+		t.Fatalf("expected active kid 2026-02-01, got %s", after.ActiveKeyID())
+	}
+	if _, err := after.ParseAccessToken(oldToken); err != nil {
+		t.Fatalf("expected token signed by retired key to verify, got error: %v", err)
+	}
+	if len(after.PublicKeys()) != 2 {
+		t.Fatalf("expected 2 published keys, got %d", len(after.PublicKeys()))
+	}
+
+	if _, err := NewManagerWithKeys(AlgES256, keys, "2026-01-01", "issuer", time.Minute); err == nil {
+		t.Fatal("expected error when active key has no private key")
+	}
+}
+
+func TestManagerHS256PublishesNoKeys(t *testing.T) {
+	manager, err := NewManager("secret", "issuer", time.Minute)
+	if err != nil {
+		t.Fatalf("expected manager, got error: %v", err)
+	}
+	if keys := manager.PublicKeys(); len(keys) != 0 {
+		t.Fatalf("expected no public keys, got %d", len(keys))
+	}
+}
+
+func TestGenerateAndWriteKey(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgES256, AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			dir := t.TempDir()
+			key, err := GenerateKey(alg)
+			if err != nil {
+				t.Fatalf("expected key, got error: %v", err)
+			}
+			kid := NewKeyID(time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC))
+			if _, err := WriteKey(dir, kid, key); err != nil {
+				t.Fatalf("expected key file, got error: %v", err)
+			}
+			if _, err := WriteKey(dir, kid, key); err == nil {
+				t.Fatal("expected error when overwriting key file")
+			}
+
+			keys, err := LoadKeys(dir, alg)
+			if err != nil {
+				t.Fatalf("expected keys, got error: %v", err)
+			}
+			if len(keys) != 1 || keys[0].ID != kid || keys[0].Private == nil {
+				t.Fatalf("unexpected keys: %+v", keys)
+			}
+		})
+	}
+
+	if NewKeyID(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)) <= "2026-10-16" {
+		t.Fatal("expected timestamp kid to sort after date-based kid")
+	}
+}
+
+func writePrivateKey(t *testing.T, dir, kid string, key crypto.Signer) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	writePEM(t, filepath.Join(dir, kid+".pem"), "PRIVATE KEY", der)
+}
+
+func writePublicKey(t *testing.T, dir, kid string, key crypto.PublicKey) {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	writePEM(t, filepath.Join(dir, kid+".pub.pem"), "PUBLIC KEY", der)
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
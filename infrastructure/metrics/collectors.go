@@ -0,0 +1,158 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// queueScrapeTimeout bounds the Redis round trips made while serving a scrape.
+const queueScrapeTimeout = 2 * time.Second
+
+type pgxPoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+}
+
+// NewPgxPoolCollector exports pgxpool.Stat on every scrape.
+func NewPgxPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	return &pgxPoolCollector{
+		pool:                 pool,
+		acquiredConns:        prometheus.NewDesc("pgxpool_acquired_conns", "Connections currently checked out of the pool.", nil, nil),
+		idleConns:            prometheus.NewDesc("pgxpool_idle_conns", "Idle connections in the pool.", nil, nil),
+		totalConns:           prometheus.NewDesc("pgxpool_total_conns", "Total connections in the pool.", nil, nil),
+		maxConns:             prometheus.NewDesc("pgxpool_max_conns", "Maximum pool size.", nil, nil),
+		acquireCount:         prometheus.NewDesc("pgxpool_acquire_total", "Successful connection acquisitions.", nil, nil),
+		acquireDuration:      prometheus.NewDesc("pgxpool_acquire_duration_seconds_total", "Time spent acquiring connections.", nil, nil),
+		emptyAcquireCount:    prometheus.NewDesc("pgxpool_empty_acquire_total", "Acquisitions that had to wait for a connection.", nil, nil),
+		canceledAcquireCount: prometheus.NewDesc("pgxpool_canceled_acquire_total", "Acquisitions canceled by their context.", nil, nil),
+	}
+}
+
+func (c *pgxPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquireCount
+	ch <- c.canceledAcquireCount
+}
+
+func (c *pgxPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.pool == nil {
+		return
+	}
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquireCount, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
+
+type redisPoolCollector struct {
+	stats func() *goredis.PoolStats
+
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	timeouts   *prometheus.Desc
+	totalConns *prometheus.Desc
+	idleConns  *prometheus.Desc
+	staleConns *prometheus.Desc
+}
+
+// NewRedisPoolCollector exports go-redis pool stats on every scrape.
+func NewRedisPoolCollector(stats func() *goredis.PoolStats) prometheus.Collector {
+	return &redisPoolCollector{
+		stats:      stats,
+		hits:       prometheus.NewDesc("redis_pool_hits_total", "Times a free connection was found in the pool.", nil, nil),
+		misses:     prometheus.NewDesc("redis_pool_misses_total", "Times a free connection was not found in the pool.", nil, nil),
+		timeouts:   prometheus.NewDesc("redis_pool_timeouts_total", "Times a wait for a connection timed out.", nil, nil),
+		totalConns: prometheus.NewDesc("redis_pool_total_conns", "Total connections in the pool.", nil, nil),
+		idleConns:  prometheus.NewDesc("redis_pool_idle_conns", "Idle connections in the pool.", nil, nil),
+		staleConns: prometheus.NewDesc("redis_pool_stale_conns_total", "Stale connections removed from the pool.", nil, nil),
+	}
+}
+
+func (c *redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.staleConns
+}
+
+func (c *redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.stats == nil {
+		return
+	}
+	stats := c.stats()
+	if stats == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
+
+// QueueDepthFunc returns the number of jobs per state, e.g. "pending" or "dead".
+type QueueDepthFunc func(ctx context.Context) (map[string]int64, error)
+
+type queueCollector struct {
+	queue  string
+	depths QueueDepthFunc
+	depth  *prometheus.Desc
+}
+
+// NewQueueCollector exports queue depths labelled by queue name and state.
+// Depths are read from Redis on every scrape.
+func NewQueueCollector(queue string, depths QueueDepthFunc) prometheus.Collector {
+	return &queueCollector{
+		queue:  queue,
+		depths: depths,
+		depth: prometheus.NewDesc("queue_depth", "Jobs in the queue by state.",
+			[]string{"state"}, prometheus.Labels{"queue": queue}),
+	}
+}
+
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.depth
+}
+
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	if c.depths == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), queueScrapeTimeout)
+	defer cancel()
+
+	depths, err := c.depths(ctx)
+	if err != nil {
+		logrus.WithError(err).WithField("queue", c.queue).Warn("queue depth scrape failed")
+		return
+	}
+	for state, depth := range depths {
+		ch <- prometheus.MustNewConstMetric(c.depth, prometheus.GaugeValue, float64(depth), state)
+	}
+}
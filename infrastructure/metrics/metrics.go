@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds the application's Prometheus collectors. A nil *Registry is
+// valid and records nothing, so callers do not need to check METRICS_ENABLED.
+type Registry struct {
+	registry       *prometheus.Registry
+	httpRequests   *prometheus.CounterVec
+	httpDuration   *prometheus.HistogramVec
+	xenditDuration *prometheus.HistogramVec
+	xenditErrors   *prometheus.CounterVec
+}
+
+func New() *Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	m := &Registry{
+		registry: registry,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total HTTP requests by method, route template and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route template and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		xenditDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "xendit_request_duration_seconds",
+			Help:    "Xendit API call latency by operation and outcome.",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"operation", "outcome"}),
+		xenditErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "xendit_request_errors_total",
+			Help: "Failed Xendit API calls by operation.",
+		}, []string{"operation"}),
+	}
+	registry.MustRegister(m.httpRequests, m.httpDuration, m.xenditDuration, m.xenditErrors)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Registry) Handler() http.Handler {
+	if m == nil {
+		return http.NotFoundHandler()
+	}
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Register adds extra collectors such as the pool and queue collectors.
+func (m *Registry) Register(cs ...prometheus.Collector) error {
+	if m == nil {
+		return nil
+	}
+	for _, c := range cs {
+		if err := m.registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Registry) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func (m *Registry) ObserveXenditCall(operation string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+		m.xenditErrors.WithLabelValues(operation).Inc()
+	}
+	m.xenditDuration.WithLabelValues(operation, outcome).Observe(duration.Seconds())
+}
@@ -0,0 +1,377 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "client-1"
+	testClientSecret = "secret-1"
+	testRedirectURL  = "https://app.example.com/auth/oauth/test/callback"
+)
+
+// fakeProvider is a minimal OpenID Connect provider. Codes are issued by
+// authorize and remember the PKCE challenge and nonce of the authorization
+// request.
+type fakeProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+
+	mu     sync.Mutex
+	codes  map[string]fakeGrant
+	claims func(claims jwt.MapClaims)
+	// signingKey, when set, signs ID tokens instead of the published key.
+	signingKey *rsa.PrivateKey
+	// issuer, when set, is advertised instead of the server URL.
+	issuer string
+	// userinfo is served from the userinfo endpoint when set.
+	userinfo    map[string]any
+	jwksFetches int
+}
+
+type fakeGrant struct {
+	challenge string
+	nonce     string
+	subject   string
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	fake := &fakeProvider{t: t, key: key, kid: "key-1", codes: make(map[string]fakeGrant)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := fake.issuer
+		if issuer == "" {
+			issuer = fake.server.URL
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                                issuer,
+			"authorization_endpoint":                fake.server.URL + "/authorize",
+			"token_endpoint":                        fake.server.URL + "/token",
+			"userinfo_endpoint":                     fake.server.URL + "/userinfo",
+			"jwks_uri":                              fake.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		fake.jwksFetches++
+		kid := fake.kid
+		fake.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": kid,
+			"n":   base64.RawURLEncoding.EncodeToString(fake.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(fake.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", fake.handleToken)
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		info := fake.userinfo
+		fake.mu.Unlock()
+		if info == nil || r.Header.Get("Authorization") != "Bearer access-token" {
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "invalid_token"})
+			return
+		}
+		writeJSON(w, http.StatusOK, info)
+	})
+
+	fake.server = httptest.NewServer(mux)
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+// authorize follows the authorization URL like a browser would after the
+// user consents and returns the code.
+func (f *fakeProvider) authorize(authURL, subject string) string {
+	f.t.Helper()
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		f.t.Fatalf("parse auth url: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		f.t.Fatalf("expected S256 challenge, got %q", query.Get("code_challenge_method"))
+	}
+	code := "code-" + query.Get("state")
+	f.mu.Lock()
+	f.codes[code] = fakeGrant{challenge: query.Get("code_challenge"), nonce: query.Get("nonce"), subject: subject}
+	f.mu.Unlock()
+	return code
+}
+
+func (f *fakeProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_request"})
+		return
+	}
+	clientID, secret, ok := r.BasicAuth()
+	if !ok || clientID != testClientID || secret != testClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "invalid_client"})
+		return
+	}
+
+	f.mu.Lock()
+	grant, found := f.codes[r.PostForm.Get("code")]
+	delete(f.codes, r.PostForm.Get("code"))
+	customize := f.claims
+	f.mu.Unlock()
+	if !found || r.PostForm.Get("redirect_uri") != testRedirectURL {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+		return
+	}
+	if CodeChallenge(r.PostForm.Get("code_verifier")) != grant.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            f.server.URL,
+		"sub":            grant.subject,
+		"aud":            testClientID,
+		"exp":            now.Add(time.Hour).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          grant.nonce,
+		"email":          "ada@example.com",
+		"email_verified": true,
+		"name":           "Ada",
+	}
+	if customize != nil {
+		customize(claims)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"id_token":     f.sign(claims),
+	})
+}
+
+func (f *fakeProvider) sign(claims jwt.MapClaims) string {
+	f.t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	f.mu.Lock()
+	token.Header["kid"] = f.kid
+	key := f.key
+	if f.signingKey != nil {
+		key = f.signingKey
+	}
+	f.mu.Unlock()
+	signed, err := token.SignedString(key)
+	if err != nil {
+		f.t.Fatalf("sign id token: %v", err)
+	}
+	return signed
+}
+
+func (f *fakeProvider) newClient(t *testing.T) *Provider {
+	t.Helper()
+	provider, err := NewProvider(Config{
+		Name:         "test",
+		Issuer:       f.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+	if err != nil {
+		t.Fatalf("expected provider, got error: %v", err)
+	}
+	return provider
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestProviderLogin(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := fake.newClient(t)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("expected auth url, got error: %v", err)
+	}
+	if !strings.HasPrefix(authURL, fake.server.URL+"/authorize?") {
+		t.Fatalf("expected authorization endpoint, got %s", authURL)
+	}
+	query, _ := url.ParseQuery(authURL[strings.Index(authURL, "?")+1:])
+	if query.Get("client_id") != testClientID || query.Get("redirect_uri") != testRedirectURL {
+		t.Fatalf("unexpected client parameters: %v", query)
+	}
+	if query.Get("scope") != "openid email profile" {
+		t.Fatalf("expected default scopes, got %q", query.Get("scope"))
+	}
+
+	code := fake.authorize(authURL, "subject-1")
+	identity, err := provider.Exchange(ctx, code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("expected identity, got error: %v", err)
+	}
+	if identity.Provider != "test" || identity.Subject != "subject-1" {
+		t.Fatalf("unexpected identity: %+v", identity)
+	}
+	if identity.Email != "ada@example.com" || !identity.EmailVerified || identity.Name != "Ada" {
+		t.Fatalf("unexpected profile: %+v", identity)
+	}
+}
+
+func TestProviderRejectsWrongVerifier(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := fake.newClient(t)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("expected auth url, got error: %v", err)
+	}
+	code := fake.authorize(authURL, "subject-1")
+	if _, err := provider.Exchange(ctx, code, "other-verifier", "nonce-1"); !errors.Is(err, ErrExchange) {
+		t.Fatalf("expected exchange error, got %v", err)
+	}
+}
+
+func TestProviderRejectsInvalidIDTokens(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		nonce  string
+		claims func(claims jwt.MapClaims)
+		key    *rsa.PrivateKey
+	}{
+		{name: "nonce mismatch", nonce: "other-nonce"},
+		{name: "wrong audience", nonce: "nonce-1", claims: func(c jwt.MapClaims) { c["aud"] = "other-client" }},
+		{name: "wrong issuer", nonce: "nonce-1", claims: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "expired", nonce: "nonce-1", claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "foreign azp", nonce: "nonce-1", claims: func(c jwt.MapClaims) {
+			c["aud"] = []string{testClientID, "other-client"}
+			c["azp"] = "other-client"
+		}},
+		{name: "bad signature", nonce: "nonce-1", key: otherKey},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := newFakeProvider(t)
+			fake.claims = tc.claims
+			fake.signingKey = tc.key
+			provider := fake.newClient(t)
+			ctx := context.Background()
+
+			authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+			if err != nil {
+				t.Fatalf("expected auth url, got error: %v", err)
+			}
+			code := fake.authorize(authURL, "subject-1")
+			if _, err := provider.Exchange(ctx, code, "verifier-1", tc.nonce); !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("expected invalid id token, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProviderUserinfoFallback(t *testing.T) {
+	fake := newFakeProvider(t)
+	fake.claims = func(c jwt.MapClaims) {
+		delete(c, "email")
+		delete(c, "email_verified")
+	}
+	fake.userinfo = map[string]any{"sub": "subject-1", "email": "ada@example.com", "email_verified": "true"}
+	provider := fake.newClient(t)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("expected auth url, got error: %v", err)
+	}
+	identity, err := provider.Exchange(ctx, fake.authorize(authURL, "subject-1"), "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("expected identity, got error: %v", err)
+	}
+	if identity.Email != "ada@example.com" || !identity.EmailVerified {
+		t.Fatalf("expected email from userinfo, got %+v", identity)
+	}
+
+	fake.userinfo = map[string]any{"sub": "someone-else", "email": "eve@example.com", "email_verified": true}
+	authURL, _ = provider.AuthCodeURL(ctx, "state-2", "nonce-2", "verifier-2")
+	if _, err := provider.Exchange(ctx, fake.authorize(authURL, "subject-1"), "verifier-2", "nonce-2"); !errors.Is(err, ErrExchange) {
+		t.Fatalf("expected subject mismatch error, got %v", err)
+	}
+}
+
+func TestProviderKeyRotation(t *testing.T) {
+	fake := newFakeProvider(t)
+	provider := fake.newClient(t)
+	ctx := context.Background()
+
+	authURL, _ := provider.AuthCodeURL(ctx, "state-1", "nonce-1", "verifier-1")
+	if _, err := provider.Exchange(ctx, fake.authorize(authURL, "subject-1"), "verifier-1", "nonce-1"); err != nil {
+		t.Fatalf("expected identity, got error: %v", err)
+	}
+
+	rotated, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	fake.mu.Lock()
+	fake.key = rotated
+	fake.kid = "key-2"
+	fake.mu.Unlock()
+	// Allow the refetch that a new kid triggers.
+	provider.mu.Lock()
+	provider.keysFetchedAt = time.Now().Add(-keyRefreshDelay)
+	provider.mu.Unlock()
+
+	authURL, _ = provider.AuthCodeURL(ctx, "state-2", "nonce-2", "verifier-2")
+	if _, err := provider.Exchange(ctx, fake.authorize(authURL, "subject-1"), "verifier-2", "nonce-2"); err != nil {
+		t.Fatalf("expected identity after rotation, got error: %v", err)
+	}
+	if fake.jwksFetches != 2 {
+		t.Fatalf("expected key set to be fetched twice, got %d", fake.jwksFetches)
+	}
+}
+
+func TestProviderDiscoveryIssuerMismatch(t *testing.T) {
+	fake := newFakeProvider(t)
+	fake.issuer = "https://evil.example.com"
+	provider := fake.newClient(t)
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); !errors.Is(err, ErrDiscovery) {
+		t.Fatalf("expected discovery error, got %v", err)
+	}
+}
+
+func TestNewProviderRequiresConfig(t *testing.T) {
+	if _, err := NewProvider(Config{Name: "test", Issuer: "https://idp.example.com"}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected invalid config, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const breachedPrefixLength = 5
+
+// BreachedList looks passwords up in a local copy of a breached password
+// list keyed by uppercase hex SHA-1, such as the Pwned Passwords downloads.
+// The path is either:
+//
+//   - a directory of hash-prefix files named by the first five hex digits
+//     (e.g. 5BAA6 or 5BAA6.txt), each with SUFFIX[:COUNT] lines, the layout
+//     of the k-anonymity range API; or
+//   - one file of HASH[:COUNT] lines sorted by hash, searched without
+//     loading it into memory.
+type BreachedList struct {
+	path string
+	dir  bool
+}
+
+func OpenBreachedList(path string) (*BreachedList, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: breached password list: %v", ErrInvalidConfig, err)
+	}
+	return &BreachedList{path: path, dir: info.IsDir()}, nil
+}
+
+// Contains reports whether password is in the list.
+func (l *BreachedList) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	if l.dir {
+		return l.containsInRange(hash[:breachedPrefixLength], hash[breachedPrefixLength:])
+	}
+	return l.containsInSorted(hash)
+}
+
+func (l *BreachedList) containsInRange(prefix, suffix string) (bool, error) {
+	var file *os.File
+	for _, name := range []string{prefix, prefix + ".txt"} {
+		f, err := os.Open(filepath.Join(l.path, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		file = f
+		break
+	}
+	if file == nil {
+		return false, nil
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if breachedKey(scanner.Text()) == suffix {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// containsInSorted binary searches the file by byte offset. If hash is in the
+// file, its line starts in [lo, hi).
+func (l *BreachedList) containsInSorted(hash string) (bool, error) {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	lo, hi := int64(0), info.Size()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, line, err := lineAtOrAfter(file, mid, info.Size())
+		if err != nil {
+			return false, err
+		}
+		if start >= info.Size() {
+			hi = mid
+			continue
+		}
+		switch key := breachedKey(line); {
+		case key == hash:
+			return true, nil
+		case key < hash:
+			lo = start + int64(len(line)) + 1
+		default:
+			hi = mid
+		}
+	}
+	return false, nil
+}
+
+// lineAtOrAfter returns the first line starting at or after offset, without
+// its newline. start is size when there is none.
+func lineAtOrAfter(file *os.File, offset, size int64) (int64, string, error) {
+	start := offset
+	if offset > 0 {
+		// Skip the rest of the line offset falls in, unless offset is
+		// already the start of a line.
+		reader := bufio.NewReader(io.NewSectionReader(file, offset-1, size-offset+1))
+		skipped, err := reader.ReadString('\n')
+		if errors.Is(err, io.EOF) {
+			return size, "", nil
+		}
+		if err != nil {
+			return 0, "", err
+		}
+		start = offset - 1 + int64(len(skipped))
+	}
+	if start >= size {
+		return size, "", nil
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(file, start, size-start))
+	line, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, "", err
+	}
+	return start, strings.TrimSuffix(line, "\n"), nil
+}
+
+// breachedKey returns the uppercase hash of a HASH[:COUNT] line.
+func breachedKey(line string) string {
+	key, _, _ := strings.Cut(line, ":")
+	return strings.ToUpper(strings.TrimSpace(key))
+}
@@ -0,0 +1,239 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
+)
+
+const (
+	AlgArgon2id = "argon2id"
+	AlgBcrypt   = "bcrypt"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var (
+	ErrMismatch      = errors.New("password: hash does not match")
+	ErrInvalidHash   = errors.New("password: invalid hash")
+	ErrInvalidConfig = errors.New("password: invalid config")
+)
+
+var phcEncoding = base64.RawStdEncoding
+
+// Config selects the algorithm new hashes use. Memory is in KiB.
+type Config struct {
+	Algorithm         string
+	Argon2Memory      uint32
+	Argon2Time        uint32
+	Argon2Parallelism uint8
+	BcryptCost        int
+}
+
+// Hasher hashes passwords with the configured algorithm and verifies hashes
+// produced by either algorithm, so existing bcrypt hashes keep working after
+// switching to argon2id. Argon2id hashes use the PHC string format:
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+//
+// bcrypt hashes keep their standard $2a$/$2b$ format.
+type Hasher struct {
+	cfg Config
+}
+
+// NewFromConfig builds a hasher from the PASSWORD_* settings.
+func NewFromConfig(cfg config.Config) (*Hasher, error) {
+	if cfg.PasswordArgon2Memory < 0 || int64(cfg.PasswordArgon2Memory) > math.MaxUint32 ||
+		cfg.PasswordArgon2Time < 0 || int64(cfg.PasswordArgon2Time) > math.MaxUint32 ||
+		cfg.PasswordArgon2Parallelism < 0 || cfg.PasswordArgon2Parallelism > math.MaxUint8 {
+		return nil, fmt.Errorf("%w: argon2 parameters out of range", ErrInvalidConfig)
+	}
+	return New(Config{
+		Algorithm:         cfg.PasswordHashAlgorithm,
+		Argon2Memory:      uint32(cfg.PasswordArgon2Memory),
+		Argon2Time:        uint32(cfg.PasswordArgon2Time),
+		Argon2Parallelism: uint8(cfg.PasswordArgon2Parallelism),
+		BcryptCost:        cfg.PasswordBcryptCost,
+	})
+}
+
+func New(cfg Config) (*Hasher, error) {
+	cfg.Algorithm = strings.ToLower(strings.TrimSpace(cfg.Algorithm))
+	switch cfg.Algorithm {
+	case AlgArgon2id:
+		if cfg.Argon2Time < 1 || cfg.Argon2Parallelism < 1 {
+			return nil, fmt.Errorf("%w: argon2 time and parallelism must be at least 1", ErrInvalidConfig)
+		}
+		if cfg.Argon2Memory < 8*uint32(cfg.Argon2Parallelism) {
+			return nil, fmt.Errorf("%w: argon2 memory must be at least 8 KiB per lane", ErrInvalidConfig)
+		}
+	case AlgBcrypt:
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("%w: bcrypt cost must be between %d and %d", ErrInvalidConfig, bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidConfig, cfg.Algorithm)
+	}
+	return &Hasher{cfg: cfg}, nil
+}
+
+// Hash returns a new hash of password with the configured algorithm.
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.cfg.Algorithm == AlgBcrypt {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.cfg.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashed), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	params := argon2Params{
+		memory:      h.cfg.Argon2Memory,
+		time:        h.cfg.Argon2Time,
+		parallelism: h.cfg.Argon2Parallelism,
+	}
+	key := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.parallelism, argon2KeyLength)
+	return params.encode(salt, key), nil
+}
+
+// Compare checks password against encoded, which may use either algorithm.
+// It returns ErrMismatch when the password is wrong and ErrInvalidHash when
+// encoded is not a recognized hash.
+func (h *Hasher) Compare(encoded, password string) error {
+	switch {
+	case isBcrypt(encoded):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrMismatch
+		}
+		if err != nil {
+			return ErrInvalidHash
+		}
+		return nil
+	case strings.HasPrefix(encoded, "$"+AlgArgon2id+"$"):
+		params, salt, key, err := decodeArgon2(encoded)
+		if err != nil {
+			return err
+		}
+		derived := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(derived, key) != 1 {
+			return ErrMismatch
+		}
+		return nil
+	default:
+		return ErrInvalidHash
+	}
+}
+
+// NeedsRehash reports whether encoded was produced with another algorithm
+// or weaker parameters than the hasher is configured with. Call it after a
+// successful Compare, while the plaintext password is at hand.
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	if h.cfg.Algorithm == AlgBcrypt {
+		if !isBcrypt(encoded) {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return err != nil || cost != h.cfg.BcryptCost
+	}
+
+	params, salt, key, err := decodeArgon2(encoded)
+	if err != nil {
+		return true
+	}
+	return params.memory != h.cfg.Argon2Memory ||
+		params.time != h.cfg.Argon2Time ||
+		params.parallelism != h.cfg.Argon2Parallelism ||
+		len(salt) < argon2SaltLength ||
+		len(key) != argon2KeyLength
+}
+
+type argon2Params struct {
+	memory      uint32
+	time        uint32
+	parallelism uint8
+}
+
+func (p argon2Params) encode(salt, key []byte) string {
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		AlgArgon2id, argon2.Version, p.memory, p.time, p.parallelism,
+		phcEncoding.EncodeToString(salt), phcEncoding.EncodeToString(key))
+}
+
+func decodeArgon2(encoded string) (argon2Params, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != AlgArgon2id {
+		return argon2Params{}, nil, nil, ErrInvalidHash
+	}
+	if parts[2] != "v="+strconv.Itoa(argon2.Version) {
+		return argon2Params{}, nil, nil, ErrInvalidHash
+	}
+
+	var params argon2Params
+	seen := 0
+	for _, field := range strings.Split(parts[3], ",") {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return argon2Params{}, nil, nil, ErrInvalidHash
+		}
+		var bits int
+		switch name {
+		case "m", "t":
+			bits = 32
+		case "p":
+			bits = 8
+		default:
+			return argon2Params{}, nil, nil, ErrInvalidHash
+		}
+		n, err := strconv.ParseUint(value, 10, bits)
+		if err != nil || n == 0 {
+			return argon2Params{}, nil, nil, ErrInvalidHash
+		}
+		switch name {
+		case "m":
+			params.memory = uint32(n)
+			seen |= 1
+		case "t":
+			params.time = uint32(n)
+			seen |= 2
+		case "p":
+			params.parallelism = uint8(n)
+			seen |= 4
+		}
+	}
+	if seen != 7 {
+		return argon2Params{}, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := phcEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return argon2Params{}, nil, nil, ErrInvalidHash
+	}
+	key, err := phcEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return argon2Params{}, nil, nil, ErrInvalidHash
+	}
+	return params, salt, key, nil
+}
+
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
@@ -0,0 +1,139 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newArgon2Hasher(t *testing.T, memory, time uint32) *Hasher {
+	t.Helper()
+	hasher, err := New(Config{Algorithm: AlgArgon2id, Argon2Memory: memory, Argon2Time: time, Argon2Parallelism: 1})
+	if err != nil {
+		t.Fatalf("expected hasher, got error: %v", err)
+	}
+	return hasher
+}
+
+func TestArgon2idHashAndCompare(t *testing.T) {
+	hasher := newArgon2Hasher(t, 1024, 1)
+
+	encoded, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("expected hash, got error: %v", err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("expected PHC argon2id hash, got %s", encoded)
+	}
+	if strings.Count(encoded, "$") != 5 {
+		t.Fatalf("expected salt and hash segments, got %s", encoded)
+	}
+
+	if err := hasher.Compare(encoded, "correct horse"); err != nil {
+		t.Fatalf("expected match, got error: %v", err)
+	}
+	if err := hasher.Compare(encoded, "battery staple"); !errors.Is(err, ErrMismatch) {
+		t.Fatalf("expected ErrMismatch, got %v", err)
+	}
+
+	other, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("expected hash, got error: %v", err)
+	}
+	if other == encoded {
+		t.Fatal("expected a fresh salt per hash")
+	}
+	if hasher.NeedsRehash(encoded) {
+		t.Fatal("expected current hash not to need rehash")
+	}
+}
+
+func TestCompareLegacyBcrypt(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("expected bcrypt hash, got error: %v", err)
+	}
+	hasher := newArgon2Hasher(t, 1024, 1)
+
+	if err := hasher.Compare(string(legacy), "correct horse"); err != nil {
+		t.Fatalf("expected bcrypt hash to verify, got error: %v", err)
+	}
+	if err := hasher.Compare(string(legacy), "battery staple"); !errors.Is(err, ErrMismatch) {
+		t.Fatalf("expected ErrMismatch, got %v", err)
+	}
+	if !hasher.NeedsRehash(string(legacy)) {
+		t.Fatal("expected bcrypt hash to need rehash under argon2id")
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	weak := newArgon2Hasher(t, 1024, 1)
+	weakHash, err := weak.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("expected hash, got error: %v", err)
+	}
+	strong := newArgon2Hasher(t, 2048, 2)
+	if err := strong.Compare(weakHash, "correct horse"); err != nil {
+		t.Fatalf("expected hash with other parameters to verify, got error: %v", err)
+	}
+	if !strong.NeedsRehash(weakHash) {
+		t.Fatal("expected weaker parameters to need rehash")
+	}
+
+	bcryptHasher, err := New(Config{Algorithm: AlgBcrypt, BcryptCost: bcrypt.MinCost + 1})
+	if err != nil {
+		t.Fatalf("expected hasher, got error: %v", err)
+	}
+	if !bcryptHasher.NeedsRehash(weakHash) {
+		t.Fatal("expected argon2id hash to need rehash under bcrypt")
+	}
+	cheap, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("expected bcrypt hash, got error: %v", err)
+	}
+	if !bcryptHasher.NeedsRehash(string(cheap)) {
+		t.Fatal("expected lower bcrypt cost to need rehash")
+	}
+	current, err := bcryptHasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("expected hash, got error: %v", err)
+	}
+	if bcryptHasher.NeedsRehash(current) {
+		t.Fatal("expected current bcrypt hash not to need rehash")
+	}
+}
+
+func TestCompareInvalidHash(t *testing.T) {
+	hasher := newArgon2Hasher(t, 1024, 1)
+	cases := []string{
+		"",
+		"plaintext",
+		"$argon2i$v=19$m=1024,t=1,p=1$c2FsdHNhbHQ$aGFzaA",
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdHNhbHQ$aGFzaA",
+		"$argon2id$v=19$m=1024,t=1$c2FsdHNhbHQ$aGFzaA",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdHNhbHQ$aGFzaA",
+		"$argon2id$v=19$m=1024,t=1,p=1$not base64!$aGFzaA",
+		"$2a$04$tooshort",
+	}
+	for _, encoded := range cases {
+		if err := hasher.Compare(encoded, "correct horse"); !errors.Is(err, ErrInvalidHash) {
+			t.Fatalf("%q: expected ErrInvalidHash, got %v", encoded, err)
+		}
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	cases := []Config{
+		{Algorithm: "scrypt"},
+		{Algorithm: AlgArgon2id, Argon2Memory: 1024, Argon2Time: 0, Argon2Parallelism: 1},
+		{Algorithm: AlgArgon2id, Argon2Memory: 4, Argon2Time: 1, Argon2Parallelism: 1},
+		{Algorithm: AlgBcrypt, BcryptCost: 3},
+	}
+	for _, cfg := range cases {
+		if _, err := New(cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("%+v: expected ErrInvalidConfig, got %v", cfg, err)
+		}
+	}
+}
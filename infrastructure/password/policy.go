@@ -0,0 +1,159 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
+	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+)
+
+// bcryptMaxBytes is the longest password bcrypt hashes; it ignores the rest.
+const bcryptMaxBytes = 72
+
+// PolicyConfig sets the rules a new password must pass. MinLength counts
+// characters and MaxLength bytes. History is how many previous passwords
+// cannot be reused. BreachedList is an optional path to a breached password
+// list (see BreachedList).
+type PolicyConfig struct {
+	MinLength           int
+	MaxLength           int
+	MinCharacterClasses int
+	History             int
+	BreachedList        string
+}
+
+// Policy checks new passwords against PolicyConfig.
+type Policy struct {
+	cfg      PolicyConfig
+	hasher   *Hasher
+	breached *BreachedList
+}
+
+// NewPolicyFromConfig builds a policy from the PASSWORD_* settings.
+func NewPolicyFromConfig(cfg config.Config, hasher *Hasher) (*Policy, error) {
+	return NewPolicy(PolicyConfig{
+		MinLength:           cfg.PasswordMinLength,
+		MaxLength:           cfg.PasswordMaxLength,
+		MinCharacterClasses: cfg.PasswordMinCharacterClasses,
+		History:             cfg.PasswordHistory,
+		BreachedList:        cfg.PasswordBreachedList,
+	}, hasher)
+}
+
+func NewPolicy(cfg PolicyConfig, hasher *Hasher) (*Policy, error) {
+	if hasher == nil {
+		return nil, fmt.Errorf("%w: hasher is nil", ErrInvalidConfig)
+	}
+	if cfg.MinLength < 1 {
+		return nil, fmt.Errorf("%w: minimum length must be at least 1", ErrInvalidConfig)
+	}
+	if cfg.MaxLength < cfg.MinLength {
+		return nil, fmt.Errorf("%w: maximum length is below the minimum", ErrInvalidConfig)
+	}
+	if hasher.cfg.Algorithm == AlgBcrypt && cfg.MaxLength > bcryptMaxBytes {
+		return nil, fmt.Errorf("%w: bcrypt only uses the first %d bytes of a password", ErrInvalidConfig, bcryptMaxBytes)
+	}
+	if cfg.MinCharacterClasses < 0 || cfg.MinCharacterClasses > 4 {
+		return nil, fmt.Errorf("%w: character classes must be between 0 and 4", ErrInvalidConfig)
+	}
+	if cfg.History < 0 {
+		return nil, fmt.Errorf("%w: history must not be negative", ErrInvalidConfig)
+	}
+
+	policy := &Policy{cfg: cfg, hasher: hasher}
+	if strings.TrimSpace(cfg.BreachedList) != "" {
+		breached, err := OpenBreachedList(cfg.BreachedList)
+		if err != nil {
+			return nil, err
+		}
+		policy.breached = breached
+	}
+	return policy, nil
+}
+
+// HistorySize is how many previous password hashes Check needs.
+func (p *Policy) HistorySize() int {
+	return p.cfg.History
+}
+
+// Check returns a *authdomain.PasswordPolicyError listing every rule password
+// fails. previousHashes are the user's recent password hashes, newest first;
+// only the first HistorySize are checked. Other errors come from reading the
+// breached password list.
+func (p *Policy) Check(password, email string, previousHashes []string) error {
+	var violations []authdomain.PasswordViolation
+	add := func(code, message string) {
+		violations = append(violations, authdomain.PasswordViolation{Code: code, Message: message})
+	}
+
+	if utf8.RuneCountInString(password) < p.cfg.MinLength {
+		add(authdomain.PasswordTooShort, fmt.Sprintf("password must be at least %d characters", p.cfg.MinLength))
+	}
+	if len(password) > p.cfg.MaxLength {
+		add(authdomain.PasswordTooLong, fmt.Sprintf("password must be at most %d bytes", p.cfg.MaxLength))
+	}
+	if p.cfg.MinCharacterClasses > 0 && characterClasses(password) < p.cfg.MinCharacterClasses {
+		add(authdomain.PasswordCharacterClasses, fmt.Sprintf(
+			"password must contain at least %d of: lowercase letters, uppercase letters, digits, symbols", p.cfg.MinCharacterClasses))
+	}
+	if matchesEmail(password, email) {
+		add(authdomain.PasswordMatchesEmail, "password must not match the email address")
+	}
+
+	if len(previousHashes) > p.cfg.History {
+		previousHashes = previousHashes[:p.cfg.History]
+	}
+	for _, hash := range previousHashes {
+		if p.hasher.Compare(hash, password) == nil {
+			add(authdomain.PasswordReused, fmt.Sprintf("password must differ from the last %d passwords", p.cfg.History))
+			break
+		}
+	}
+
+	if p.breached != nil {
+		found, err := p.breached.Contains(password)
+		if err != nil {
+			return err
+		}
+		if found {
+			add(authdomain.PasswordBreached, "password appears in a known data breach")
+		}
+	}
+
+	if len(violations) > 0 {
+		return &authdomain.PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// matchesEmail reports whether password is the email address or its local
+// part, ignoring case and surrounding spaces.
+func matchesEmail(password, email string) bool {
+	password = strings.ToLower(strings.TrimSpace(password))
+	email = strings.ToLower(strings.TrimSpace(email))
+	if password == "" || email == "" {
+		return false
+	}
+	local, _, _ := strings.Cut(email, "@")
+	return password == email || password == local
+}
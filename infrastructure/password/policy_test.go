@@ -0,0 +1,177 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+)
+
+func newPolicy(t *testing.T, cfg PolicyConfig) *Policy {
+	t.Helper()
+	policy, err := NewPolicy(cfg, newArgon2Hasher(t, 1024, 1))
+	if err != nil {
+		t.Fatalf("expected policy, got error: %v", err)
+	}
+	return policy
+}
+
+func violationCodes(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var policyErr *authdomain.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected PasswordPolicyError, got %v", err)
+	}
+	if !errors.Is(err, authdomain.ErrWeakPassword) {
+		t.Fatal("expected PasswordPolicyError to match ErrWeakPassword")
+	}
+	codes := make([]string, 0, len(policyErr.Violations))
+	for _, violation := range policyErr.Violations {
+		codes = append(codes, violation.Code)
+	}
+	return codes
+}
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestPolicyRules(t *testing.T) {
+	policy := newPolicy(t, PolicyConfig{MinLength: 8, MaxLength: 16, MinCharacterClasses: 3})
+
+	cases := []struct {
+		password string
+		email    string
+		want     []string
+	}{
+		{"Tr0ub4dor&3", "user@example.com", nil},
+		{"Ab1!", "user@example.com", []string{authdomain.PasswordTooShort}},
+		{"Aa1!Aa1!Aa1!Aa1!x", "user@example.com", []string{authdomain.PasswordTooLong}},
+		{"alllowercase", "user@example.com", []string{authdomain.PasswordCharacterClasses}},
+		{"Lowercase1", "user@example.com", nil},
+		{"User@Example.com", "user@example.com", []string{authdomain.PasswordMatchesEmail}},
+		{"Jane.Doe1", "jane.doe1@example.com", []string{authdomain.PasswordMatchesEmail}},
+		{"short", "short@example.com", []string{authdomain.PasswordTooShort, authdomain.PasswordCharacterClasses, authdomain.PasswordMatchesEmail}},
+		// Length counts characters, so multi-byte passwords are not penalized.
+		{"Pässwörd1", "user@example.com", nil},
+	}
+	for _, tc := range cases {
+		got := violationCodes(t, policy.Check(tc.password, tc.email, nil))
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Fatalf("%q: expected %v, got %v", tc.password, tc.want, got)
+		}
+	}
+}
+
+func TestPolicyHistory(t *testing.T) {
+	hasher := newArgon2Hasher(t, 1024, 1)
+	policy, err := NewPolicy(PolicyConfig{MinLength: 8, MaxLength: 72, History: 2}, hasher)
+	if err != nil {
+		t.Fatalf("expected policy, got error: %v", err)
+	}
+
+	var history []string
+	for _, password := range []string{"oldest password", "older password", "recent password"} {
+		hash, err := hasher.Hash(password)
+		if err != nil {
+			t.Fatalf("expected hash, got error: %v", err)
+		}
+		history = append([]string{hash}, history...)
+	}
+
+	for _, reused := range []string{"recent password", "older password"} {
+		got := violationCodes(t, policy.Check(reused, "user@example.com", history))
+		if len(got) != 1 || got[0] != authdomain.PasswordReused {
+			t.Fatalf("%q: expected reuse violation, got %v", reused, got)
+		}
+	}
+	// Only the last History passwords are remembered.
+	if err := policy.Check("oldest password", "user@example.com", history); err != nil {
+		t.Fatalf("expected password beyond history to pass, got %v", err)
+	}
+}
+
+func TestBreachedListRangeDirectory(t *testing.T) {
+	dir := t.TempDir()
+	hash := sha1Hex("password1")
+	content := "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n" + hash[5:] + ":2427\r\n"
+	if err := os.WriteFile(filepath.Join(dir, hash[:5]+".txt"), []byte(content), 0o600); err != nil {
+		t.Fatalf("write range file: %v", err)
+	}
+
+	policy := newPolicy(t, PolicyConfig{MinLength: 8, MaxLength: 72, BreachedList: dir})
+	got := violationCodes(t, policy.Check("password1", "user@example.com", nil))
+	if len(got) != 1 || got[0] != authdomain.PasswordBreached {
+		t.Fatalf("expected breached violation, got %v", got)
+	}
+	if err := policy.Check("correct horse battery", "user@example.com", nil); err != nil {
+		t.Fatalf("expected password without a range file to pass, got %v", err)
+	}
+}
+
+func TestBreachedListSortedFile(t *testing.T) {
+	breached := []string{"password", "123456", "qwerty", "letmein", "iloveyou", "monkey", "dragon"}
+	var lines []string
+	for _, password := range breached {
+		lines = append(lines, sha1Hex(password)+":"+"42")
+	}
+	for i := 0; i < 200; i++ {
+		lines = append(lines, sha1Hex(strings.Repeat("x", i+1)+"filler"))
+	}
+	sort.Strings(lines)
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatalf("write list: %v", err)
+	}
+
+	list, err := OpenBreachedList(path)
+	if err != nil {
+		t.Fatalf("expected list, got error: %v", err)
+	}
+	for _, password := range breached {
+		found, err := list.Contains(password)
+		if err != nil || !found {
+			t.Fatalf("%q: expected found, got %v (err %v)", password, found, err)
+		}
+	}
+	for _, password := range []string{"correct horse battery", "", "zzzzzz"} {
+		found, err := list.Contains(password)
+		if err != nil || found {
+			t.Fatalf("%q: expected not found, got %v (err %v)", password, found, err)
+		}
+	}
+}
+
+func TestNewPolicyRejectsInvalidConfig(t *testing.T) {
+	argon2Hasher := newArgon2Hasher(t, 1024, 1)
+	bcryptHasher, err := New(Config{Algorithm: AlgBcrypt, BcryptCost: 4})
+	if err != nil {
+		t.Fatalf("expected hasher, got error: %v", err)
+	}
+
+	cases := []struct {
+		cfg    PolicyConfig
+		hasher *Hasher
+	}{
+		{PolicyConfig{MinLength: 0, MaxLength: 72}, argon2Hasher},
+		{PolicyConfig{MinLength: 12, MaxLength: 8}, argon2Hasher},
+		{PolicyConfig{MinLength: 8, MaxLength: 128}, bcryptHasher},
+		{PolicyConfig{MinLength: 8, MaxLength: 72, MinCharacterClasses: 5}, argon2Hasher},
+		{PolicyConfig{MinLength: 8, MaxLength: 72, BreachedList: filepath.Join(t.TempDir(), "missing")}, argon2Hasher},
+	}
+	for _, tc := range cases {
+		if _, err := NewPolicy(tc.cfg, tc.hasher); !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("%+v: expected ErrInvalidConfig, got %v", tc.cfg, err)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockID is the pg_advisory_lock key held while migrating so that
+// replicas started together apply each migration exactly once.
+const migrationLockID int64 = 7_146_518_020_417
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is one numbered pair of up/down SQL files.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus describes the schema_migrations row and the migrations that
+// have not been applied yet.
+type MigrationStatus struct {
+	Version uint64
+	Dirty   bool
+	Applied []Migration
+	Pending []Migration
+}
+
+// Migrator applies SQL migrations using the same schema_migrations table as
+// golang-migrate, so databases migrated with the CLI keep working.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+func NewMigrator(pool *pgxpool.Pool, source fs.FS) (*Migrator, error) {
+	if pool == nil {
+		return nil, errors.New("postgres: pool is nil")
+	}
+	migrations, err := LoadMigrations(source)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// LoadMigrations reads "<version>_<name>.(up|down).sql" files from the root of
+// source, ordered by version. Every version needs an up file.
+func LoadMigrations(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("postgres: read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("postgres: invalid migration version in %s", entry.Name())
+		}
+		body, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("postgres: read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("postgres: migration %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("postgres: migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies up to steps pending migrations, or all of them when steps <= 0.
+func (m *Migrator) Up(ctx context.Context, steps int) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.pending(version) {
+			if steps > 0 && len(applied) >= steps {
+				break
+			}
+			if err := m.apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("postgres: migration %d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back up to steps applied migrations, newest first. steps <= 0
+// rolls back a single migration.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		applied := m.applied(version)
+		for i := len(applied) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := applied[i]
+			if migration.Down == "" {
+				return fmt.Errorf("postgres: migration %d_%s has no down file", migration.Version, migration.Name)
+			}
+			var previous uint64
+			if i > 0 {
+				previous = applied[i-1].Version
+			}
+			if err := m.apply(ctx, conn, migration.Down, previous); err != nil {
+				return fmt.Errorf("postgres: migration %d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+func (m *Migrator) Status(ctx context.Context) (MigrationStatus, error) {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	defer conn.Release()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return MigrationStatus{}, err
+	}
+	version, dirty, err := readMigrationVersion(ctx, conn)
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+	return MigrationStatus{
+		Version: version,
+		Dirty:   dirty,
+		Applied: m.applied(version),
+		Pending: m.pending(version),
+	}, nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	// Session-level advisory locks belong to the connection, so the lock,
+	// the migrations and the unlock all have to run on this conn.
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("postgres: acquire migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+	}()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) currentVersion(ctx context.Context, conn *pgxpool.Conn) (uint64, error) {
+	version, dirty, err := readMigrationVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("postgres: database is dirty at version %d; fix it manually and force the version", version)
+	}
+	return version, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, sql string, version uint64) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// Exec without arguments uses the simple protocol, which accepts the
+	// multi-statement files written for golang-migrate.
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return err
+	}
+	if version > 0 {
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, int64(version)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (m *Migrator) applied(version uint64) []Migration {
+	var result []Migration
+	for _, migration := range m.migrations {
+		if migration.Version <= version {
+			result = append(result, migration)
+		}
+	}
+	return result
+}
+
+func (m *Migrator) pending(version uint64) []Migration {
+	var result []Migration
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			result = append(result, migration)
+		}
+	}
+	return result
+}
+
+func ensureMigrationsTable(ctx context.Context, conn *pgxpool.Conn) error {
+	const query = `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version bigint NOT NULL PRIMARY KEY,
+			dirty boolean NOT NULL
+		)
+	`
+	_, err := conn.Exec(ctx, query)
+	return err
+}
+
+func readMigrationVersion(ctx context.Context, conn *pgxpool.Conn) (uint64, bool, error) {
+	var version int64
+	var dirty bool
+	err := conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if version < 0 {
+		return 0, dirty, nil
+	}
+	return uint64(version), dirty, nil
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/mzulfanw/boilerplate-go-fiber/migrations"
+)
+
+func TestLoadMigrationsOrdersByVersion(t *testing.T) {
+	source := fstest.MapFS{
+		"000010_second.up.sql":   {Data: []byte("SELECT 10;")},
+		"000010_second.down.sql": {Data: []byte("SELECT -10;")},
+		"000002_first.up.sql":    {Data: []byte("SELECT 2;")},
+		"README.md":              {Data: []byte("ignored")},
+	}
+
+	loaded, err := LoadMigrations(source)
+	if err != nil {
+		t.Fatalf("expected migrations, got error: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(loaded))
+	}
+	if loaded[0].Version != 2 || loaded[0].Name != "first" || loaded[0].Down != "" {
+		t.Fatalf("unexpected first migration: %+v", loaded[0])
+	}
+	if loaded[1].Version != 10 || loaded[1].Up != "SELECT 10;" || loaded[1].Down != "SELECT -10;" {
+		t.Fatalf("unexpected second migration: %+v", loaded[1])
+	}
+}
+
+func TestLoadMigrationsRequiresUpFile(t *testing.T) {
+	source := fstest.MapFS{
+		"000001_only_down.down.sql": {Data: []byte("SELECT 1;")},
+	}
+	if _, err := LoadMigrations(source); err == nil {
+		t.Fatal("expected error for migration without up file")
+	}
+}
+
+func TestPendingAndApplied(t *testing.T) {
+	m := &Migrator{migrations: []Migration{{Version: 1}, {Version: 2}, {Version: 5}}}
+
+	if got := m.applied(2); len(got) != 2 || got[1].Version != 2 {
+		t.Fatalf("unexpected applied migrations: %+v", got)
+	}
+	if got := m.pending(2); len(got) != 1 || got[0].Version != 5 {
+		t.Fatalf("unexpected pending migrations: %+v", got)
+	}
+	if got := m.pending(0); len(got) != 3 {
+		t.Fatalf("expected all migrations pending, got %d", len(got))
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("expected embedded migrations, got error: %v", err)
+	}
+	if len(loaded) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+	for _, migration := range loaded {
+		if migration.Down == "" {
+			t.Fatalf("migration %d_%s has no down file", migration.Version, migration.Name)
+		}
+	}
+}
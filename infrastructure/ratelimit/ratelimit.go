@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	redisinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/redis"
+)
+
+const defaultPrefix = "ratelimit:"
+
+// slidingWindowScript keeps one sorted-set member per accepted request,
+// scored by Redis server time in milliseconds, so every replica shares the
+// same window and clock. It returns {allowed, count, reset_ms}, where reset_ms
+// is how long until the oldest request leaves the window.
+const slidingWindowScript = `
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local member = ARGV[3]
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, now .. '-' .. member)
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local reset = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, count, reset}
+`
+
+var ErrInvalidResult = errors.New("ratelimit: unexpected script result")
+
+// Result describes the state of a window after a call to Allow.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is how long until the oldest request in the window expires
+	// and another one is accepted.
+	ResetAfter time.Duration
+}
+
+// Limiter is a sliding-window rate limiter backed by Redis.
+type Limiter struct {
+	cache  redisinfra.Cache
+	prefix string
+}
+
+func New(cache redisinfra.Cache, prefix string) (*Limiter, error) {
+	if cache == nil {
+		return nil, errors.New("ratelimit: cache is nil")
+	}
+	if strings.TrimSpace(prefix) == "" {
+		prefix = defaultPrefix
+	}
+	return &Limiter{cache: cache, prefix: prefix}, nil
+}
+
+// Allow records a request for key and reports whether it fits within limit
+// requests per window. Rejected requests are not counted.
+func (l *Limiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	if l == nil || l.cache == nil {
+		return Result{}, redisinfra.ErrNilClient
+	}
+	if strings.TrimSpace(key) == "" {
+		return Result{}, redisinfra.ErrEmptyKey
+	}
+	if limit <= 0 || window < time.Millisecond {
+		return Result{}, fmt.Errorf("ratelimit: invalid limit %d per %s", limit, window)
+	}
+
+	member, err := randomMember()
+	if err != nil {
+		return Result{}, err
+	}
+	raw, err := l.cache.Eval(ctx, slidingWindowScript, []string{l.prefix + key}, limit, window.Milliseconds(), member)
+	if err != nil {
+		return Result{}, err
+	}
+	return parseResult(raw, limit)
+}
+
+func parseResult(raw interface{}, limit int) (Result, error) {
+	values, ok := raw.([]interface{})
+	if !ok || len(values) != 3 {
+		return Result{}, ErrInvalidResult
+	}
+	nums := make([]int64, len(values))
+	for i, value := range values {
+		n, ok := value.(int64)
+		if !ok {
+			return Result{}, ErrInvalidResult
+		}
+		nums[i] = n
+	}
+
+	remaining := limit - int(nums[1])
+	if remaining < 0 {
+		remaining = 0
+	}
+	reset := time.Duration(nums[2]) * time.Millisecond
+	if reset < 0 {
+		reset = 0
+	}
+	return Result{
+		Allowed:    nums[0] == 1,
+		Limit:      limit,
+		Remaining:  remaining,
+		ResetAfter: reset,
+	}, nil
+}
+
+func randomMember() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
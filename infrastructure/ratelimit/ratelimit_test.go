@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	redisinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/redis"
+)
+
+type fakeCache struct {
+	redisinfra.Cache
+	result interface{}
+	keys   []string
+	args   []interface{}
+}
+
+func (f *fakeCache) Eval(_ context.Context, _ string, keys []string, args ...interface{}) (interface{}, error) {
+	f.keys = keys
+	f.args = args
+	return f.result, nil
+}
+
+func TestAllowParsesScriptResult(t *testing.T) {
+	cache := &fakeCache{result: []interface{}{int64(1), int64(3), int64(1500)}}
+	limiter, err := New(cache, "rl:")
+	if err != nil {
+		t.Fatalf("expected limiter, got error: %v", err)
+	}
+
+	result, err := limiter.Allow(context.Background(), "ip:10.0.0.1", 5, time.Minute)
+	if err != nil {
+		t.Fatalf("expected result, got error: %v", err)
+	}
+	if !result.Allowed || result.Limit != 5 || result.Remaining != 2 || result.ResetAfter != 1500*time.Millisecond {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(cache.keys) != 1 || cache.keys[0] != "rl:ip:10.0.0.1" {
+		t.Fatalf("unexpected keys: %v", cache.keys)
+	}
+	if cache.args[0] != 5 || cache.args[1] != int64(60000) {
+		t.Fatalf("unexpected args: %v", cache.args)
+	}
+}
+
+func TestAllowRejected(t *testing.T) {
+	cache := &fakeCache{result: []interface{}{int64(0), int64(5), int64(42000)}}
+	limiter, _ := New(cache, "")
+
+	result, err := limiter.Allow(context.Background(), "user:1", 5, time.Minute)
+	if err != nil {
+		t.Fatalf("expected result, got error: %v", err)
+	}
+	if result.Allowed || result.Remaining != 0 || result.ResetAfter != 42*time.Second {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if cache.keys[0] != defaultPrefix+"user:1" {
+		t.Fatalf("expected default prefix, got %q", cache.keys[0])
+	}
+}
+
+func TestAllowRejectsInvalidInput(t *testing.T) {
+	limiter, _ := New(&fakeCache{}, "")
+	if _, err := limiter.Allow(context.Background(), "", 5, time.Minute); err == nil {
+		t.Fatal("expected error for empty key")
+	}
+	if _, err := limiter.Allow(context.Background(), "k", 0, time.Minute); err == nil {
+		t.Fatal("expected error for zero limit")
+	}
+}
+
+func TestAllowInvalidScriptResult(t *testing.T) {
+	limiter, _ := New(&fakeCache{result: "OK"}, "")
+	if _, err := limiter.Allow(context.Background(), "k", 5, time.Minute); err != ErrInvalidResult {
+		t.Fatalf("expected ErrInvalidResult, got %v", err)
+	}
+}
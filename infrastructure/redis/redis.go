@@ -140,6 +140,13 @@ func (c *Client) LPush(ctx context.Context, key string, values ...interface{}) (
 	return c.client.LPush(ensureContext(ctx), key, values...).Result()
 }
 
+func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
+	if err := c.validateKey(key); err != nil {
+		return 0, err
+	}
+	return c.client.LLen(ensureContext(ctx), key).Result()
+}
+
 func (c *Client) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
 	if err := c.validateKey(key); err != nil {
 		return nil, err
@@ -191,6 +198,13 @@ func (c *Client) ZAdd(ctx context.Context, key string, members ...ZMember) (int6
 	return c.client.ZAdd(ensureContext(ctx), key, items...).Result()
 }
 
+func (c *Client) ZCard(ctx context.Context, key string) (int64, error) {
+	if err := c.validateKey(key); err != nil {
+		return 0, err
+	}
+	return c.client.ZCard(ensureContext(ctx), key).Result()
+}
+
 func (c *Client) ZRangeByScore(ctx context.Context, key, min, max string, count int64) ([]string, error) {
 	if err := c.validateKey(key); err != nil {
 		return nil, err
@@ -239,6 +253,14 @@ func (c *Client) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ensureContext(ctx), key).Err()
 }
 
+// PoolStats reports connection pool usage for metrics.
+func (c *Client) PoolStats() *goredis.PoolStats {
+	if c == nil || c.client == nil {
+		return nil
+	}
+	return c.client.PoolStats()
+}
+
 func (c *Client) Close() error {
 	if c == nil || c.client == nil {
 		return nil
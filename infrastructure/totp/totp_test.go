@@ -0,0 +1,65 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// Test vectors from RFC 6238 Appendix B (SHA1).
+func TestHOTPRFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tc := range cases {
+		got := hotp(key, uint64(Step(time.Unix(tc.unix, 0))), 8)
+		if got != tc.want {
+			t.Fatalf("unix %d: expected %s, got %s", tc.unix, tc.want, got)
+		}
+	}
+}
+
+func TestValidateWithSkew(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	previous, err := Code(secret, now.Add(-Period))
+	if err != nil {
+		t.Fatalf("expected code, got error: %v", err)
+	}
+
+	step, err := Validate(secret, previous, now, 1)
+	if err != nil {
+		t.Fatalf("expected previous step to validate, got error: %v", err)
+	}
+	if step != Step(now)-1 {
+		t.Fatalf("expected step %d, got %d", Step(now)-1, step)
+	}
+
+	if _, err := Validate(secret, previous, now, 0); err == nil {
+		t.Fatal("expected error without skew")
+	}
+	if _, err := Validate(secret, "000000", now, 1); err == nil {
+		t.Fatal("expected error for wrong code")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("expected secret, got error: %v", err)
+	}
+	if _, err := Code(secret, time.Now()); err != nil {
+		t.Fatalf("expected generated secret to be usable, got error: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package xendit
+
+import (
+	"context"
+	"time"
+
+	"github.com/xendit/xendit-go/v7/invoice"
+)
+
+// CallObserver receives the latency and result of every Xendit API call.
+type CallObserver interface {
+	ObserveXenditCall(operation string, duration time.Duration, err error)
+}
+
+type instrumentedClient struct {
+	next     XenditClient
+	observer CallObserver
+}
+
+var _ XenditClient = (*instrumentedClient)(nil)
+
+// NewInstrumented wraps next so each call is reported to observer.
+func NewInstrumented(next XenditClient, observer CallObserver) XenditClient {
+	if observer == nil {
+		return next
+	}
+	return &instrumentedClient{next: next, observer: observer}
+}
+
+func (c *instrumentedClient) BalanceInquiry(ctx context.Context) (float64, error) {
+	start := time.Now()
+	balance, err := c.next.BalanceInquiry(ctx)
+	c.observer.ObserveXenditCall("balance_inquiry", time.Since(start), err)
+	return balance, err
+}
+
+func (c *instrumentedClient) GetInvoiceById(ctx context.Context, invoiceID string) (invoice.Invoice, error) {
+	start := time.Now()
+	result, err := c.next.GetInvoiceById(ctx, invoiceID)
+	c.observer.ObserveXenditCall("get_invoice", time.Since(start), err)
+	return result, err
+}
+
+func (c *instrumentedClient) CreateInvoice(ctx context.Context, req invoice.CreateInvoiceRequest) (invoice.Invoice, error) {
+	start := time.Now()
+	result, err := c.next.CreateInvoice(ctx, req)
+	c.observer.ObserveXenditCall("create_invoice", time.Since(start), err)
+	return result, err
+}
+
+func (c *instrumentedClient) ExpireInvoice(ctx context.Context, invoiceID string) (invoice.Invoice, error) {
+	start := time.Now()
+	result, err := c.next.ExpireInvoice(ctx, invoiceID)
+	c.observer.ObserveXenditCall("expire_invoice", time.Since(start), err)
+	return result, err
+}
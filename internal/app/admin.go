@@ -0,0 +1,218 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	jwtinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/jwt"
+	passwordinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/password"
+	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/postgres"
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+	userdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/user"
+	postgresrepo "github.com/mzulfanw/boilerplate-go-fiber/internal/repository/postgres"
+	rbacservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/rbac"
+	userservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/user"
+	"github.com/mzulfanw/boilerplate-go-fiber/migrations"
+	"github.com/sirupsen/logrus"
+)
+
+const adminRoleName = rbacdomain.AdminRole
+
+// Admin runs the one-off maintenance commands exposed by cmd/main.go. It only
+// needs Postgres, so it can run before Redis or Xendit are reachable.
+type Admin struct {
+	db          *postgres.Client
+	migrator    *postgres.Migrator
+	rbacService *rbacservice.Service
+	userService *userservice.Service
+	cfg         config.Config
+}
+
+// SeedResult reports what Seed changed.
+type SeedResult struct {
+	GrantedPermissions int64
+	AdminCreated       bool
+}
+
+func NewAdmin(cfg config.Config) (*Admin, error) {
+	db, err := postgres.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := postgres.NewMigrator(db.Pool(), migrations.FS)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	rbacService, err := rbacservice.NewService(postgresrepo.NewRBACRepository(db.Pool()))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	passwordHasher, err := passwordinfra.NewFromConfig(cfg)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	passwordPolicy, err := passwordinfra.NewPolicyFromConfig(cfg, passwordHasher)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	userService, err := userservice.NewService(postgresrepo.NewUserRepository(db.Pool()), passwordHasher)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	userService.SetPasswordPolicy(passwordPolicy)
+
+	return &Admin{
+		db:          db,
+		migrator:    migrator,
+		rbacService: rbacService,
+		userService: userService,
+		cfg:         cfg,
+	}, nil
+}
+
+func (a *Admin) Close() {
+	if a == nil {
+		return
+	}
+	a.db.Close()
+}
+
+func (a *Admin) MigrateUp(ctx context.Context, steps int) ([]postgres.Migration, error) {
+	return a.migrator.Up(ctx, steps)
+}
+
+func (a *Admin) MigrateDown(ctx context.Context, steps int) ([]postgres.Migration, error) {
+	return a.migrator.Down(ctx, steps)
+}
+
+func (a *Admin) MigrationStatus(ctx context.Context) (postgres.MigrationStatus, error) {
+	return a.migrator.Status(ctx)
+}
+
+// Seed makes sure the admin role holds every permission and, when
+// SEED_ADMIN_EMAIL is set, that the configured admin account exists. It is
+// safe to run repeatedly.
+func (a *Admin) Seed(ctx context.Context) (SeedResult, error) {
+	role, err := a.rbacService.EnsureRole(ctx, adminRoleName, "Full access")
+	if err != nil {
+		return SeedResult{}, err
+	}
+	granted, err := a.rbacService.GrantAllPermissions(ctx, role.ID)
+	if err != nil {
+		return SeedResult{}, err
+	}
+	result := SeedResult{GrantedPermissions: granted}
+
+	email := strings.TrimSpace(a.cfg.SeedAdminEmail)
+	if email == "" {
+		return result, nil
+	}
+	_, err = a.userService.CreateUser(ctx, email, a.cfg.SeedAdminPassword, nil, []string{role.ID})
+	switch {
+	case errors.Is(err, userdomain.ErrConflict):
+		return result, nil
+	case err != nil:
+		return SeedResult{}, err
+	}
+	result.AdminCreated = true
+	return result, nil
+}
+
+// CreateAdmin creates an active user holding the admin role. When password is
+// empty a random one is generated and returned so the caller can show it once.
+func (a *Admin) CreateAdmin(ctx context.Context, email, password string) (userdomain.User, string, error) {
+	if strings.TrimSpace(email) == "" {
+		return userdomain.User{}, "", userdomain.ErrInvalidInput
+	}
+	if password == "" {
+		generated, err := generatePassword()
+		if err != nil {
+			return userdomain.User{}, "", err
+		}
+		password = generated
+	}
+
+	role, err := a.rbacService.GetRoleByName(ctx, adminRoleName)
+	if err != nil {
+		return userdomain.User{}, "", fmt.Errorf("lookup %s role: %w", adminRoleName, err)
+	}
+	user, err := a.userService.CreateUser(ctx, email, password, nil, []string{role.ID})
+	if err != nil {
+		return userdomain.User{}, "", err
+	}
+	return user, password, nil
+}
+
+// ExportPolicy returns every role and permission with their grants and
+// parents.
+func (a *Admin) ExportPolicy(ctx context.Context) (rbacdomain.Policy, error) {
+	return a.rbacService.ExportPolicy(ctx)
+}
+
+// ImportPolicy makes roles and permissions match policy, or only reports the
+// changes when opts.DryRun is set.
+func (a *Admin) ImportPolicy(ctx context.Context, policy rbacdomain.Policy, opts rbacservice.PolicyImportOptions) (rbacservice.PolicyImportResult, error) {
+	return a.rbacService.ImportPolicy(ctx, policy, opts)
+}
+
+// RotateJWTKey writes a new signing key to JWT_KEYS_DIR. Its timestamp kid
+// sorts last, so replicas sign with it after their next restart unless
+// JWT_ACTIVE_KID pins another key.
+func RotateJWTKey(cfg config.Config) (string, string, error) {
+	if cfg.JWTSigningAlg == jwtinfra.AlgHS256 {
+		return "", "", errors.New("rotate-jwt-key requires JWT_SIGNING_ALG to be RS256, ES256 or EdDSA")
+	}
+	key, err := jwtinfra.GenerateKey(cfg.JWTSigningAlg)
+	if err != nil {
+		return "", "", err
+	}
+	kid := jwtinfra.NewKeyID(time.Now())
+	path, err := jwtinfra.WriteKey(cfg.JWTKeysDir, kid, key)
+	if err != nil {
+		return "", "", err
+	}
+	if active := strings.TrimSpace(cfg.JWTActiveKeyID); active != "" {
+		logrus.WithField("kid", active).Warn("JWT_ACTIVE_KID is set; update it to sign with the new key")
+	}
+	return kid, path, nil
+}
+
+func migrateOnStart(db *postgres.Client) error {
+	migrator, err := postgres.NewMigrator(db.Pool(), migrations.FS)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	applied, err := migrator.Up(ctx, 0)
+	if err != nil {
+		return err
+	}
+	for _, migration := range applied {
+		logrus.WithFields(logrus.Fields{
+			"version": migration.Version,
+			"name":    migration.Name,
+		}).Info("migration applied")
+	}
+	return nil
+}
+
+func generatePassword() (string, error) {
+	buf := make([]byte, 18)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
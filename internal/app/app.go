@@ -5,12 +5,16 @@ import (
 	"time"
 
 	emailinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/email"
+	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/metrics"
 	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/postgres"
 	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/redis"
 	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/xendit"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
+	postgresrepo "github.com/mzulfanw/boilerplate-go-fiber/internal/repository/postgres"
 	authservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/auth"
 	emailservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/email"
+	outboxservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/outbox"
+	userservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/user"
 	httptransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http"
 	"github.com/sirupsen/logrus"
 )
@@ -22,9 +26,12 @@ type App struct {
 	db              postgres.DB
 	xenditClient    xendit.XenditClient
 	authService     *authservice.Service
+	userService     *userservice.Service
 	emailWorker     *emailservice.Worker
+	outboxRelay     *outboxservice.Relay
 
 	refreshTokenCleanupInterval time.Duration
+	roleExpiryInterval          time.Duration
 }
 
 func NewApp(cfg config.Config) (*App, error) {
@@ -37,16 +44,35 @@ func NewApp(cfg config.Config) (*App, error) {
 		_ = cache.Close()
 		return nil, err
 	}
-	xenditClient, err := xendit.New(cfg)
+	if cfg.MigrateOnStart {
+		if err := migrateOnStart(db); err != nil {
+			_ = cache.Close()
+			db.Close()
+			return nil, err
+		}
+	}
+	xenditBase, err := xendit.New(cfg)
 	if err != nil {
 		_ = cache.Close()
 		db.Close()
 		return nil, err
 	}
 
+	var xenditClient xendit.XenditClient = xenditBase
 	emailQueue := emailservice.NewRedisQueue(cache, emailservice.QueueOptions{
 		Prefix: "email:queue",
 	})
+
+	var metricsRegistry *metrics.Registry
+	if cfg.MetricsEnabled {
+		metricsRegistry, err = newMetricsRegistry(db, cache, emailQueue)
+		if err != nil {
+			_ = cache.Close()
+			db.Close()
+			return nil, err
+		}
+		xenditClient = xendit.NewInstrumented(xenditClient, metricsRegistry)
+	}
 	emailSender, err := emailinfra.NewSMTP(cfg)
 	if err != nil {
 		_ = cache.Close()
@@ -65,13 +91,34 @@ func NewApp(cfg config.Config) (*App, error) {
 		})
 	}
 
+	var outboxRelay *outboxservice.Relay
+	if cfg.OutboxRelayEnabled {
+		outboxRelay, err = outboxservice.NewRelay(postgresrepo.NewOutboxRepository(db.Pool()), []outboxservice.Sink{outboxservice.LogSink{}}, outboxservice.RelayOptions{
+			PollInterval: cfg.OutboxPollInterval,
+			BatchSize:    cfg.OutboxBatchSize,
+			Retention:    cfg.OutboxRetention,
+		})
+		if err != nil {
+			_ = cache.Close()
+			db.Close()
+			return nil, err
+		}
+	}
+
 	registry, err := httpRouters(cfg, db, xenditClient, cache, emailService, emailRenderer)
 	if err != nil {
 		_ = cache.Close()
 		db.Close()
 		return nil, err
 	}
-	httpServer := httptransport.NewServer(cfg, registry.Routers...)
+	httpServer := httptransport.NewServer(cfg, metricsRegistry, registry.Routers...)
+	if cfg.PermissionCatalogSync {
+		if err := syncPermissionCatalog(registry); err != nil {
+			_ = cache.Close()
+			db.Close()
+			return nil, err
+		}
+	}
 
 	return &App{
 		httpServer:                  httpServer,
@@ -80,18 +127,66 @@ func NewApp(cfg config.Config) (*App, error) {
 		db:                          db,
 		xenditClient:                xenditClient,
 		authService:                 registry.AuthService,
+		userService:                 registry.UserService,
 		emailWorker:                 emailWorker,
+		outboxRelay:                 outboxRelay,
 		refreshTokenCleanupInterval: cfg.RefreshTokenCleanupInterval,
+		roleExpiryInterval:          cfg.RoleExpiryInterval,
 	}, nil
 }
 
+func newMetricsRegistry(db *postgres.Client, cache *redis.Client, emailQueue *emailservice.RedisQueue) (*metrics.Registry, error) {
+	registry := metrics.New()
+	err := registry.Register(
+		metrics.NewPgxPoolCollector(db.Pool()),
+		metrics.NewRedisPoolCollector(cache.PoolStats),
+		metrics.NewQueueCollector("email", func(ctx context.Context) (map[string]int64, error) {
+			depths, err := emailQueue.Depths(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]int64{
+				"pending":    depths.Pending,
+				"processing": depths.Processing,
+				"retry":      depths.Retry,
+				"dead":       depths.Dead,
+			}, nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// syncPermissionCatalog stores the permissions the registered routes require.
+// Routers declare them while registering, so it must run after NewServer.
+func syncPermissionCatalog(registry httpRegistry) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	created, orphaned, err := registry.RBACService.SyncCatalog(ctx, registry.PermissionCatalog.Permissions())
+	if err != nil {
+		return err
+	}
+	for _, name := range created {
+		logrus.WithField("permission", name).Info("permission registered")
+	}
+	if len(orphaned) > 0 {
+		logrus.WithField("permissions", orphaned).Warn("permissions not required by any route")
+	}
+	return nil
+}
+
 func (a *App) Run(ctx context.Context) error {
 	defer a.closeResources()
 
 	errChan := make(chan error, 1)
 
 	a.startRefreshTokenCleanup(ctx)
+	a.startRoleExpiry(ctx)
 	a.startEmailWorker(ctx)
+	a.startOutboxRelay(ctx)
 
 	go func() {
 		errChan <- a.httpServer.Start()
@@ -141,6 +236,40 @@ func (a *App) startRefreshTokenCleanup(ctx context.Context) {
 	}()
 }
 
+// startRoleExpiry removes lapsed time-bound role assignments. Permission
+// lookups already ignore them; this also bumps token_version so access tokens
+// issued while the grant was active stop working.
+func (a *App) startRoleExpiry(ctx context.Context) {
+	if a == nil || a.userService == nil || a.roleExpiryInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(a.roleExpiryInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if ctx.Err() != nil {
+					return
+				}
+				expiryCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+				expired, err := a.userService.ExpireUserRoles(expiryCtx)
+				cancel()
+				if err != nil {
+					logrus.WithError(err).Warn("role expiry failed")
+					continue
+				}
+				if expired > 0 {
+					logrus.WithField("expired", expired).Info("role expiry completed")
+				}
+			}
+		}
+	}()
+}
+
 func (a *App) startEmailWorker(ctx context.Context) {
 	if a == nil || a.emailWorker == nil {
 		return
@@ -148,6 +277,13 @@ func (a *App) startEmailWorker(ctx context.Context) {
 	go a.emailWorker.Run(ctx)
 }
 
+func (a *App) startOutboxRelay(ctx context.Context) {
+	if a == nil || a.outboxRelay == nil {
+		return
+	}
+	go a.outboxRelay.Run(ctx)
+}
+
 func (a *App) closeResources() {
 	if a.cache == nil {
 	} else if err := a.cache.Close(); err != nil {
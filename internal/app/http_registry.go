@@ -5,17 +5,22 @@ import (
 	"errors"
 
 	jwtinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/jwt"
+	oidcinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/oidc"
+	passwordinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/password"
 	pgdb "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/postgres"
+	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/ratelimit"
 	redisinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/redis"
 	xenditinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/xendit"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
 	postgresrepo "github.com/mzulfanw/boilerplate-go-fiber/internal/repository/postgres"
+	auditservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/audit"
 	authservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/auth"
 	emailservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/email"
 	paymentservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/payment"
 	rbacservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/rbac"
 	userservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/user"
 	httptransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http"
+	audittransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/audit"
 	authtransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/auth"
 	docstransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/docs"
 	healthtransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/health"
@@ -25,8 +30,11 @@ import (
 )
 
 type httpRegistry struct {
-	Routers     []httptransport.Router
-	AuthService *authservice.Service
+	Routers           []httptransport.Router
+	AuthService       *authservice.Service
+	UserService       *userservice.Service
+	RBACService       *rbacservice.Service
+	PermissionCatalog *httptransport.PermissionCatalog
 }
 
 func httpRouters(cfg config.Config, db pgdb.DB, xenditClient xenditinfra.XenditClient, cache redisinfra.Cache, emailService *emailservice.Service, emailRenderer *emailservice.Renderer) (httpRegistry, error) {
@@ -37,36 +45,89 @@ func httpRouters(cfg config.Config, db pgdb.DB, xenditClient xenditinfra.XenditC
 		return httpRegistry{}, errors.New("xendit client is nil")
 	}
 
+	auditRepo := postgresrepo.NewAuditRepository(db.Pool())
+	auditService, err := auditservice.NewService(auditRepo)
+	if err != nil {
+		return httpRegistry{}, err
+	}
+	auditHandler := audittransport.NewHandler(auditService)
+
 	authRepo := postgresrepo.NewAuthRepository(db.Pool())
-	tokenManager, err := jwtinfra.NewManager(cfg.JWTSecret, cfg.JWTIssuer, cfg.AccessTokenTTL)
+	tokenManager, err := jwtinfra.NewManagerFromConfig(cfg)
 	if err != nil {
 		return httpRegistry{}, err
 	}
-	authService, err := authservice.NewServiceWithCache(cfg, authRepo, tokenManager, cache)
+	passwordHasher, err := passwordinfra.NewFromConfig(cfg)
 	if err != nil {
 		return httpRegistry{}, err
 	}
-	authHandler := authtransport.NewHandler(authService, emailService, emailRenderer, cfg.AuthPasswordResetURL, cfg.AppName)
+	passwordPolicy, err := passwordinfra.NewPolicyFromConfig(cfg, passwordHasher)
+	if err != nil {
+		return httpRegistry{}, err
+	}
+	authService, err := authservice.NewServiceWithCache(cfg, authRepo, tokenManager, passwordHasher, cache)
+	if err != nil {
+		return httpRegistry{}, err
+	}
+	authService.SetAuditRecorder(auditService)
+	authService.SetPasswordPolicy(passwordPolicy)
+	for _, providerCfg := range cfg.OAuthProviders {
+		provider, err := oidcinfra.NewProvider(oidcinfra.Config{
+			Name:         providerCfg.Name,
+			Issuer:       providerCfg.Issuer,
+			ClientID:     providerCfg.ClientID,
+			ClientSecret: providerCfg.ClientSecret,
+			RedirectURL:  providerCfg.RedirectURL,
+			Scopes:       providerCfg.Scopes,
+		})
+		if err != nil {
+			return httpRegistry{}, err
+		}
+		authService.RegisterIdentityProvider(providerCfg.Name, provider)
+	}
+	authHandler := authtransport.NewHandler(authService, emailService, emailRenderer, cfg.AuthPasswordResetURL, cfg.AuthEmailVerificationURL, cfg.AppName)
 	authMiddleware := httptransport.NewAuthMiddleware(authService)
+	permissionCatalog := httptransport.NewPermissionCatalog()
+	authMiddleware.SetPermissionCatalog(permissionCatalog)
+
+	limiter, err := ratelimit.New(cache, "")
+	if err != nil {
+		return httpRegistry{}, err
+	}
+	rateLimiter := httptransport.NewRateLimiter(limiter)
+	authMiddleware.SetRateLimit(rateLimiter, httptransport.RateLimitPolicy{
+		Name:   "principal",
+		Limit:  cfg.RateLimitUserRequests,
+		Window: cfg.RateLimitUserWindow,
+		Key:    httptransport.RateLimitByPrincipal,
+	})
 
 	rbacRepo := postgresrepo.NewRBACRepository(db.Pool())
 	rbacService, err := rbacservice.NewService(rbacRepo)
 	if err != nil {
 		return httpRegistry{}, err
 	}
-	rbacHandler := rbactransport.NewHandler(rbacService)
+	rbacService.SetAuditRecorder(auditService)
+	rbacService.SetRoleApproval(cfg.RoleApprovalEnabled)
+	rbacHandler := rbactransport.NewHandler(rbacService, permissionCatalog)
 
 	userRepo := postgresrepo.NewUserRepository(db.Pool())
-	userService, err := userservice.NewService(userRepo)
+	userService, err := userservice.NewService(userRepo, passwordHasher)
 	if err != nil {
 		return httpRegistry{}, err
 	}
-	userHandler := usertransport.NewHandler(userService)
+	userService.SetAuditRecorder(auditService)
+	userService.SetPasswordPolicy(passwordPolicy)
+	userService.SetRoleApproval(cfg.RoleApprovalEnabled)
+	userHandler := usertransport.NewHandler(userService, authService)
+	userHandler.SetEmailVerifier(authHandler)
 
-	paymentService, err := paymentservice.NewService(xenditClient, cache)
+	paymentRepo := postgresrepo.NewPaymentRepository(db.Pool())
+	paymentService, err := paymentservice.NewService(xenditClient, cache, paymentRepo)
 	if err != nil {
 		return httpRegistry{}, err
 	}
+	paymentService.SetAuditRecorder(auditService)
 	paymentHandler := paymenttransport.NewHandler(paymentService, cfg.XENDIT_WEBHOOK_TOKEN)
 
 	healthDependencies := []healthtransport.Dependency{
@@ -93,14 +154,24 @@ func httpRouters(cfg config.Config, db pgdb.DB, xenditClient xenditinfra.XenditC
 	routers := []httptransport.Router{
 		healthtransport.NewRouter(cfg, healthDependencies...),
 		docstransport.NewRouter(cfg),
-		authtransport.NewRouter(authHandler, cfg),
+		rateLimiter.Use(httptransport.RateLimitPolicy{
+			Name:   "ip",
+			Limit:  cfg.RateLimitRequests,
+			Window: cfg.RateLimitWindow,
+			Key:    httptransport.RateLimitByIP,
+		}),
+		authtransport.NewRouter(authHandler, authMiddleware, rateLimiter, cfg),
 		rbactransport.NewRouter(rbacHandler, authMiddleware),
 		usertransport.NewRouter(userHandler, authMiddleware),
-		paymenttransport.NewRouter(paymentHandler),
+		paymenttransport.NewRouter(paymentHandler, authMiddleware),
+		audittransport.NewRouter(auditHandler, authMiddleware),
 	}
 
 	return httpRegistry{
-		Routers:     routers,
-		AuthService: authService,
+		Routers:           routers,
+		AuthService:       authService,
+		UserService:       userService,
+		RBACService:       rbacService,
+		PermissionCatalog: permissionCatalog,
 	}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -58,18 +59,40 @@ type Config struct {
 	PostgresMaxConnIdleTime   time.Duration
 	PostgresHealthCheckPeriod time.Duration
 
+	MigrateOnStart        bool
+	PermissionCatalogSync bool
+	RoleApprovalEnabled   bool
+	SeedAdminEmail        string
+	SeedAdminPassword     string
+
 	JWTSecret       string
 	JWTIssuer       string
+	JWTSigningAlg   string
+	JWTKeysDir      string
+	JWTActiveKeyID  string
 	AccessTokenTTL  time.Duration
 	RefreshTokenTTL time.Duration
 
 	RefreshTokenCleanupInterval time.Duration
+	RoleExpiryInterval          time.Duration
+
+	OutboxRelayEnabled bool
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+	OutboxRetention    time.Duration
 
 	AuthMaxLoginAttempts int
 	AuthLockoutDuration  time.Duration
 	AuthLoginRateLimit   int
 	AuthLoginRateWindow  time.Duration
 
+	AuthLoginEmailRateLimit int
+
+	RateLimitRequests     int
+	RateLimitWindow       time.Duration
+	RateLimitUserRequests int
+	RateLimitUserWindow   time.Duration
+
 	MetricsEnabled bool
 	MetricsPath    string
 
@@ -97,11 +120,48 @@ type Config struct {
 	AuthPasswordResetCooldown time.Duration
 	AuthPasswordResetURL      string
 
+	AuthRequireEmailVerification  bool
+	AuthEmailVerificationTTL      time.Duration
+	AuthEmailVerificationCooldown time.Duration
+	AuthEmailVerificationURL      string
+
+	AuthMFAIssuer        string
+	AuthMFAEncryptionKey string
+	AuthMFAChallengeTTL  time.Duration
+	AuthMFAMaxAttempts   int
+
+	PasswordHashAlgorithm     string
+	PasswordArgon2Memory      int
+	PasswordArgon2Time        int
+	PasswordArgon2Parallelism int
+	PasswordBcryptCost        int
+
+	PasswordMinLength           int
+	PasswordMaxLength           int
+	PasswordMinCharacterClasses int
+	PasswordHistory             int
+	PasswordBreachedList        string
+
+	OAuthProviders   []OAuthProvider
+	OAuthStateTTL    time.Duration
+	OAuthAllowSignup bool
+
 	XENDIT_SECRET_KEY    string
 	XENDIT_PUBLIC_KEY    string
 	XENDIT_WEBHOOK_TOKEN string
 }
 
+// OAuthProvider is an OpenID Connect provider used for external login,
+// configured through OAUTH_<NAME>_* variables.
+type OAuthProvider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
 func Load() (Config, error) {
 	env := strings.TrimSpace(os.Getenv("APP_ENV"))
 	if env == "" {
@@ -141,8 +201,14 @@ func Load() (Config, error) {
 		PostgresDB:       getString("POSTGRES_DB", "postgres"),
 		PostgresSSLMode:  getString("POSTGRES_SSLMODE", "disable"),
 
-		JWTSecret: getString("JWT_SECRET", ""),
-		JWTIssuer: getString("JWT_ISSUER", "boilerplate-go-fiber"),
+		SeedAdminEmail:    getString("SEED_ADMIN_EMAIL", ""),
+		SeedAdminPassword: getString("SEED_ADMIN_PASSWORD", ""),
+
+		JWTSecret:      getString("JWT_SECRET", ""),
+		JWTIssuer:      getString("JWT_ISSUER", "boilerplate-go-fiber"),
+		JWTSigningAlg:  getString("JWT_SIGNING_ALG", "HS256"),
+		JWTKeysDir:     getString("JWT_KEYS_DIR", ""),
+		JWTActiveKeyID: getString("JWT_ACTIVE_KID", ""),
 
 		MetricsPath:          getString("METRICS_PATH", "/metrics"),
 		SwaggerPath:          getString("SWAGGER_PATH", "/docs"),
@@ -228,6 +294,15 @@ func Load() (Config, error) {
 	if cfg.PostgresHealthCheckPeriod, err = getDuration("POSTGRES_HEALTHCHECK_PERIOD", time.Minute); err != nil {
 		return Config{}, err
 	}
+	if cfg.MigrateOnStart, err = getBool("MIGRATE_ON_START", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.PermissionCatalogSync, err = getBool("PERMISSION_CATALOG_SYNC", true); err != nil {
+		return Config{}, err
+	}
+	if cfg.RoleApprovalEnabled, err = getBool("ROLE_APPROVAL_ENABLED", false); err != nil {
+		return Config{}, err
+	}
 	if cfg.AccessTokenTTL, err = getDuration("ACCESS_TOKEN_TTL", 15*time.Minute); err != nil {
 		return Config{}, err
 	}
@@ -237,6 +312,21 @@ func Load() (Config, error) {
 	if cfg.RefreshTokenCleanupInterval, err = getDuration("REFRESH_TOKEN_CLEANUP_INTERVAL", time.Hour); err != nil {
 		return Config{}, err
 	}
+	if cfg.RoleExpiryInterval, err = getDuration("ROLE_EXPIRY_INTERVAL", time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxRelayEnabled, err = getBool("OUTBOX_RELAY_ENABLED", true); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxPollInterval, err = getDuration("OUTBOX_POLL_INTERVAL", time.Second); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxBatchSize, err = getInt("OUTBOX_BATCH_SIZE", 100); err != nil {
+		return Config{}, err
+	}
+	if cfg.OutboxRetention, err = getDuration("OUTBOX_RETENTION", 168*time.Hour); err != nil {
+		return Config{}, err
+	}
 	if cfg.AuthLockoutDuration, err = getDuration("AUTH_LOCKOUT_DURATION", 15*time.Minute); err != nil {
 		return Config{}, err
 	}
@@ -249,6 +339,21 @@ func Load() (Config, error) {
 	if cfg.AuthLoginRateLimit, err = getInt("AUTH_LOGIN_RATE_LIMIT", 10); err != nil {
 		return Config{}, err
 	}
+	if cfg.AuthLoginEmailRateLimit, err = getInt("AUTH_LOGIN_EMAIL_RATE_LIMIT", 5); err != nil {
+		return Config{}, err
+	}
+	if cfg.RateLimitRequests, err = getInt("RATE_LIMIT_REQUESTS", 600); err != nil {
+		return Config{}, err
+	}
+	if cfg.RateLimitWindow, err = getDuration("RATE_LIMIT_WINDOW", time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.RateLimitUserRequests, err = getInt("RATE_LIMIT_USER_REQUESTS", 300); err != nil {
+		return Config{}, err
+	}
+	if cfg.RateLimitUserWindow, err = getDuration("RATE_LIMIT_USER_WINDOW", time.Minute); err != nil {
+		return Config{}, err
+	}
 	if cfg.MetricsEnabled, err = getBool("METRICS_ENABLED", true); err != nil {
 		return Config{}, err
 	}
@@ -289,6 +394,59 @@ func Load() (Config, error) {
 		return Config{}, err
 	}
 	cfg.AuthPasswordResetURL = getString("AUTH_PASSWORD_RESET_URL", "")
+	if cfg.AuthRequireEmailVerification, err = getBool("AUTH_REQUIRE_EMAIL_VERIFICATION", false); err != nil {
+		return Config{}, err
+	}
+	if cfg.AuthEmailVerificationTTL, err = getDuration("AUTH_EMAIL_VERIFICATION_TTL", 24*time.Hour); err != nil {
+		return Config{}, err
+	}
+	if cfg.AuthEmailVerificationCooldown, err = getDuration("AUTH_EMAIL_VERIFICATION_COOLDOWN", time.Minute); err != nil {
+		return Config{}, err
+	}
+	cfg.AuthEmailVerificationURL = getString("AUTH_EMAIL_VERIFICATION_URL", "")
+	cfg.AuthMFAIssuer = getString("AUTH_MFA_ISSUER", cfg.AppName)
+	cfg.AuthMFAEncryptionKey = getString("AUTH_MFA_ENCRYPTION_KEY", "")
+	if cfg.AuthMFAChallengeTTL, err = getDuration("AUTH_MFA_CHALLENGE_TTL", 5*time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.AuthMFAMaxAttempts, err = getInt("AUTH_MFA_MAX_ATTEMPTS", 5); err != nil {
+		return Config{}, err
+	}
+	cfg.PasswordHashAlgorithm = strings.ToLower(getString("PASSWORD_HASH_ALGORITHM", "argon2id"))
+	if cfg.PasswordArgon2Memory, err = getInt("PASSWORD_ARGON2_MEMORY", 65536); err != nil {
+		return Config{}, err
+	}
+	if cfg.PasswordArgon2Time, err = getInt("PASSWORD_ARGON2_TIME", 3); err != nil {
+		return Config{}, err
+	}
+	if cfg.PasswordArgon2Parallelism, err = getInt("PASSWORD_ARGON2_PARALLELISM", 2); err != nil {
+		return Config{}, err
+	}
+	if cfg.PasswordBcryptCost, err = getInt("PASSWORD_BCRYPT_COST", 12); err != nil {
+		return Config{}, err
+	}
+	if cfg.PasswordMinLength, err = getInt("PASSWORD_MIN_LENGTH", 8); err != nil {
+		return Config{}, err
+	}
+	if cfg.PasswordMaxLength, err = getInt("PASSWORD_MAX_LENGTH", 72); err != nil {
+		return Config{}, err
+	}
+	if cfg.PasswordMinCharacterClasses, err = getInt("PASSWORD_MIN_CHARACTER_CLASSES", 0); err != nil {
+		return Config{}, err
+	}
+	if cfg.PasswordHistory, err = getInt("PASSWORD_HISTORY", 5); err != nil {
+		return Config{}, err
+	}
+	cfg.PasswordBreachedList = getString("PASSWORD_BREACHED_LIST", "")
+	if cfg.OAuthProviders, err = loadOAuthProviders(getString("OAUTH_PROVIDERS", "")); err != nil {
+		return Config{}, err
+	}
+	if cfg.OAuthStateTTL, err = getDuration("OAUTH_STATE_TTL", 10*time.Minute); err != nil {
+		return Config{}, err
+	}
+	if cfg.OAuthAllowSignup, err = getBool("OAUTH_ALLOW_SIGNUP", true); err != nil {
+		return Config{}, err
+	}
 
 	if strings.TrimSpace(cfg.HTTPAddr) == "" {
 		return Config{}, fmt.Errorf("HTTP_ADDR is required")
@@ -296,8 +454,33 @@ func Load() (Config, error) {
 	if cfg.CORSAllowCredentials && strings.TrimSpace(cfg.CORSAllowOrigins) == "*" {
 		return Config{}, fmt.Errorf("CORS_ALLOW_CREDENTIALS=true requires CORS_ALLOW_ORIGINS not '*'")
 	}
-	if strings.TrimSpace(cfg.JWTSecret) == "" {
-		return Config{}, fmt.Errorf("JWT_SECRET is required")
+	switch cfg.JWTSigningAlg {
+	case "HS256":
+		if strings.TrimSpace(cfg.JWTSecret) == "" {
+			return Config{}, fmt.Errorf("JWT_SECRET is required")
+		}
+	case "RS256", "ES256", "EdDSA":
+		if strings.TrimSpace(cfg.JWTKeysDir) == "" {
+			return Config{}, fmt.Errorf("JWT_KEYS_DIR is required when JWT_SIGNING_ALG=%s", cfg.JWTSigningAlg)
+		}
+	default:
+		return Config{}, fmt.Errorf("JWT_SIGNING_ALG must be one of HS256, RS256, ES256, EdDSA")
+	}
+	switch cfg.PasswordHashAlgorithm {
+	case "argon2id", "bcrypt":
+	default:
+		return Config{}, fmt.Errorf("PASSWORD_HASH_ALGORITHM must be one of argon2id, bcrypt")
+	}
+	if cfg.PasswordHistory < 0 || cfg.PasswordHistory > 24 {
+		return Config{}, fmt.Errorf("PASSWORD_HISTORY must be between 0 and 24")
+	}
+	// TOTP secrets are sealed with this key, so it must not be tied to the
+	// JWT secret, which is meant to be rotated.
+	if strings.TrimSpace(cfg.AuthMFAEncryptionKey) == "" {
+		return Config{}, fmt.Errorf("AUTH_MFA_ENCRYPTION_KEY is required")
+	}
+	if strings.TrimSpace(cfg.SeedAdminEmail) != "" && strings.TrimSpace(cfg.SeedAdminPassword) == "" {
+		return Config{}, fmt.Errorf("SEED_ADMIN_PASSWORD is required when SEED_ADMIN_EMAIL is set")
 	}
 	if cfg.OTELSampleRatio < 0 || cfg.OTELSampleRatio > 1 {
 		return Config{}, fmt.Errorf("OTEL_SAMPLE_RATIO must be between 0 and 1")
@@ -313,6 +496,50 @@ func Load() (Config, error) {
 	return cfg, nil
 }
 
+// loadOAuthProviders reads OAUTH_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET,
+// _REDIRECT_URL and _SCOPES for each name in the comma-separated list.
+var oauthProviderName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+func loadOAuthProviders(list string) ([]OAuthProvider, error) {
+	var providers []OAuthProvider
+	seen := make(map[string]struct{})
+	for _, raw := range strings.Split(list, ",") {
+		name := strings.ToLower(strings.TrimSpace(raw))
+		if name == "" {
+			continue
+		}
+		if !oauthProviderName.MatchString(name) {
+			return nil, fmt.Errorf("OAUTH_PROVIDERS: invalid provider name %q", name)
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("OAUTH_PROVIDERS: duplicate provider %q", name)
+		}
+		seen[name] = struct{}{}
+
+		prefix := "OAUTH_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		provider := OAuthProvider{
+			Name:         name,
+			Issuer:       getString(prefix+"ISSUER", ""),
+			ClientID:     getString(prefix+"CLIENT_ID", ""),
+			ClientSecret: getString(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getString(prefix+"REDIRECT_URL", ""),
+			Scopes: strings.FieldsFunc(getString(prefix+"SCOPES", ""), func(r rune) bool {
+				return r == ',' || r == ' '
+			}),
+		}
+		switch {
+		case provider.Issuer == "":
+			return nil, fmt.Errorf("%sISSUER is required", prefix)
+		case provider.ClientID == "":
+			return nil, fmt.Errorf("%sCLIENT_ID is required", prefix)
+		case provider.RedirectURL == "":
+			return nil, fmt.Errorf("%sREDIRECT_URL is required", prefix)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
 func getString(key, fallback string) string {
 	value := strings.TrimSpace(os.Getenv(key))
 	if value == "" {
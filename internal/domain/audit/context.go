@@ -0,0 +1,30 @@
+package audit
+
+import "context"
+
+// Actor identifies who triggered a change. The HTTP layer stores it in the
+// request context so services can record it without extra parameters.
+type Actor struct {
+	UserID    string
+	APIKeyID  string
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+type actorContextKey struct{}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) Actor {
+	if ctx == nil {
+		return Actor{}
+	}
+	actor, _ := ctx.Value(actorContextKey{}).(Actor)
+	return actor
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+type Change struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
+
+// Diff compares the JSON forms of before and after and returns the fields
+// that differ. Either side may be nil for creations and deletions.
+func Diff(before, after any) (json.RawMessage, error) {
+	beforeFields, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]Change)
+	for key, value := range beforeFields {
+		next, ok := afterFields[key]
+		if !ok || !reflect.DeepEqual(value, next) {
+			changes[key] = Change{Before: value, After: next}
+		}
+	}
+	for key, value := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			changes[key] = Change{After: value}
+		}
+	}
+	return json.Marshal(changes)
+}
+
+func toFields(value any) (map[string]any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/domain/query"
+)
+
+const (
+	ActionUserCreate             = "user.create"
+	ActionUserUpdate             = "user.update"
+	ActionUserDelete             = "user.delete"
+	ActionUserRolesReplace       = "user.roles.replace"
+	ActionUserRoleGrant          = "user.role.grant"
+	ActionUserRoleExpire         = "user.role.expire"
+	ActionUserRoleRequest        = "user.role.request"
+	ActionUserRoleApprove        = "user.role.approve"
+	ActionUserRoleReject         = "user.role.reject"
+	ActionUserMFAReset           = "user.mfa.reset"
+	ActionResourceRoleGrant      = "user.resource_role.grant"
+	ActionResourceRoleRevoke     = "user.resource_role.revoke"
+	ActionRoleCreate             = "role.create"
+	ActionRoleUpdate             = "role.update"
+	ActionRoleDelete             = "role.delete"
+	ActionRolePermsReplace       = "role.permissions.replace"
+	ActionRoleParentsReplace     = "role.parents.replace"
+	ActionRoleConstraintsReplace = "role.constraints.replace"
+	ActionPermissionCreate       = "permission.create"
+	ActionPermissionUpdate       = "permission.update"
+	ActionPermissionDelete       = "permission.delete"
+	ActionPolicyImport           = "rbac.policy.import"
+	ActionPasswordReset          = "auth.password.reset"
+	ActionPasswordChange         = "auth.password.change"
+	ActionMFAEnable              = "auth.mfa.enable"
+	ActionMFADisable             = "auth.mfa.disable"
+	ActionAPIKeyCreate           = "auth.api_key.create"
+	ActionAPIKeyRevoke           = "auth.api_key.revoke"
+	ActionIdentityLink           = "auth.identity.link"
+	ActionInvoiceCreate          = "payment.invoice.create"
+	ActionInvoiceExpire          = "payment.invoice.expire"
+)
+
+const (
+	TargetUser       = "user"
+	TargetRole       = "role"
+	TargetPermission = "permission"
+	TargetPolicy     = "rbac_policy"
+	TargetAPIKey     = "api_key"
+	TargetInvoice    = "invoice"
+)
+
+// Entry is one audit log row. Changes maps each changed field to its before
+// and after value.
+type Entry struct {
+	ID            string
+	ActorUserID   string
+	ActorAPIKeyID string
+	Action        string
+	TargetType    string
+	TargetID      string
+	Changes       json.RawMessage
+	IP            string
+	UserAgent     string
+	RequestID     string
+	CreatedAt     time.Time
+}
+
+type ListFilter struct {
+	ActorUserID string
+	Action      string
+	TargetType  string
+	TargetID    string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Pagination  query.Pagination
+}
+
+type ListResult struct {
+	Entries []Entry
+	Total   int
+}
@@ -2,4 +2,7 @@ package auth
 
 import "errors"
 
-var ErrNotFound = errors.New("auth: not found")
+var (
+	ErrNotFound = errors.New("auth: not found")
+	ErrConflict = errors.New("auth: conflict")
+)
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrWeakPassword matches every PasswordPolicyError.
+var ErrWeakPassword = errors.New("auth: password does not meet policy")
+
+// Password policy violation codes.
+const (
+	PasswordTooShort         = "too_short"
+	PasswordTooLong          = "too_long"
+	PasswordCharacterClasses = "character_classes"
+	PasswordMatchesEmail     = "matches_email"
+	PasswordReused           = "reused"
+	PasswordBreached         = "breached"
+)
+
+// PasswordViolation is one password policy rule a password fails.
+type PasswordViolation struct {
+	Code    string
+	Message string
+}
+
+// PasswordPolicyError lists every rule a password fails.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Message)
+	}
+	return "password does not meet policy: " + strings.Join(messages, "; ")
+}
+
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
@@ -7,14 +7,19 @@ type User struct {
 	Email               string
 	PasswordHash        string
 	IsActive            bool
+	EmailVerifiedAt     *time.Time
 	FailedLoginAttempts int
 	LockedUntil         *time.Time
 	TokenVersion        int
+	MFASecret           string
+	MFAEnabledAt        *time.Time
+	MFALastUsedStep     *int64
 }
 
 type RefreshToken struct {
 	ID             string
 	UserID         string
+	SessionID      string
 	TokenHash      string
 	ReplacedByHash string
 	ExpiresAt      time.Time
@@ -24,7 +29,43 @@ type RefreshToken struct {
 	UserAgent      string
 }
 
+// Session is a refresh token rotation chain, represented by its latest token.
+type Session struct {
+	ID         string
+	UserID     string
+	StartedAt  time.Time
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+	IPAddress  string
+	UserAgent  string
+}
+
 type AuthState struct {
 	IsActive     bool
 	TokenVersion int
 }
+
+// APIKey is a long-lived credential whose permissions are limited to Scopes.
+// Only the SHA-256 hash of the key is stored.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// ExternalIdentity is a user as asserted by an external identity provider.
+// Subject is stable per provider; Email is only trusted when EmailVerified.
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
@@ -0,0 +1,73 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	TypeUserActivated           = "user.activated"
+	TypeUserDeactivated         = "user.deactivated"
+	TypeRolePermissionsReplaced = "rbac.role_permissions_replaced"
+	TypeInvoiceStatusChanged    = "payment.invoice_status_changed"
+)
+
+const (
+	AggregateUser    = "user"
+	AggregateRole    = "role"
+	AggregateInvoice = "invoice"
+)
+
+var ErrInvalidEvent = errors.New("event: invalid event")
+
+// Event is a domain event stored in the outbox. ID and Attempts are assigned
+// by the outbox; consumers should use ID to deduplicate redeliveries.
+type Event struct {
+	ID            string
+	Type          string
+	AggregateType string
+	AggregateID   string
+	Payload       json.RawMessage
+	OccurredAt    time.Time
+	Attempts      int
+}
+
+func New(eventType, aggregateType, aggregateID string, payload any) (Event, error) {
+	if strings.TrimSpace(eventType) == "" || strings.TrimSpace(aggregateType) == "" || strings.TrimSpace(aggregateID) == "" {
+		return Event{}, ErrInvalidEvent
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+		OccurredAt:    time.Now().UTC(),
+	}, nil
+}
+
+type UserStatusChanged struct {
+	UserID   string `json:"user_id"`
+	Email    string `json:"email"`
+	IsActive bool   `json:"is_active"`
+}
+
+type RolePermissionsReplaced struct {
+	RoleID        string   `json:"role_id"`
+	PermissionIDs []string `json:"permission_ids"`
+}
+
+type InvoiceStatusChanged struct {
+	InvoiceID       string  `json:"invoice_id"`
+	XenditInvoiceID string  `json:"xendit_invoice_id"`
+	ExternalID      string  `json:"external_id"`
+	FromStatus      string  `json:"from_status,omitempty"`
+	ToStatus        string  `json:"to_status"`
+	Amount          float64 `json:"amount"`
+	Source          string  `json:"source"`
+}
@@ -5,4 +5,6 @@ import "errors"
 var (
 	ErrInvalidInput          = errors.New("payment: invalid input")
 	ErrIdempotencyInProgress = errors.New("payment: idempotency in progress")
+	ErrNotFound              = errors.New("payment: not found")
+	ErrInvalidTransition     = errors.New("payment: invalid status transition")
 )
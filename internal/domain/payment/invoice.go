@@ -0,0 +1,85 @@
+package payment
+
+import (
+	"strings"
+	"time"
+)
+
+type InvoiceStatus string
+
+const (
+	InvoiceStatusPending InvoiceStatus = "PENDING"
+	InvoiceStatusPaid    InvoiceStatus = "PAID"
+	InvoiceStatusSettled InvoiceStatus = "SETTLED"
+	InvoiceStatusExpired InvoiceStatus = "EXPIRED"
+)
+
+const (
+	TransitionSourceAPI     = "api"
+	TransitionSourceWebhook = "webhook"
+)
+
+// invoiceTransitions lists the statuses reachable from each status.
+// SETTLED and EXPIRED are terminal.
+var invoiceTransitions = map[InvoiceStatus][]InvoiceStatus{
+	InvoiceStatusPending: {InvoiceStatusPaid, InvoiceStatusSettled, InvoiceStatusExpired},
+	InvoiceStatusPaid:    {InvoiceStatusSettled},
+}
+
+func ParseInvoiceStatus(value string) (InvoiceStatus, error) {
+	status := InvoiceStatus(strings.ToUpper(strings.TrimSpace(value)))
+	switch status {
+	case InvoiceStatusPending, InvoiceStatusPaid, InvoiceStatusSettled, InvoiceStatusExpired:
+		return status, nil
+	default:
+		return "", ErrInvalidInput
+	}
+}
+
+func (s InvoiceStatus) CanTransitionTo(next InvoiceStatus) bool {
+	for _, allowed := range invoiceTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+type Invoice struct {
+	ID              string
+	XenditInvoiceID string
+	ExternalID      string
+	Amount          float64
+	PaidAmount      *float64
+	Currency        string
+	Status          InvoiceStatus
+	PayerEmail      string
+	Description     string
+	InvoiceURL      string
+	PaymentMethod   string
+	PaymentChannel  string
+	ExpiresAt       *time.Time
+	PaidAt          *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type StatusTransition struct {
+	ID          string
+	InvoiceID   string
+	FromStatus  InvoiceStatus
+	ToStatus    InvoiceStatus
+	Source      string
+	PayloadHash string
+	CreatedAt   time.Time
+}
+
+// TransitionInput describes a status change reported for an invoice. Invoice
+// carries the latest known snapshot and is used to create the local record
+// when the invoice was not created through this service.
+type TransitionInput struct {
+	Invoice     Invoice
+	ToStatus    InvoiceStatus
+	Source      string
+	PayloadHash string
+}
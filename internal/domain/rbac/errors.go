@@ -6,4 +6,14 @@ var (
 	ErrNotFound     = errors.New("rbac: not found")
 	ErrConflict     = errors.New("rbac: conflict")
 	ErrInvalidInput = errors.New("rbac: invalid input")
+	ErrCycle        = errors.New("rbac: role hierarchy cycle")
+	ErrProtected    = errors.New("rbac: system role or permission")
+	// ErrExclusionHeld means a user would hold both roles of an exclusion,
+	// either because of a new exclusion or a new resource-scoped grant.
+	ErrExclusionHeld = errors.New("rbac: exclusive roles held together")
+	// ErrApprovalRequired means a role whose grants need approval would be
+	// inherited or granted on a resource, which would bypass the approval.
+	ErrApprovalRequired = errors.New("rbac: role requires approval")
+	// ErrInvalidPolicy wraps the reason a policy document was rejected.
+	ErrInvalidPolicy = errors.New("rbac: invalid policy")
 )
@@ -0,0 +1,88 @@
+package rbac
+
+import "strings"
+
+// Wildcard grants every permission when used alone ("*"), or every
+// permission below a namespace when used as the last segment ("user.*").
+// Elsewhere it stands for exactly one segment ("user.*.read").
+const Wildcard = "*"
+
+// PermissionMatches reports whether grant covers required. Permissions are
+// dot-separated and compared case-insensitively. A wildcard in required is
+// only covered by a wildcard in grant, so "user.*" covers "user.role.*" but
+// not the other way round.
+func PermissionMatches(grant, required string) bool {
+	grant = NormalizePermission(grant)
+	required = NormalizePermission(required)
+	if grant == "" || required == "" {
+		return false
+	}
+	if grant == required || grant == Wildcard {
+		return true
+	}
+
+	grantParts := strings.Split(grant, ".")
+	requiredParts := strings.Split(required, ".")
+	for i, part := range grantParts {
+		if i >= len(requiredParts) {
+			return false
+		}
+		if part == Wildcard {
+			if i == len(grantParts)-1 {
+				return true
+			}
+			continue
+		}
+		if part != requiredParts[i] {
+			return false
+		}
+	}
+	return len(grantParts) == len(requiredParts)
+}
+
+// Can reports whether any of granted covers permission.
+func Can(granted []string, permission string) bool {
+	for _, grant := range granted {
+		if PermissionMatches(grant, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAny reports whether granted covers at least one of required. An empty
+// required list is always satisfied.
+func CanAny(granted, required []string) bool {
+	if len(required) == 0 {
+		return true
+	}
+	for _, permission := range required {
+		if Can(granted, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanOn reports whether permission is covered globally by granted, or on the
+// resource identified by resourceType and resourceID by one of grants.
+func CanOn(granted []string, grants []ResourceGrant, permission, resourceType, resourceID string) bool {
+	if Can(granted, permission) {
+		return true
+	}
+	resourceType = strings.ToLower(strings.TrimSpace(resourceType))
+	resourceID = strings.TrimSpace(resourceID)
+	if resourceType == "" || resourceID == "" {
+		return false
+	}
+	for _, grant := range grants {
+		if grant.ResourceType == resourceType && grant.ResourceID == resourceID && Can(grant.Permissions, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func NormalizePermission(permission string) string {
+	return strings.ToLower(strings.TrimSpace(permission))
+}
@@ -0,0 +1,70 @@
+package rbac
+
+import "testing"
+
+func TestPermissionMatches(t *testing.T) {
+	cases := []struct {
+		grant    string
+		required string
+		want     bool
+	}{
+		// Exact names, compared case-insensitively.
+		{"user.read", "user.read", true},
+		{" User.Read ", "user.READ", true},
+		{"user.read", "user.update", false},
+		{"", "user.read", false},
+		{"user.read", "", false},
+
+		// "*" grants everything.
+		{"*", "user.read", true},
+		{"*", "user.role.update", true},
+		{"*", "*", true},
+
+		// A trailing wildcard covers the namespace at any depth, but not
+		// the namespace itself or names that merely share a prefix.
+		{"user.*", "user.read", true},
+		{"user.*", "user.role.update", true},
+		{"user.*", "user", false},
+		{"user.*", "username.read", false},
+		{"user.*", "users.read", false},
+		{"user.role.*", "user.read", false},
+
+		// Multi-segment names must match segment by segment.
+		{"user.role.read", "user.role.read", true},
+		{"user.role.read", "user.role", false},
+		{"user.role", "user.role.read", false},
+
+		// An inner wildcard stands for exactly one segment.
+		{"*.read", "user.read", true},
+		{"*.read", "user.role.read", false},
+		{"user.*.read", "user.role.read", true},
+		{"user.*.read", "user.role.update", false},
+		{"user.*.read", "user.read", false},
+
+		// A wildcard in the required name is only covered by a wildcard
+		// grant at least as broad.
+		{"user.*", "user.role.*", true},
+		{"user.role.*", "user.*", false},
+		{"user.read", "user.*", false},
+		{"user.*", "*", false},
+		{"*.read", "*.read", true},
+	}
+	for _, tc := range cases {
+		if got := PermissionMatches(tc.grant, tc.required); got != tc.want {
+			t.Fatalf("PermissionMatches(%q, %q): expected %v, got %v", tc.grant, tc.required, tc.want, got)
+		}
+	}
+}
+
+func TestCanAny(t *testing.T) {
+	granted := []string{"report.read", "user.*"}
+	if !CanAny(granted, nil) {
+		t.Fatal("expected an empty requirement to be satisfied")
+	}
+	if !CanAny(granted, []string{"invoice.read", "user.update"}) {
+		t.Fatal("expected user.* to cover user.update")
+	}
+	if CanAny(granted, []string{"invoice.read", "username.read"}) {
+		t.Fatal("expected no grant to cover invoice.read or username.read")
+	}
+}
@@ -0,0 +1,315 @@
+package rbac
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	PolicyFormatJSON = "json"
+	PolicyFormatYAML = "yaml"
+)
+
+const (
+	PolicyKindRole       = "role"
+	PolicyKindPermission = "permission"
+)
+
+const (
+	PolicyActionCreate       = "create"
+	PolicyActionUpdate       = "update"
+	PolicyActionDelete       = "delete"
+	PolicyActionGrant        = "grant"
+	PolicyActionRevoke       = "revoke"
+	PolicyActionAddParent    = "add_parent"
+	PolicyActionRemoveParent = "remove_parent"
+)
+
+// Policy describes every role and permission and the grants between them.
+// Entries are identified by name so a policy can move between environments.
+type Policy struct {
+	Permissions []PolicyPermission `json:"permissions" yaml:"permissions"`
+	Roles       []PolicyRole       `json:"roles" yaml:"roles"`
+}
+
+// PolicyPermission is a permission in a Policy. System is informational on
+// export and ignored on import.
+type PolicyPermission struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	System      bool   `json:"system,omitempty" yaml:"system,omitempty"`
+}
+
+// PolicyRole is a role in a Policy with the names of the permissions it holds
+// directly and of the roles it inherits from.
+type PolicyRole struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	System      bool     `json:"system,omitempty" yaml:"system,omitempty"`
+	Permissions []string `json:"permissions" yaml:"permissions"`
+	Parents     []string `json:"parents,omitempty" yaml:"parents,omitempty"`
+}
+
+// PolicyChange is one difference between the stored policy and an imported
+// one. Target names the permission or parent role for grant and parent
+// changes.
+type PolicyChange struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Target string `json:"target,omitempty"`
+}
+
+// ParsePolicy decodes a JSON or YAML policy document. Unknown fields are
+// rejected so typos do not silently drop grants.
+func ParsePolicy(data []byte, format string) (Policy, error) {
+	var policy Policy
+	switch format {
+	case PolicyFormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&policy); err != nil {
+			return Policy{}, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+		}
+	case PolicyFormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&policy); err != nil {
+			return Policy{}, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+		}
+	default:
+		return Policy{}, fmt.Errorf("%w: unsupported format %q", ErrInvalidPolicy, format)
+	}
+	return policy.Normalize(), nil
+}
+
+// EncodePolicy renders policy as an indented JSON or YAML document.
+func EncodePolicy(policy Policy, format string) ([]byte, error) {
+	policy = policy.Normalize()
+	switch format {
+	case PolicyFormatJSON:
+		data, err := json.MarshalIndent(policy, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case PolicyFormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(policy); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidPolicy, format)
+	}
+}
+
+// Normalize trims names and descriptions and sorts every list, so equal
+// policies encode and compare the same way.
+func (p Policy) Normalize() Policy {
+	permissions := make([]PolicyPermission, 0, len(p.Permissions))
+	for _, permission := range p.Permissions {
+		permission.Name = strings.TrimSpace(permission.Name)
+		permission.Description = strings.TrimSpace(permission.Description)
+		permissions = append(permissions, permission)
+	}
+	sort.SliceStable(permissions, func(i, j int) bool { return permissions[i].Name < permissions[j].Name })
+
+	roles := make([]PolicyRole, 0, len(p.Roles))
+	for _, role := range p.Roles {
+		role.Name = strings.TrimSpace(role.Name)
+		role.Description = strings.TrimSpace(role.Description)
+		role.Permissions = normalizeNames(role.Permissions)
+		role.Parents = normalizeNames(role.Parents)
+		roles = append(roles, role)
+	}
+	sort.SliceStable(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+
+	return Policy{Permissions: permissions, Roles: roles}
+}
+
+// Validate checks that names are unique and non-empty, that roles only
+// reference permissions and parents declared in the policy, and that the
+// role hierarchy has no cycle.
+func (p Policy) Validate() error {
+	permissions := make(map[string]struct{}, len(p.Permissions))
+	for _, permission := range p.Permissions {
+		if permission.Name == "" {
+			return fmt.Errorf("%w: permission without a name", ErrInvalidPolicy)
+		}
+		if _, ok := permissions[permission.Name]; ok {
+			return fmt.Errorf("%w: permission %q declared twice", ErrInvalidPolicy, permission.Name)
+		}
+		permissions[permission.Name] = struct{}{}
+	}
+
+	parents := make(map[string][]string, len(p.Roles))
+	for _, role := range p.Roles {
+		if role.Name == "" {
+			return fmt.Errorf("%w: role without a name", ErrInvalidPolicy)
+		}
+		if _, ok := parents[role.Name]; ok {
+			return fmt.Errorf("%w: role %q declared twice", ErrInvalidPolicy, role.Name)
+		}
+		for _, name := range role.Permissions {
+			if _, ok := permissions[name]; !ok {
+				return fmt.Errorf("%w: role %q grants undeclared permission %q", ErrInvalidPolicy, role.Name, name)
+			}
+		}
+		parents[role.Name] = role.Parents
+	}
+	for _, role := range p.Roles {
+		for _, parent := range role.Parents {
+			if _, ok := parents[parent]; !ok {
+				return fmt.Errorf("%w: role %q inherits undeclared role %q", ErrInvalidPolicy, role.Name, parent)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(parents))
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		switch state[name] {
+		case visiting:
+			return false
+		case done:
+			return true
+		}
+		state[name] = visiting
+		for _, parent := range parents[name] {
+			if !visit(parent) {
+				return false
+			}
+		}
+		state[name] = done
+		return true
+	}
+	for _, role := range p.Roles {
+		if !visit(role.Name) {
+			return fmt.Errorf("%w: role hierarchy of %q contains a cycle", ErrInvalidPolicy, role.Name)
+		}
+	}
+	return nil
+}
+
+// DiffPolicy lists the changes that turn current into desired. Roles and
+// permissions missing from desired are only deleted when prune is set.
+// Both policies must be normalized.
+func DiffPolicy(current, desired Policy, prune bool) []PolicyChange {
+	var changes []PolicyChange
+
+	currentPermissions := make(map[string]PolicyPermission, len(current.Permissions))
+	for _, permission := range current.Permissions {
+		currentPermissions[permission.Name] = permission
+	}
+	declaredPermissions := make(map[string]struct{}, len(desired.Permissions))
+	for _, permission := range desired.Permissions {
+		declaredPermissions[permission.Name] = struct{}{}
+		existing, ok := currentPermissions[permission.Name]
+		switch {
+		case !ok:
+			changes = append(changes, PolicyChange{Action: PolicyActionCreate, Kind: PolicyKindPermission, Name: permission.Name})
+		case existing.Description != permission.Description:
+			changes = append(changes, PolicyChange{Action: PolicyActionUpdate, Kind: PolicyKindPermission, Name: permission.Name})
+		}
+	}
+	if prune {
+		for _, permission := range current.Permissions {
+			if _, ok := declaredPermissions[permission.Name]; !ok {
+				changes = append(changes, PolicyChange{Action: PolicyActionDelete, Kind: PolicyKindPermission, Name: permission.Name})
+			}
+		}
+	}
+
+	currentRoles := make(map[string]PolicyRole, len(current.Roles))
+	for _, role := range current.Roles {
+		currentRoles[role.Name] = role
+	}
+	declaredRoles := make(map[string]struct{}, len(desired.Roles))
+	for _, role := range desired.Roles {
+		declaredRoles[role.Name] = struct{}{}
+		existing, ok := currentRoles[role.Name]
+		switch {
+		case !ok:
+			changes = append(changes, PolicyChange{Action: PolicyActionCreate, Kind: PolicyKindRole, Name: role.Name})
+		case existing.Description != role.Description:
+			changes = append(changes, PolicyChange{Action: PolicyActionUpdate, Kind: PolicyKindRole, Name: role.Name})
+		}
+		added, removed := diffNames(existing.Permissions, role.Permissions)
+		for _, name := range added {
+			changes = append(changes, PolicyChange{Action: PolicyActionGrant, Kind: PolicyKindRole, Name: role.Name, Target: name})
+		}
+		for _, name := range removed {
+			changes = append(changes, PolicyChange{Action: PolicyActionRevoke, Kind: PolicyKindRole, Name: role.Name, Target: name})
+		}
+		added, removed = diffNames(existing.Parents, role.Parents)
+		for _, name := range added {
+			changes = append(changes, PolicyChange{Action: PolicyActionAddParent, Kind: PolicyKindRole, Name: role.Name, Target: name})
+		}
+		for _, name := range removed {
+			changes = append(changes, PolicyChange{Action: PolicyActionRemoveParent, Kind: PolicyKindRole, Name: role.Name, Target: name})
+		}
+	}
+	if prune {
+		for _, role := range current.Roles {
+			if _, ok := declaredRoles[role.Name]; !ok {
+				changes = append(changes, PolicyChange{Action: PolicyActionDelete, Kind: PolicyKindRole, Name: role.Name})
+			}
+		}
+	}
+	return changes
+}
+
+// diffNames returns the names only in next and the names only in current.
+// Both slices must be sorted.
+func diffNames(current, next []string) ([]string, []string) {
+	var added, removed []string
+	i, j := 0, 0
+	for i < len(current) || j < len(next) {
+		switch {
+		case j == len(next) || (i < len(current) && current[i] < next[j]):
+			removed = append(removed, current[i])
+			i++
+		case i == len(current) || next[j] < current[i]:
+			added = append(added, next[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return added, removed
+}
+
+func normalizeNames(names []string) []string {
+	seen := make(map[string]struct{}, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		trimmed := strings.TrimSpace(name)
+		if trimmed == "" {
+			continue
+		}
+		if _, ok := seen[trimmed]; ok {
+			continue
+		}
+		seen[trimmed] = struct{}{}
+		result = append(result, trimmed)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,114 @@
+package rbac
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	valid := Policy{
+		Permissions: []PolicyPermission{{Name: "user.read"}, {Name: "user.update"}},
+		Roles: []PolicyRole{
+			{Name: "viewer", Permissions: []string{"user.read"}},
+			{Name: "editor", Permissions: []string{"user.update"}, Parents: []string{"viewer"}},
+			{Name: "admin", Parents: []string{"editor", "viewer"}},
+		},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid policy, got %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		policy Policy
+	}{
+		{"unnamed permission", Policy{Permissions: []PolicyPermission{{Name: ""}}}},
+		{"duplicate permission", Policy{Permissions: []PolicyPermission{{Name: "user.read"}, {Name: "user.read"}}}},
+		{"unnamed role", Policy{Roles: []PolicyRole{{Name: ""}}}},
+		{"duplicate role", Policy{Roles: []PolicyRole{{Name: "viewer"}, {Name: "viewer"}}}},
+		{"undeclared permission", Policy{Roles: []PolicyRole{{Name: "viewer", Permissions: []string{"user.read"}}}}},
+		{"undeclared parent", Policy{Roles: []PolicyRole{{Name: "editor", Parents: []string{"viewer"}}}}},
+		{"self parent", Policy{Roles: []PolicyRole{{Name: "viewer", Parents: []string{"viewer"}}}}},
+		{"indirect cycle", Policy{Roles: []PolicyRole{
+			{Name: "a", Parents: []string{"b"}},
+			{Name: "b", Parents: []string{"c"}},
+			{Name: "c", Parents: []string{"a"}},
+		}}},
+	}
+	for _, tc := range cases {
+		if err := tc.policy.Validate(); !errors.Is(err, ErrInvalidPolicy) {
+			t.Fatalf("%s: expected ErrInvalidPolicy, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestDiffPolicy(t *testing.T) {
+	current := Policy{
+		Permissions: []PolicyPermission{
+			{Name: "report.read", Description: "Read reports"},
+			{Name: "user.read", Description: "Read users"},
+		},
+		Roles: []PolicyRole{
+			{Name: "legacy", Permissions: []string{}},
+			{Name: "viewer", Description: "Viewer", Permissions: []string{"report.read", "user.read"}},
+		},
+	}.Normalize()
+
+	desired := Policy{
+		Permissions: []PolicyPermission{
+			{Name: "user.read", Description: "Read all users"},
+			{Name: "user.update"},
+		},
+		Roles: []PolicyRole{
+			{Name: "editor", Permissions: []string{"user.update"}, Parents: []string{"viewer"}},
+			{Name: "viewer", Description: "Viewer", Permissions: []string{"user.read"}},
+		},
+	}.Normalize()
+
+	kept := []PolicyChange{
+		{Action: PolicyActionUpdate, Kind: PolicyKindPermission, Name: "user.read"},
+		{Action: PolicyActionCreate, Kind: PolicyKindPermission, Name: "user.update"},
+		{Action: PolicyActionCreate, Kind: PolicyKindRole, Name: "editor"},
+		{Action: PolicyActionGrant, Kind: PolicyKindRole, Name: "editor", Target: "user.update"},
+		{Action: PolicyActionAddParent, Kind: PolicyKindRole, Name: "editor", Target: "viewer"},
+		{Action: PolicyActionRevoke, Kind: PolicyKindRole, Name: "viewer", Target: "report.read"},
+	}
+	pruned := []PolicyChange{
+		{Action: PolicyActionUpdate, Kind: PolicyKindPermission, Name: "user.read"},
+		{Action: PolicyActionCreate, Kind: PolicyKindPermission, Name: "user.update"},
+		{Action: PolicyActionDelete, Kind: PolicyKindPermission, Name: "report.read"},
+		{Action: PolicyActionCreate, Kind: PolicyKindRole, Name: "editor"},
+		{Action: PolicyActionGrant, Kind: PolicyKindRole, Name: "editor", Target: "user.update"},
+		{Action: PolicyActionAddParent, Kind: PolicyKindRole, Name: "editor", Target: "viewer"},
+		{Action: PolicyActionRevoke, Kind: PolicyKindRole, Name: "viewer", Target: "report.read"},
+		{Action: PolicyActionDelete, Kind: PolicyKindRole, Name: "legacy"},
+	}
+
+	cases := []struct {
+		name     string
+		current  Policy
+		desired  Policy
+		prune    bool
+		expected []PolicyChange
+	}{
+		{"add and remove without prune", current, desired, false, kept},
+		{"add and remove with prune", current, desired, true, pruned},
+		{"no-op import", current, current, true, nil},
+		{"parent removed", Policy{Roles: []PolicyRole{
+			{Name: "editor", Permissions: []string{}, Parents: []string{"viewer"}},
+			{Name: "viewer", Permissions: []string{}},
+		}}.Normalize(), Policy{Roles: []PolicyRole{
+			{Name: "editor"},
+			{Name: "viewer"},
+		}}.Normalize(), false, []PolicyChange{
+			{Action: PolicyActionRemoveParent, Kind: PolicyKindRole, Name: "editor", Target: "viewer"},
+		}},
+	}
+	for _, tc := range cases {
+		got := DiffPolicy(tc.current, tc.desired, tc.prune)
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Fatalf("%s:\nexpected %+v\ngot      %+v", tc.name, tc.expected, got)
+		}
+	}
+}
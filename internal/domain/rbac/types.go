@@ -6,17 +6,76 @@ import (
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/domain/query"
 )
 
+// AdminRole is the seeded system role that holds every permission.
+const AdminRole = "admin"
+
+// Role is a named set of permissions. System roles are seeded by migrations
+// and cannot be renamed or deleted.
 type Role struct {
 	ID          string
 	Name        string
 	Description string
+	IsSystem    bool
 	CreatedAt   time.Time
 }
 
+// UserRole is a global role assignment. ValidFrom and ValidUntil bound a
+// temporary grant; nil means unbounded on that side.
+type UserRole struct {
+	Role       Role
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
+}
+
+// RoleAssignment identifies one user_roles row. ResourceType and ResourceID
+// are empty for global assignments.
+type RoleAssignment struct {
+	UserID       string
+	RoleID       string
+	ResourceType string
+	ResourceID   string
+}
+
+// RoleParent is an edge in the role hierarchy: RoleID inherits the
+// permissions of ParentRoleID.
+type RoleParent struct {
+	RoleID       string
+	ParentRoleID string
+}
+
+// RoleConstraints are the separation-of-duties rules of a role: whether
+// granting it needs a second administrator's approval, and which roles a
+// user may not hold alongside it.
+type RoleConstraints struct {
+	RequiresApproval bool
+	ExcludedRoles    []Role
+}
+
+// ResourceRole assigns a role to a user for a single resource, such as
+// invoice.read on the invoices of one merchant.
+type ResourceRole struct {
+	UserID       string
+	Role         Role
+	ResourceType string
+	ResourceID   string
+	CreatedAt    time.Time
+}
+
+// ResourceGrant is the set of permissions a user holds on one resource
+// through resource-scoped roles, including inherited ones.
+type ResourceGrant struct {
+	ResourceType string
+	ResourceID   string
+	Permissions  []string
+}
+
+// Permission is a grantable action. System permissions are checked by the
+// application itself and cannot be renamed or deleted.
 type Permission struct {
 	ID          string
 	Name        string
 	Description string
+	IsSystem    bool
 	CreatedAt   time.Time
 }
 
@@ -3,7 +3,13 @@ package user
 import "errors"
 
 var (
-	ErrNotFound     = errors.New("user: not found")
-	ErrConflict     = errors.New("user: conflict")
-	ErrInvalidInput = errors.New("user: invalid input")
+	ErrNotFound          = errors.New("user: not found")
+	ErrConflict          = errors.New("user: conflict")
+	ErrInvalidInput      = errors.New("user: invalid input")
+	ErrLastAdmin         = errors.New("user: last active admin")
+	ErrExclusiveRoles    = errors.New("user: mutually exclusive roles")
+	ErrApprovalRequired  = errors.New("user: role requires approval")
+	ErrSelfApproval      = errors.New("user: approver must be another person")
+	ErrRequestNotFound   = errors.New("user: role request not found")
+	ErrRequestNotPending = errors.New("user: role request already decided")
 )
@@ -10,3 +10,26 @@ type User struct {
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
+
+const (
+	RoleRequestPending  = "pending"
+	RoleRequestApproved = "approved"
+	RoleRequestRejected = "rejected"
+)
+
+// RoleGrantRequest is a pending or decided grant of a role that requires
+// approval. It only reaches user_roles once someone other than the requester
+// and the grantee approves it.
+type RoleGrantRequest struct {
+	ID          string
+	UserID      string
+	RoleID      string
+	RoleName    string
+	ValidFrom   *time.Time
+	ValidUntil  *time.Time
+	RequestedBy string
+	Status      string
+	DecidedBy   string
+	DecidedAt   *time.Time
+	CreatedAt   time.Time
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+)
+
+type AuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{pool: pool}
+}
+
+func (r *AuditRepository) InsertEntry(ctx context.Context, entry auditdomain.Entry) error {
+	const query = `
+		INSERT INTO audit_logs (
+			actor_user_id, actor_api_key_id, action, target_type, target_id,
+			changes, ip, user_agent, request_id
+		)
+		VALUES (NULLIF($1, '')::uuid, NULLIF($2, '')::uuid, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''))
+	`
+
+	changes := []byte(entry.Changes)
+	if len(changes) == 0 {
+		changes = []byte("{}")
+	}
+	_, err := r.pool.Exec(ctx, query,
+		entry.ActorUserID,
+		entry.ActorAPIKeyID,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		changes,
+		entry.IP,
+		entry.UserAgent,
+		entry.RequestID,
+	)
+	return err
+}
+
+func (r *AuditRepository) ListEntries(ctx context.Context, filter auditdomain.ListFilter) (auditdomain.ListResult, error) {
+	where, args := buildAuditListFilter(filter)
+
+	countQuery := `SELECT COUNT(*) FROM audit_logs`
+	if where != "" {
+		countQuery += " " + where
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return auditdomain.ListResult{}, err
+	}
+
+	limit := filter.Pagination.Limit()
+	if limit <= 0 {
+		limit = 20
+	}
+	offset := filter.Pagination.Offset()
+	if offset < 0 {
+		offset = 0
+	}
+
+	listQuery := fmt.Sprintf(`
+		SELECT id::text, COALESCE(actor_user_id::text, ''), COALESCE(actor_api_key_id::text, ''),
+			action, target_type, target_id, changes, COALESCE(ip, ''), COALESCE(user_agent, ''),
+			COALESCE(request_id, ''), created_at
+		FROM audit_logs
+		%s
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2)
+
+	listArgs := append(args, limit, offset)
+	rows, err := r.pool.Query(ctx, listQuery, listArgs...)
+	if err != nil {
+		return auditdomain.ListResult{}, err
+	}
+	defer rows.Close()
+
+	var entries []auditdomain.Entry
+	for rows.Next() {
+		var entry auditdomain.Entry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.ActorUserID,
+			&entry.ActorAPIKeyID,
+			&entry.Action,
+			&entry.TargetType,
+			&entry.TargetID,
+			&entry.Changes,
+			&entry.IP,
+			&entry.UserAgent,
+			&entry.RequestID,
+			&entry.CreatedAt,
+		); err != nil {
+			return auditdomain.ListResult{}, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return auditdomain.ListResult{}, err
+	}
+
+	return auditdomain.ListResult{
+		Entries: entries,
+		Total:   total,
+	}, nil
+}
+
+func buildAuditListFilter(filter auditdomain.ListFilter) (string, []any) {
+	conditions := make([]string, 0, 6)
+	args := make([]any, 0, 6)
+
+	if actor := strings.TrimSpace(filter.ActorUserID); actor != "" {
+		args = append(args, actor)
+		conditions = append(conditions, fmt.Sprintf("actor_user_id::text = $%d", len(args)))
+	}
+	if action := strings.TrimSpace(filter.Action); action != "" {
+		args = append(args, action)
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if targetType := strings.TrimSpace(filter.TargetType); targetType != "" {
+		args = append(args, targetType)
+		conditions = append(conditions, fmt.Sprintf("target_type = $%d", len(args)))
+	}
+	if targetID := strings.TrimSpace(filter.TargetID); targetID != "" {
+		args = append(args, targetID)
+		conditions = append(conditions, fmt.Sprintf("target_id = $%d", len(args)))
+	}
+	if filter.CreatedFrom != nil {
+		args = append(args, *filter.CreatedFrom)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.CreatedTo != nil {
+		args = append(args, *filter.CreatedTo)
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
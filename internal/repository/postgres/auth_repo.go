@@ -5,8 +5,10 @@ import (
 	"errors"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 )
 
 type AuthRepository struct {
@@ -17,23 +19,17 @@ func NewAuthRepository(pool *pgxpool.Pool) *AuthRepository {
 	return &AuthRepository{pool: pool}
 }
 
+const authUserColumns = `id::text, email, password_hash, is_active, email_verified_at, failed_login_attempts,
+	locked_until, token_version, COALESCE(mfa_totp_secret, ''), mfa_enabled_at, mfa_last_used_step`
+
 func (r *AuthRepository) FindUserByEmail(ctx context.Context, email string) (authdomain.User, error) {
 	const query = `
-		SELECT id::text, email, password_hash, is_active, failed_login_attempts, locked_until, token_version
+		SELECT ` + authUserColumns + `
 		FROM users
 		WHERE email = $1
 	`
 
-	var user authdomain.User
-	err := r.pool.QueryRow(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.IsActive,
-		&user.FailedLoginAttempts,
-		&user.LockedUntil,
-		&user.TokenVersion,
-	)
+	user, err := scanAuthUser(r.pool.QueryRow(ctx, query, email))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return authdomain.User{}, authdomain.ErrNotFound
@@ -45,21 +41,12 @@ func (r *AuthRepository) FindUserByEmail(ctx context.Context, email string) (aut
 
 func (r *AuthRepository) FindUserByID(ctx context.Context, id string) (authdomain.User, error) {
 	const query = `
-		SELECT id::text, email, password_hash, is_active, failed_login_attempts, locked_until, token_version
+		SELECT ` + authUserColumns + `
 		FROM users
 		WHERE id = $1
 	`
 
-	var user authdomain.User
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.PasswordHash,
-		&user.IsActive,
-		&user.FailedLoginAttempts,
-		&user.LockedUntil,
-		&user.TokenVersion,
-	)
+	user, err := scanAuthUser(r.pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return authdomain.User{}, authdomain.ErrNotFound
@@ -69,6 +56,145 @@ func (r *AuthRepository) FindUserByID(ctx context.Context, id string) (authdomai
 	return user, nil
 }
 
+func (r *AuthRepository) CreateUser(ctx context.Context, email, passwordHash string) (authdomain.User, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return authdomain.User{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	const query = `
+		INSERT INTO users (email, password_hash, is_active)
+		VALUES ($1, $2, true)
+		RETURNING ` + authUserColumns + `
+	`
+
+	user, err := scanAuthUser(tx.QueryRow(ctx, query, email, passwordHash))
+	if err != nil {
+		return authdomain.User{}, mapAuthError(err)
+	}
+	if err := recordPasswordHistory(ctx, tx, user.ID, passwordHash); err != nil {
+		return authdomain.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return authdomain.User{}, err
+	}
+	return user, nil
+}
+
+// MarkEmailVerified marks the user's email verified if it is still email,
+// the address the verification link was sent to. It returns ErrNotFound
+// when the user is gone or the email has changed since.
+func (r *AuthRepository) MarkEmailVerified(ctx context.Context, userID, email string) error {
+	const query = `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, now()),
+			updated_at = now()
+		WHERE id = $1 AND email = $2
+	`
+
+	tag, err := r.pool.Exec(ctx, query, userID, email)
+	if err != nil {
+		return mapAuthError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return authdomain.ErrNotFound
+	}
+	return nil
+}
+
+// FindUserByIdentity returns the user linked to an external identity and
+// records the login on the link.
+func (r *AuthRepository) FindUserByIdentity(ctx context.Context, provider, subject string) (authdomain.User, error) {
+	const query = `
+		WITH identity AS (
+			UPDATE user_identities
+			SET last_login_at = now()
+			WHERE provider = $1 AND subject = $2
+			RETURNING user_id
+		)
+		SELECT ` + authUserColumns + `
+		FROM users
+		WHERE id = (SELECT user_id FROM identity)
+	`
+
+	user, err := scanAuthUser(r.pool.QueryRow(ctx, query, provider, subject))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return authdomain.User{}, authdomain.ErrNotFound
+		}
+		return authdomain.User{}, mapAuthError(err)
+	}
+	return user, nil
+}
+
+// LinkIdentity links an external identity to an existing user whose email
+// is verified. It returns ErrNotFound when there is no such user, so an
+// account whose email was changed or never verified is not linked.
+func (r *AuthRepository) LinkIdentity(ctx context.Context, userID string, identity authdomain.ExternalIdentity) error {
+	const query = `
+		INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+		SELECT id, $2, $3, NULLIF($4, ''), now()
+		FROM users
+		WHERE id = $1 AND email_verified_at IS NOT NULL
+	`
+
+	tag, err := r.pool.Exec(ctx, query, userID, identity.Provider, identity.Subject, identity.Email)
+	if err != nil {
+		return mapAuthError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return authdomain.ErrNotFound
+	}
+	return nil
+}
+
+// CreateUserWithIdentity creates a user with a verified email and no
+// password, linked to an external identity.
+func (r *AuthRepository) CreateUserWithIdentity(ctx context.Context, email string, identity authdomain.ExternalIdentity) (authdomain.User, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return authdomain.User{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	const createQuery = `
+		INSERT INTO users (email, password_hash, is_active, email_verified_at)
+		VALUES ($1, '', true, now())
+		RETURNING ` + authUserColumns + `
+	`
+
+	user, err := scanAuthUser(tx.QueryRow(ctx, createQuery, email))
+	if err != nil {
+		return authdomain.User{}, mapAuthError(err)
+	}
+	if err := insertIdentity(ctx, tx, user.ID, identity); err != nil {
+		return authdomain.User{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return authdomain.User{}, err
+	}
+	return user, nil
+}
+
+func insertIdentity(ctx context.Context, tx pgx.Tx, userID string, identity authdomain.ExternalIdentity) error {
+	const query = `
+		INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), now())
+	`
+
+	if _, err := tx.Exec(ctx, query, userID, identity.Provider, identity.Subject, identity.Email); err != nil {
+		return mapAuthError(err)
+	}
+	return nil
+}
+
 func (r *AuthRepository) GetUserAuthState(ctx context.Context, id string) (authdomain.AuthState, error) {
 	const query = `
 		SELECT is_active, token_version
@@ -87,12 +213,16 @@ func (r *AuthRepository) GetUserAuthState(ctx context.Context, id string) (authd
 	return state, nil
 }
 
+// ListUserRoles returns the names of the user's global roles that are
+// currently in their validity window.
 func (r *AuthRepository) ListUserRoles(ctx context.Context, userID string) ([]string, error) {
 	const query = `
 		SELECT r.name
 		FROM roles r
 		JOIN user_roles ur ON ur.role_id = r.id
-		WHERE ur.user_id = $1
+		WHERE ur.user_id = $1 AND ur.resource_type = ''
+			AND (ur.valid_from IS NULL OR ur.valid_from <= now())
+			AND (ur.valid_until IS NULL OR ur.valid_until > now())
 	`
 
 	rows, err := r.pool.Query(ctx, query, userID)
@@ -112,13 +242,27 @@ func (r *AuthRepository) ListUserRoles(ctx context.Context, userID string) ([]st
 	return roles, rows.Err()
 }
 
+// ListUserPermissions returns the permissions of the user's roles and of
+// every role they inherit from. Assignments outside their validity window
+// are ignored even before the expiry job removes them. UNION keeps the walk
+// finite even if the hierarchy ever contains a cycle.
 func (r *AuthRepository) ListUserPermissions(ctx context.Context, userID string) ([]string, error) {
 	const query = `
+		WITH RECURSIVE effective_roles AS (
+			SELECT ur.role_id
+			FROM user_roles ur
+			WHERE ur.user_id = $1 AND ur.resource_type = ''
+				AND (ur.valid_from IS NULL OR ur.valid_from <= now())
+				AND (ur.valid_until IS NULL OR ur.valid_until > now())
+			UNION
+			SELECT rp.parent_role_id
+			FROM role_parents rp
+			JOIN effective_roles er ON er.role_id = rp.role_id
+		)
 		SELECT DISTINCT p.name
 		FROM permissions p
 		JOIN role_permissions rp ON rp.permission_id = p.id
-		JOIN user_roles ur ON ur.role_id = rp.role_id
-		WHERE ur.user_id = $1
+		JOIN effective_roles er ON er.role_id = rp.role_id
 	`
 
 	rows, err := r.pool.Query(ctx, query, userID)
@@ -138,19 +282,60 @@ func (r *AuthRepository) ListUserPermissions(ctx context.Context, userID string)
 	return permissions, rows.Err()
 }
 
+// ListUserResourceGrants resolves the user's resource-scoped roles, through
+// the role hierarchy, into permissions per resource.
+func (r *AuthRepository) ListUserResourceGrants(ctx context.Context, userID string) ([]rbacdomain.ResourceGrant, error) {
+	const query = `
+		WITH RECURSIVE scoped_roles AS (
+			SELECT ur.role_id, ur.resource_type, ur.resource_id
+			FROM user_roles ur
+			WHERE ur.user_id = $1 AND ur.resource_type <> ''
+				AND (ur.valid_from IS NULL OR ur.valid_from <= now())
+				AND (ur.valid_until IS NULL OR ur.valid_until > now())
+			UNION
+			SELECT rp.parent_role_id, sr.resource_type, sr.resource_id
+			FROM role_parents rp
+			JOIN scoped_roles sr ON sr.role_id = rp.role_id
+		)
+		SELECT sr.resource_type, sr.resource_id, array_agg(DISTINCT p.name ORDER BY p.name)
+		FROM scoped_roles sr
+		JOIN role_permissions rp ON rp.role_id = sr.role_id
+		JOIN permissions p ON p.id = rp.permission_id
+		GROUP BY sr.resource_type, sr.resource_id
+		ORDER BY sr.resource_type, sr.resource_id
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var grants []rbacdomain.ResourceGrant
+	for rows.Next() {
+		var grant rbacdomain.ResourceGrant
+		if err := rows.Scan(&grant.ResourceType, &grant.ResourceID, &grant.Permissions); err != nil {
+			return nil, err
+		}
+		grants = append(grants, grant)
+	}
+	return grants, rows.Err()
+}
+
 func (r *AuthRepository) CreateRefreshToken(ctx context.Context, token authdomain.RefreshToken) error {
 	const query = `
-		INSERT INTO refresh_tokens (user_id, token_hash, expires_at, ip_address, user_agent)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO refresh_tokens (user_id, session_id, token_hash, expires_at, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
-	_, err := r.pool.Exec(ctx, query, token.UserID, token.TokenHash, token.ExpiresAt, token.IPAddress, token.UserAgent)
-	return err
+	_, err := r.pool.Exec(ctx, query, token.UserID, token.SessionID, token.TokenHash, token.ExpiresAt, token.IPAddress, token.UserAgent)
+	return mapAuthError(err)
 }
 
 func (r *AuthRepository) GetRefreshToken(ctx context.Context, tokenHash string) (authdomain.RefreshToken, error) {
 	const query = `
-		SELECT id::text, user_id::text, token_hash, COALESCE(replaced_by_hash, ''), expires_at, revoked_at, created_at, ip_address, user_agent
+		SELECT id::text, user_id::text, session_id::text, token_hash, COALESCE(replaced_by_hash, ''), expires_at, revoked_at, created_at,
+			COALESCE(ip_address, ''), COALESCE(user_agent, '')
 		FROM refresh_tokens
 		WHERE token_hash = $1
 	`
@@ -159,6 +344,7 @@ func (r *AuthRepository) GetRefreshToken(ctx context.Context, tokenHash string)
 	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
 		&token.ID,
 		&token.UserID,
+		&token.SessionID,
 		&token.TokenHash,
 		&token.ReplacedByHash,
 		&token.ExpiresAt,
@@ -198,6 +384,103 @@ func (r *AuthRepository) RevokeAllRefreshTokens(ctx context.Context, userID stri
 	return err
 }
 
+// ListSessions returns the user's active sessions. A session is active while
+// the latest token of its rotation chain is neither revoked nor expired.
+func (r *AuthRepository) ListSessions(ctx context.Context, userID string) ([]authdomain.Session, error) {
+	const query = `
+		SELECT session_id, user_id, started_at, last_used_at, expires_at, ip_address, user_agent
+		FROM (
+			SELECT DISTINCT ON (t.session_id)
+				t.session_id::text AS session_id,
+				t.user_id::text AS user_id,
+				(SELECT MIN(s.created_at) FROM refresh_tokens s WHERE s.session_id = t.session_id) AS started_at,
+				t.created_at AS last_used_at,
+				t.expires_at,
+				COALESCE(t.ip_address, '') AS ip_address,
+				COALESCE(t.user_agent, '') AS user_agent
+			FROM refresh_tokens t
+			WHERE t.user_id = $1 AND t.revoked_at IS NULL AND t.expires_at > now()
+			ORDER BY t.session_id, t.created_at DESC
+		) active
+		ORDER BY last_used_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, mapAuthError(err)
+	}
+	defer rows.Close()
+
+	sessions := make([]authdomain.Session, 0)
+	for rows.Next() {
+		var session authdomain.Session
+		if err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.StartedAt,
+			&session.LastUsedAt,
+			&session.ExpiresAt,
+			&session.IPAddress,
+			&session.UserAgent,
+		); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func (r *AuthRepository) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	const query = `
+		UPDATE refresh_tokens
+		SET revoked_at = now()
+		WHERE user_id = $1 AND session_id = $2 AND revoked_at IS NULL AND expires_at > now()
+	`
+
+	tag, err := r.pool.Exec(ctx, query, userID, sessionID)
+	if err != nil {
+		return mapAuthError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return authdomain.ErrNotFound
+	}
+	return nil
+}
+
+// RevokeSessionsExcept revokes every active session of the user other than
+// keepSessionID (all of them when it is empty) and returns the revoked ids.
+func (r *AuthRepository) RevokeSessionsExcept(ctx context.Context, userID, keepSessionID string) ([]string, error) {
+	const query = `
+		UPDATE refresh_tokens
+		SET revoked_at = now()
+		WHERE user_id = $1
+			AND revoked_at IS NULL
+			AND ($2 = '' OR session_id::text <> $2)
+		RETURNING session_id::text
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID, keepSessionID)
+	if err != nil {
+		return nil, mapAuthError(err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]struct{})
+	sessionIDs := make([]string, 0)
+	for rows.Next() {
+		var sessionID string
+		if err := rows.Scan(&sessionID); err != nil {
+			return nil, err
+		}
+		if _, ok := seen[sessionID]; ok {
+			continue
+		}
+		seen[sessionID] = struct{}{}
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	return sessionIDs, rows.Err()
+}
+
 func (r *AuthRepository) DeleteExpiredRefreshTokens(ctx context.Context) (int64, error) {
 	const query = `
 		DELETE FROM refresh_tokens
@@ -211,6 +494,94 @@ func (r *AuthRepository) DeleteExpiredRefreshTokens(ctx context.Context) (int64,
 	return tag.RowsAffected(), nil
 }
 
+const apiKeyColumns = `id::text, user_id::text, name, prefix, key_hash, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+func (r *AuthRepository) CreateAPIKey(ctx context.Context, key authdomain.APIKey) (authdomain.APIKey, error) {
+	const query = `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + apiKeyColumns + `
+	`
+
+	created, err := scanAPIKey(r.pool.QueryRow(ctx, query, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Scopes, key.ExpiresAt))
+	if err != nil {
+		return authdomain.APIKey{}, mapAuthError(err)
+	}
+	return created, nil
+}
+
+func (r *AuthRepository) ListAPIKeys(ctx context.Context, userID string) ([]authdomain.APIKey, error) {
+	const query = `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, mapAuthError(err)
+	}
+	defer rows.Close()
+
+	keys := make([]authdomain.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *AuthRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (authdomain.APIKey, error) {
+	const query = `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE key_hash = $1
+	`
+
+	key, err := scanAPIKey(r.pool.QueryRow(ctx, query, keyHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return authdomain.APIKey{}, authdomain.ErrNotFound
+		}
+		return authdomain.APIKey{}, err
+	}
+	return key, nil
+}
+
+func (r *AuthRepository) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	const query = `
+		UPDATE api_keys
+		SET revoked_at = now()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	tag, err := r.pool.Exec(ctx, query, keyID, userID)
+	if err != nil {
+		return mapAuthError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return authdomain.ErrNotFound
+	}
+	return nil
+}
+
+// TouchAPIKey records key usage. Writes are coalesced to one per minute so a
+// busy integration does not update the row on every request.
+func (r *AuthRepository) TouchAPIKey(ctx context.Context, keyID string) error {
+	const query = `
+		UPDATE api_keys
+		SET last_used_at = now()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+	`
+
+	_, err := r.pool.Exec(ctx, query, keyID)
+	return err
+}
+
 func (r *AuthRepository) RecordLoginFailure(ctx context.Context, userID string, maxAttempts int, lockoutSeconds int64) error {
 	const query = `
 		UPDATE users
@@ -239,6 +610,14 @@ func (r *AuthRepository) ResetLoginFailures(ctx context.Context, userID string)
 }
 
 func (r *AuthRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
 	const query = `
 		UPDATE users
 		SET password_hash = $2,
@@ -248,12 +627,286 @@ func (r *AuthRepository) UpdatePassword(ctx context.Context, userID, passwordHas
 		WHERE id = $1
 	`
 
-	tag, err := r.pool.Exec(ctx, query, userID, passwordHash)
+	tag, err := tx.Exec(ctx, query, userID, passwordHash)
 	if err != nil {
 		return err
 	}
 	if tag.RowsAffected() == 0 {
 		return authdomain.ErrNotFound
 	}
+	if err := recordPasswordHistory(ctx, tx, userID, passwordHash); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ListPasswordHistory returns up to limit of the user's password hashes,
+// newest first, including the current one.
+func (r *AuthRepository) ListPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error) {
+	return listPasswordHistory(ctx, r.pool, userID, limit)
+}
+
+// UpgradePasswordHash replaces currentHash with newHash, a rehash of the same
+// password. Sessions are kept, and nothing changes if the password was
+// changed concurrently.
+func (r *AuthRepository) UpgradePasswordHash(ctx context.Context, userID, currentHash, newHash string) error {
+	const query = `
+		UPDATE users
+		SET password_hash = $3
+		WHERE id = $1 AND password_hash = $2
+	`
+
+	if _, err := r.pool.Exec(ctx, query, userID, currentHash, newHash); err != nil {
+		return mapAuthError(err)
+	}
+	return nil
+}
+
+func (r *AuthRepository) SetMFASecret(ctx context.Context, userID, secret string) error {
+	const query = `
+		UPDATE users
+		SET mfa_totp_secret = $2,
+			updated_at = now()
+		WHERE id = $1 AND mfa_enabled_at IS NULL
+	`
+
+	tag, err := r.pool.Exec(ctx, query, userID, secret)
+	if err != nil {
+		return mapAuthError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return authdomain.ErrConflict
+	}
 	return nil
 }
+
+func (r *AuthRepository) EnableMFA(ctx context.Context, userID string, usedStep int64, recoveryCodeHashes []string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	const updateQuery = `
+		UPDATE users
+		SET mfa_enabled_at = now(),
+			mfa_last_used_step = $2,
+			updated_at = now()
+		WHERE id = $1 AND mfa_enabled_at IS NULL AND mfa_totp_secret IS NOT NULL
+	`
+
+	tag, err := tx.Exec(ctx, updateQuery, userID, usedStep)
+	if err != nil {
+		return mapAuthError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return authdomain.ErrConflict
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	if len(recoveryCodeHashes) > 0 {
+		batch := &pgx.Batch{}
+		for _, codeHash := range recoveryCodeHashes {
+			batch.Queue(`INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, codeHash)
+		}
+		results := tx.SendBatch(ctx, batch)
+		for range recoveryCodeHashes {
+			if _, err := results.Exec(); err != nil {
+				_ = results.Close()
+				return mapAuthError(err)
+			}
+		}
+		if err := results.Close(); err != nil {
+			return mapAuthError(err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *AuthRepository) DisableMFA(ctx context.Context, userID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	const updateQuery = `
+		UPDATE users
+		SET mfa_totp_secret = NULL,
+			mfa_enabled_at = NULL,
+			mfa_last_used_step = NULL,
+			updated_at = now()
+		WHERE id = $1
+	`
+
+	tag, err := tx.Exec(ctx, updateQuery, userID)
+	if err != nil {
+		return mapAuthError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return authdomain.ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// MarkTOTPStepUsed records the time step of an accepted code. It returns false
+// when the step (or a later one) was already used, which rejects replays.
+func (r *AuthRepository) MarkTOTPStepUsed(ctx context.Context, userID string, step int64) (bool, error) {
+	const query = `
+		UPDATE users
+		SET mfa_last_used_step = $2
+		WHERE id = $1 AND (mfa_last_used_step IS NULL OR mfa_last_used_step < $2)
+	`
+
+	tag, err := r.pool.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, mapAuthError(err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *AuthRepository) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	const query = `
+		UPDATE mfa_recovery_codes
+		SET used_at = now()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	tag, err := r.pool.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, mapAuthError(err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanAuthUser(row pgx.Row) (authdomain.User, error) {
+	var user authdomain.User
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.IsActive,
+		&user.EmailVerifiedAt,
+		&user.FailedLoginAttempts,
+		&user.LockedUntil,
+		&user.TokenVersion,
+		&user.MFASecret,
+		&user.MFAEnabledAt,
+		&user.MFALastUsedStep,
+	)
+	return user, err
+}
+
+func scanAPIKey(row pgx.Row) (authdomain.APIKey, error) {
+	var key authdomain.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	return key, err
+}
+
+// passwordHistoryRetention is how many password hashes are kept per user.
+// PASSWORD_HISTORY cannot exceed it.
+const passwordHistoryRetention = 24
+
+// recordPasswordHistory adds passwordHash to the user's password history,
+// unless it is already there, and drops the oldest entries beyond
+// passwordHistoryRetention. Users without a password are skipped.
+func recordPasswordHistory(ctx context.Context, tx pgx.Tx, userID, passwordHash string) error {
+	if passwordHash == "" {
+		return nil
+	}
+
+	const insertQuery = `
+		INSERT INTO password_history (user_id, password_hash)
+		SELECT $1, $2
+		WHERE NOT EXISTS (
+			SELECT 1 FROM password_history WHERE user_id = $1 AND password_hash = $2
+		)
+	`
+	if _, err := tx.Exec(ctx, insertQuery, userID, passwordHash); err != nil {
+		return err
+	}
+
+	const pruneQuery = `
+		DELETE FROM password_history
+		WHERE user_id = $1
+			AND id NOT IN (
+				SELECT id
+				FROM password_history
+				WHERE user_id = $1
+				ORDER BY created_at DESC
+				LIMIT $2
+			)
+	`
+	_, err := tx.Exec(ctx, pruneQuery, userID, passwordHistoryRetention)
+	return err
+}
+
+func listPasswordHistory(ctx context.Context, pool *pgxpool.Pool, userID string, limit int) ([]string, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	const query = `
+		SELECT password_hash
+		FROM password_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := pool.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
+
+func mapAuthError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return authdomain.ErrConflict
+		case "22P02":
+			return authdomain.ErrNotFound
+		}
+	}
+	return err
+}
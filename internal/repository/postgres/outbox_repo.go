@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
+)
+
+type OutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{pool: pool}
+}
+
+// Claim leases up to limit due events for the given duration. Events whose
+// lease expires without MarkPublished or MarkFailed are claimed again, which
+// gives at-least-once delivery if the relay dies mid-batch.
+func (r *OutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]eventdomain.Event, error) {
+	const query = `
+		UPDATE outbox
+		SET available_at = now() + make_interval(secs => $2),
+			attempts = attempts + 1
+		WHERE id IN (
+			SELECT id
+			FROM outbox
+			WHERE published_at IS NULL AND available_at <= now()
+			ORDER BY occurred_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id::text, event_type, aggregate_type, aggregate_id, payload, occurred_at, attempts
+	`
+
+	rows, err := r.pool.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []eventdomain.Event
+	for rows.Next() {
+		var event eventdomain.Event
+		if err := rows.Scan(&event.ID, &event.Type, &event.AggregateType, &event.AggregateID, &event.Payload, &event.OccurredAt, &event.Attempts); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING does not keep the subquery order.
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id string) error {
+	const query = `
+		UPDATE outbox
+		SET published_at = now(), last_error = NULL
+		WHERE id = $1
+	`
+	_, err := r.pool.Exec(ctx, query, id)
+	return err
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id, lastError string, retryAt time.Time) error {
+	const query = `
+		UPDATE outbox
+		SET available_at = $2, last_error = $3
+		WHERE id = $1 AND published_at IS NULL
+	`
+	_, err := r.pool.Exec(ctx, query, id, retryAt, lastError)
+	return err
+}
+
+func (r *OutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM outbox WHERE published_at IS NOT NULL AND published_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// insertOutboxEvents stores events in tx so they are committed or rolled back
+// together with the state change that produced them.
+func insertOutboxEvents(ctx context.Context, tx pgx.Tx, events []eventdomain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	const query = `
+		INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	batch := &pgx.Batch{}
+	for _, event := range events {
+		occurredAt := event.OccurredAt
+		if occurredAt.IsZero() {
+			occurredAt = time.Now().UTC()
+		}
+		batch.Queue(query, event.Type, event.AggregateType, event.AggregateID, []byte(event.Payload), occurredAt)
+	}
+	results := tx.SendBatch(ctx, batch)
+	for range events {
+		if _, err := results.Exec(); err != nil {
+			_ = results.Close()
+			return err
+		}
+	}
+	return results.Close()
+}
@@ -0,0 +1,318 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
+	paymentdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/payment"
+)
+
+const invoiceColumns = `
+	id::text, xendit_invoice_id, external_id, amount::float8, paid_amount::float8,
+	COALESCE(currency, ''), status, COALESCE(payer_email, ''), COALESCE(description, ''),
+	COALESCE(invoice_url, ''), COALESCE(payment_method, ''), COALESCE(payment_channel, ''),
+	expires_at, paid_at, created_at, updated_at
+`
+
+type PaymentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPaymentRepository(pool *pgxpool.Pool) *PaymentRepository {
+	return &PaymentRepository{pool: pool}
+}
+
+// SaveInvoice stores the invoice returned by Xendit. Existing rows keep their
+// status; status changes only go through ApplyStatusTransition.
+func (r *PaymentRepository) SaveInvoice(ctx context.Context, invoice paymentdomain.Invoice, source string) (paymentdomain.Invoice, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return paymentdomain.Invoice{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	query := `
+		INSERT INTO invoices (
+			xendit_invoice_id, external_id, amount, paid_amount, currency, status, payer_email,
+			description, invoice_url, payment_method, payment_channel, expires_at, paid_at
+		)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''),
+			NULLIF($10, ''), NULLIF($11, ''), $12, $13)
+		ON CONFLICT (xendit_invoice_id) DO UPDATE
+		SET currency = COALESCE(EXCLUDED.currency, invoices.currency),
+			payer_email = COALESCE(EXCLUDED.payer_email, invoices.payer_email),
+			description = COALESCE(EXCLUDED.description, invoices.description),
+			invoice_url = COALESCE(EXCLUDED.invoice_url, invoices.invoice_url),
+			expires_at = COALESCE(EXCLUDED.expires_at, invoices.expires_at),
+			updated_at = now()
+		RETURNING ` + invoiceColumns + `, (xmax = 0)
+	`
+
+	var inserted bool
+	saved, err := scanInvoice(tx.QueryRow(ctx, query, invoiceArgs(invoice)...), &inserted)
+	if err != nil {
+		return paymentdomain.Invoice{}, mapPaymentError(err)
+	}
+
+	if inserted {
+		if err := insertStatusTransition(ctx, tx, saved.ID, "", saved.Status, source, ""); err != nil {
+			return paymentdomain.Invoice{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return paymentdomain.Invoice{}, err
+	}
+	return saved, nil
+}
+
+// ApplyStatusTransition moves the invoice to input.ToStatus and records the
+// transition. Invoices not yet stored locally are created with the reported
+// status. The returned bool is false when the invoice already had that status.
+func (r *PaymentRepository) ApplyStatusTransition(ctx context.Context, input paymentdomain.TransitionInput) (paymentdomain.Invoice, bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return paymentdomain.Invoice{}, false, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	snapshot := input.Invoice
+	snapshot.Status = input.ToStatus
+
+	insertQuery := `
+		INSERT INTO invoices (
+			xendit_invoice_id, external_id, amount, paid_amount, currency, status, payer_email,
+			description, invoice_url, payment_method, payment_channel, expires_at, paid_at
+		)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''),
+			NULLIF($10, ''), NULLIF($11, ''), $12, $13)
+		ON CONFLICT (xendit_invoice_id) DO NOTHING
+		RETURNING ` + invoiceColumns
+
+	created, err := scanInvoice(tx.QueryRow(ctx, insertQuery, invoiceArgs(snapshot)...))
+	if err == nil {
+		if err := insertStatusTransition(ctx, tx, created.ID, "", created.Status, input.Source, input.PayloadHash); err != nil {
+			return paymentdomain.Invoice{}, false, err
+		}
+		if err := insertInvoiceStatusEvent(ctx, tx, created, "", input.Source); err != nil {
+			return paymentdomain.Invoice{}, false, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return paymentdomain.Invoice{}, false, err
+		}
+		return created, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return paymentdomain.Invoice{}, false, mapPaymentError(err)
+	}
+
+	selectQuery := `SELECT ` + invoiceColumns + ` FROM invoices WHERE xendit_invoice_id = $1 FOR UPDATE`
+	current, err := scanInvoice(tx.QueryRow(ctx, selectQuery, snapshot.XenditInvoiceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return paymentdomain.Invoice{}, false, paymentdomain.ErrNotFound
+		}
+		return paymentdomain.Invoice{}, false, mapPaymentError(err)
+	}
+
+	if current.Status == input.ToStatus {
+		return current, false, nil
+	}
+	if !current.Status.CanTransitionTo(input.ToStatus) {
+		return current, false, paymentdomain.ErrInvalidTransition
+	}
+
+	updateQuery := `
+		UPDATE invoices
+		SET status = $2,
+			paid_amount = COALESCE($3, paid_amount),
+			paid_at = COALESCE($4, paid_at),
+			payment_method = COALESCE(NULLIF($5, ''), payment_method),
+			payment_channel = COALESCE(NULLIF($6, ''), payment_channel),
+			updated_at = now()
+		WHERE id = $1
+		RETURNING ` + invoiceColumns
+
+	updated, err := scanInvoice(tx.QueryRow(ctx, updateQuery, current.ID, string(input.ToStatus), snapshot.PaidAmount, snapshot.PaidAt, snapshot.PaymentMethod, snapshot.PaymentChannel))
+	if err != nil {
+		return paymentdomain.Invoice{}, false, mapPaymentError(err)
+	}
+
+	if err := insertStatusTransition(ctx, tx, updated.ID, current.Status, updated.Status, input.Source, input.PayloadHash); err != nil {
+		return paymentdomain.Invoice{}, false, err
+	}
+	if err := insertInvoiceStatusEvent(ctx, tx, updated, current.Status, input.Source); err != nil {
+		return paymentdomain.Invoice{}, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return paymentdomain.Invoice{}, false, err
+	}
+	return updated, true, nil
+}
+
+// insertInvoiceStatusEvent publishes the transition through the outbox. It is
+// built here rather than by the service because only the locked row knows the
+// status being left.
+func insertInvoiceStatusEvent(ctx context.Context, tx pgx.Tx, invoice paymentdomain.Invoice, from paymentdomain.InvoiceStatus, source string) error {
+	event, err := eventdomain.New(eventdomain.TypeInvoiceStatusChanged, eventdomain.AggregateInvoice, invoice.ID, eventdomain.InvoiceStatusChanged{
+		InvoiceID:       invoice.ID,
+		XenditInvoiceID: invoice.XenditInvoiceID,
+		ExternalID:      invoice.ExternalID,
+		FromStatus:      string(from),
+		ToStatus:        string(invoice.Status),
+		Amount:          invoice.Amount,
+		Source:          source,
+	})
+	if err != nil {
+		return err
+	}
+	return insertOutboxEvents(ctx, tx, []eventdomain.Event{event})
+}
+
+func (r *PaymentRepository) GetInvoice(ctx context.Context, xenditInvoiceID string) (paymentdomain.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoices WHERE xendit_invoice_id = $1`
+
+	invoice, err := scanInvoice(r.pool.QueryRow(ctx, query, xenditInvoiceID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return paymentdomain.Invoice{}, paymentdomain.ErrNotFound
+		}
+		return paymentdomain.Invoice{}, mapPaymentError(err)
+	}
+	return invoice, nil
+}
+
+func (r *PaymentRepository) ListInvoicesByExternalID(ctx context.Context, externalID string) ([]paymentdomain.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoices WHERE external_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.pool.Query(ctx, query, externalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := make([]paymentdomain.Invoice, 0)
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (r *PaymentRepository) ListStatusTransitions(ctx context.Context, invoiceID string) ([]paymentdomain.StatusTransition, error) {
+	const query = `
+		SELECT id::text, invoice_id::text, COALESCE(from_status, ''), to_status, source,
+			COALESCE(payload_hash, ''), created_at
+		FROM invoice_status_transitions
+		WHERE invoice_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.pool.Query(ctx, query, invoiceID)
+	if err != nil {
+		return nil, mapPaymentError(err)
+	}
+	defer rows.Close()
+
+	transitions := make([]paymentdomain.StatusTransition, 0)
+	for rows.Next() {
+		var transition paymentdomain.StatusTransition
+		var fromStatus, toStatus string
+		if err := rows.Scan(&transition.ID, &transition.InvoiceID, &fromStatus, &toStatus, &transition.Source, &transition.PayloadHash, &transition.CreatedAt); err != nil {
+			return nil, err
+		}
+		transition.FromStatus = paymentdomain.InvoiceStatus(fromStatus)
+		transition.ToStatus = paymentdomain.InvoiceStatus(toStatus)
+		transitions = append(transitions, transition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+func insertStatusTransition(ctx context.Context, tx pgx.Tx, invoiceID string, from, to paymentdomain.InvoiceStatus, source, payloadHash string) error {
+	const query = `
+		INSERT INTO invoice_status_transitions (invoice_id, from_status, to_status, source, payload_hash)
+		VALUES ($1, NULLIF($2, ''), $3, $4, NULLIF($5, ''))
+		ON CONFLICT (invoice_id, payload_hash) WHERE payload_hash IS NOT NULL DO NOTHING
+	`
+
+	_, err := tx.Exec(ctx, query, invoiceID, string(from), string(to), source, payloadHash)
+	return mapPaymentError(err)
+}
+
+func invoiceArgs(invoice paymentdomain.Invoice) []any {
+	return []any{
+		invoice.XenditInvoiceID,
+		invoice.ExternalID,
+		invoice.Amount,
+		invoice.PaidAmount,
+		invoice.Currency,
+		string(invoice.Status),
+		invoice.PayerEmail,
+		invoice.Description,
+		invoice.InvoiceURL,
+		invoice.PaymentMethod,
+		invoice.PaymentChannel,
+		invoice.ExpiresAt,
+		invoice.PaidAt,
+	}
+}
+
+func scanInvoice(row pgx.Row, extra ...any) (paymentdomain.Invoice, error) {
+	var invoice paymentdomain.Invoice
+	var status string
+	dest := []any{
+		&invoice.ID,
+		&invoice.XenditInvoiceID,
+		&invoice.ExternalID,
+		&invoice.Amount,
+		&invoice.PaidAmount,
+		&invoice.Currency,
+		&status,
+		&invoice.PayerEmail,
+		&invoice.Description,
+		&invoice.InvoiceURL,
+		&invoice.PaymentMethod,
+		&invoice.PaymentChannel,
+		&invoice.ExpiresAt,
+		&invoice.PaidAt,
+		&invoice.CreatedAt,
+		&invoice.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return paymentdomain.Invoice{}, err
+	}
+	invoice.Status = paymentdomain.InvoiceStatus(status)
+	return invoice, nil
+}
+
+func mapPaymentError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503", "22P02", "23514":
+			return paymentdomain.ErrInvalidInput
+		}
+	}
+	return err
+}
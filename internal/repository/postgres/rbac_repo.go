@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
 	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 )
 
@@ -43,7 +45,7 @@ func (r *RBACRepository) ListRoles(ctx context.Context, filter rbacdomain.ListFi
 	}
 
 	listQuery := fmt.Sprintf(`
-		SELECT id::text, name, COALESCE(description, ''), created_at
+		SELECT id::text, name, COALESCE(description, ''), created_at, is_system
 		FROM roles
 		%s
 		ORDER BY name
@@ -60,7 +62,7 @@ func (r *RBACRepository) ListRoles(ctx context.Context, filter rbacdomain.ListFi
 	var roles []rbacdomain.Role
 	for rows.Next() {
 		var role rbacdomain.Role
-		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt); err != nil {
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.IsSystem); err != nil {
 			return rbacdomain.ListRole{}, err
 		}
 		roles = append(roles, role)
@@ -102,13 +104,13 @@ func buildRolesListFilter(filter rbacdomain.ListFilterRole) (string, []any) {
 
 func (r *RBACRepository) GetRole(ctx context.Context, id string) (rbacdomain.Role, error) {
 	const query = `
-		SELECT id::text, name, COALESCE(description, ''), created_at
+		SELECT id::text, name, COALESCE(description, ''), created_at, is_system
 		FROM roles
 		WHERE id = $1
 	`
 
 	var role rbacdomain.Role
-	err := r.pool.QueryRow(ctx, query, id).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt)
+	err := r.pool.QueryRow(ctx, query, id).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.IsSystem)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return rbacdomain.Role{}, rbacdomain.ErrNotFound
@@ -122,12 +124,48 @@ func (r *RBACRepository) CreateRole(ctx context.Context, name, description strin
 	const query = `
 		INSERT INTO roles (name, description)
 		VALUES ($1, $2)
-		RETURNING id::text, name, COALESCE(description, ''), created_at
+		RETURNING id::text, name, COALESCE(description, ''), created_at, is_system
 	`
 
 	var role rbacdomain.Role
 	desc := normalizeDescription(description)
-	err := r.pool.QueryRow(ctx, query, name, desc).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt)
+	err := r.pool.QueryRow(ctx, query, name, desc).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.IsSystem)
+	if err != nil {
+		return rbacdomain.Role{}, mapRBACError(err)
+	}
+	return role, nil
+}
+
+func (r *RBACRepository) GetRoleByName(ctx context.Context, name string) (rbacdomain.Role, error) {
+	const query = `
+		SELECT id::text, name, COALESCE(description, ''), created_at, is_system
+		FROM roles
+		WHERE name = $1
+	`
+
+	var role rbacdomain.Role
+	err := r.pool.QueryRow(ctx, query, name).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.IsSystem)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rbacdomain.Role{}, rbacdomain.ErrNotFound
+		}
+		return rbacdomain.Role{}, mapRBACError(err)
+	}
+	return role, nil
+}
+
+// EnsureRole creates the role if it does not exist and returns it either way.
+func (r *RBACRepository) EnsureRole(ctx context.Context, name, description string) (rbacdomain.Role, error) {
+	const query = `
+		INSERT INTO roles (name, description)
+		VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id::text, name, COALESCE(description, ''), created_at, is_system
+	`
+
+	var role rbacdomain.Role
+	desc := normalizeDescription(description)
+	err := r.pool.QueryRow(ctx, query, name, desc).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.IsSystem)
 	if err != nil {
 		return rbacdomain.Role{}, mapRBACError(err)
 	}
@@ -139,12 +177,12 @@ func (r *RBACRepository) UpdateRole(ctx context.Context, id, name, description s
 		UPDATE roles
 		SET name = $2, description = $3
 		WHERE id = $1
-		RETURNING id::text, name, COALESCE(description, ''), created_at
+		RETURNING id::text, name, COALESCE(description, ''), created_at, is_system
 	`
 
 	var role rbacdomain.Role
 	desc := normalizeDescription(description)
-	err := r.pool.QueryRow(ctx, query, id, name, desc).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt)
+	err := r.pool.QueryRow(ctx, query, id, name, desc).Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.IsSystem)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return rbacdomain.Role{}, rbacdomain.ErrNotFound
@@ -193,7 +231,7 @@ func (r *RBACRepository) ListPermissions(ctx context.Context, filter rbacdomain.
 	}
 
 	listQuery := fmt.Sprintf(`
-		SELECT id::text, name, COALESCE(description, ''), created_at
+		SELECT id::text, name, COALESCE(description, ''), created_at, is_system
 		FROM permissions
 		%s
 		ORDER BY name
@@ -210,7 +248,7 @@ func (r *RBACRepository) ListPermissions(ctx context.Context, filter rbacdomain.
 	var permissions []rbacdomain.Permission
 	for rows.Next() {
 		var permission rbacdomain.Permission
-		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt); err != nil {
+		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt, &permission.IsSystem); err != nil {
 			return rbacdomain.ListPermission{}, err
 		}
 		permissions = append(permissions, permission)
@@ -252,13 +290,13 @@ func buildPermissionFilters(filter rbacdomain.ListFilterPermission) (string, []a
 
 func (r *RBACRepository) GetPermission(ctx context.Context, id string) (rbacdomain.Permission, error) {
 	const query = `
-		SELECT id::text, name, COALESCE(description, ''), created_at
+		SELECT id::text, name, COALESCE(description, ''), created_at, is_system
 		FROM permissions
 		WHERE id = $1
 	`
 
 	var permission rbacdomain.Permission
-	err := r.pool.QueryRow(ctx, query, id).Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt)
+	err := r.pool.QueryRow(ctx, query, id).Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt, &permission.IsSystem)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return rbacdomain.Permission{}, rbacdomain.ErrNotFound
@@ -272,12 +310,12 @@ func (r *RBACRepository) CreatePermission(ctx context.Context, name, description
 	const query = `
 		INSERT INTO permissions (name, description)
 		VALUES ($1, $2)
-		RETURNING id::text, name, COALESCE(description, ''), created_at
+		RETURNING id::text, name, COALESCE(description, ''), created_at, is_system
 	`
 
 	var permission rbacdomain.Permission
 	desc := normalizeDescription(description)
-	err := r.pool.QueryRow(ctx, query, name, desc).Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt)
+	err := r.pool.QueryRow(ctx, query, name, desc).Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt, &permission.IsSystem)
 	if err != nil {
 		return rbacdomain.Permission{}, mapRBACError(err)
 	}
@@ -289,12 +327,12 @@ func (r *RBACRepository) UpdatePermission(ctx context.Context, id, name, descrip
 		UPDATE permissions
 		SET name = $2, description = $3
 		WHERE id = $1
-		RETURNING id::text, name, COALESCE(description, ''), created_at
+		RETURNING id::text, name, COALESCE(description, ''), created_at, is_system
 	`
 
 	var permission rbacdomain.Permission
 	desc := normalizeDescription(description)
-	err := r.pool.QueryRow(ctx, query, id, name, desc).Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt)
+	err := r.pool.QueryRow(ctx, query, id, name, desc).Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt, &permission.IsSystem)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return rbacdomain.Permission{}, rbacdomain.ErrNotFound
@@ -320,13 +358,47 @@ func (r *RBACRepository) DeletePermission(ctx context.Context, id string) error
 	return nil
 }
 
+// GetPermissionsByIDs returns the permissions with the given IDs; unknown
+// IDs are skipped.
+func (r *RBACRepository) GetPermissionsByIDs(ctx context.Context, ids []string) ([]rbacdomain.Permission, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	const query = `
+		SELECT id::text, name, COALESCE(description, ''), created_at, is_system
+		FROM permissions
+		WHERE id = ANY($1::uuid[])
+		ORDER BY name
+	`
+
+	rows, err := r.pool.Query(ctx, query, ids)
+	if err != nil {
+		return nil, mapRBACError(err)
+	}
+	defer rows.Close()
+
+	var permissions []rbacdomain.Permission
+	for rows.Next() {
+		var permission rbacdomain.Permission
+		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt, &permission.IsSystem); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapRBACError(err)
+	}
+	return permissions, nil
+}
+
 func (r *RBACRepository) ListRolePermissions(ctx context.Context, roleID string) ([]rbacdomain.Permission, error) {
 	if err := r.ensureRoleExists(ctx, roleID); err != nil {
 		return nil, err
 	}
 
 	const query = `
-		SELECT p.id::text, p.name, COALESCE(p.description, ''), p.created_at
+		SELECT p.id::text, p.name, COALESCE(p.description, ''), p.created_at, p.is_system
 		FROM permissions p
 		JOIN role_permissions rp ON rp.permission_id = p.id
 		WHERE rp.role_id = $1
@@ -342,7 +414,7 @@ func (r *RBACRepository) ListRolePermissions(ctx context.Context, roleID string)
 	var permissions []rbacdomain.Permission
 	for rows.Next() {
 		var permission rbacdomain.Permission
-		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt); err != nil {
+		if err := rows.Scan(&permission.ID, &permission.Name, &permission.Description, &permission.CreatedAt, &permission.IsSystem); err != nil {
 			return nil, err
 		}
 		permissions = append(permissions, permission)
@@ -350,7 +422,9 @@ func (r *RBACRepository) ListRolePermissions(ctx context.Context, roleID string)
 	return permissions, rows.Err()
 }
 
-func (r *RBACRepository) ReplaceRolePermissions(ctx context.Context, roleID string, permissionIDs []string) error {
+// ReplaceRolePermissions swaps the role's permissions and, in the same
+// transaction, stores any events describing the change.
+func (r *RBACRepository) ReplaceRolePermissions(ctx context.Context, roleID string, permissionIDs []string, events ...eventdomain.Event) error {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -393,6 +467,607 @@ func (r *RBACRepository) ReplaceRolePermissions(ctx context.Context, roleID stri
 		}
 	}
 
+	if err := insertOutboxEvents(ctx, tx, events); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// GrantAllPermissions adds every existing permission to the role and returns
+// how many were newly granted.
+func (r *RBACRepository) GrantAllPermissions(ctx context.Context, roleID string) (int64, error) {
+	if err := r.ensureRoleExists(ctx, roleID); err != nil {
+		return 0, err
+	}
+
+	const query = `
+		INSERT INTO role_permissions (role_id, permission_id)
+		SELECT $1, p.id
+		FROM permissions p
+		ON CONFLICT DO NOTHING
+	`
+
+	tag, err := r.pool.Exec(ctx, query, roleID)
+	if err != nil {
+		return 0, mapRBACError(err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *RBACRepository) ListRoleParents(ctx context.Context, roleID string) ([]rbacdomain.Role, error) {
+	if err := r.ensureRoleExists(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT r.id::text, r.name, COALESCE(r.description, ''), r.created_at, r.is_system
+		FROM roles r
+		JOIN role_parents rp ON rp.parent_role_id = r.id
+		WHERE rp.role_id = $1
+		ORDER BY r.name
+	`
+
+	rows, err := r.pool.Query(ctx, query, roleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []rbacdomain.Role
+	for rows.Next() {
+		var role rbacdomain.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.IsSystem); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+// ListRoleHierarchy returns every parent edge so callers can validate a
+// change against the whole graph.
+func (r *RBACRepository) ListRoleHierarchy(ctx context.Context) ([]rbacdomain.RoleParent, error) {
+	rows, err := r.pool.Query(ctx, `SELECT role_id::text, parent_role_id::text FROM role_parents`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edges []rbacdomain.RoleParent
+	for rows.Next() {
+		var edge rbacdomain.RoleParent
+		if err := rows.Scan(&edge.RoleID, &edge.ParentRoleID); err != nil {
+			return nil, err
+		}
+		edges = append(edges, edge)
+	}
+	return edges, rows.Err()
+}
+
+// ListApprovalRoleIDs returns the ids of roles flagged requires_approval.
+func (r *RBACRepository) ListApprovalRoleIDs(ctx context.Context) ([]string, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id::text FROM roles WHERE requires_approval ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *RBACRepository) ReplaceRoleParents(ctx context.Context, roleID string, parentIDs []string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := ensureRoleExistsTx(ctx, tx, roleID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM role_parents WHERE role_id = $1`, roleID); err != nil {
+		return mapRBACError(err)
+	}
+
+	if len(parentIDs) > 0 {
+		const query = `
+			INSERT INTO role_parents (role_id, parent_role_id)
+			SELECT $1, unnest($2::uuid[])
+			ON CONFLICT DO NOTHING
+		`
+		if _, err := tx.Exec(ctx, query, roleID, parentIDs); err != nil {
+			return mapRBACError(err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetRoleConstraints returns the approval flag of roleID and the roles it
+// excludes, whichever side of the pair they were stored on.
+func (r *RBACRepository) GetRoleConstraints(ctx context.Context, roleID string) (rbacdomain.RoleConstraints, error) {
+	var constraints rbacdomain.RoleConstraints
+	err := r.pool.QueryRow(ctx, `SELECT requires_approval FROM roles WHERE id = $1`, roleID).Scan(&constraints.RequiresApproval)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rbacdomain.RoleConstraints{}, rbacdomain.ErrNotFound
+		}
+		return rbacdomain.RoleConstraints{}, mapRBACError(err)
+	}
+
+	const query = `
+		SELECT r.id::text, r.name, COALESCE(r.description, ''), r.created_at, r.is_system
+		FROM role_exclusions e
+		JOIN roles r ON r.id = CASE WHEN e.role_id = $1 THEN e.excluded_role_id ELSE e.role_id END
+		WHERE e.role_id = $1 OR e.excluded_role_id = $1
+		ORDER BY r.name
+	`
+	rows, err := r.pool.Query(ctx, query, roleID)
+	if err != nil {
+		return rbacdomain.RoleConstraints{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role rbacdomain.Role
+		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt, &role.IsSystem); err != nil {
+			return rbacdomain.RoleConstraints{}, err
+		}
+		constraints.ExcludedRoles = append(constraints.ExcludedRoles, role)
+	}
+	return constraints, rows.Err()
+}
+
+// ReplaceRoleConstraints sets the approval flag of roleID and replaces the
+// roles it excludes. It returns ErrExclusionHeld if some user already holds
+// roleID together with one of excludedIDs.
+func (r *RBACRepository) ReplaceRoleConstraints(ctx context.Context, roleID string, requiresApproval bool, excludedIDs []string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	tag, err := tx.Exec(ctx, `UPDATE roles SET requires_approval = $2 WHERE id = $1`, roleID, requiresApproval)
+	if err != nil {
+		return mapRBACError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return rbacdomain.ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM role_exclusions WHERE role_id = $1 OR excluded_role_id = $1`, roleID); err != nil {
+		return mapRBACError(err)
+	}
+
+	if len(excludedIDs) > 0 {
+		const insertQuery = `
+			INSERT INTO role_exclusions (role_id, excluded_role_id)
+			SELECT LEAST($1::uuid, other), GREATEST($1::uuid, other)
+			FROM unnest($2::uuid[]) AS other
+			ON CONFLICT DO NOTHING
+		`
+		if _, err := tx.Exec(ctx, insertQuery, roleID, excludedIDs); err != nil {
+			return mapRBACError(err)
+		}
+
+		const heldQuery = `
+			SELECT EXISTS (
+				SELECT 1
+				FROM user_roles a
+				JOIN user_roles b ON b.user_id = a.user_id
+				WHERE a.role_id = $1 AND b.role_id = ANY($2::uuid[])
+			)
+		`
+		var held bool
+		if err := tx.QueryRow(ctx, heldQuery, roleID, excludedIDs).Scan(&held); err != nil {
+			return mapRBACError(err)
+		}
+		if held {
+			return rbacdomain.ErrExclusionHeld
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListPermissionNames returns the name of every stored permission, sorted.
+func (r *RBACRepository) ListPermissionNames(ctx context.Context) ([]string, error) {
+	rows, err := r.pool.Query(ctx, `SELECT name FROM permissions ORDER BY name`)
+	if err != nil {
+		return nil, mapRBACError(err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// EnsureSystemPermissions inserts the missing permissions in names, marks all
+// of them as system permissions and returns the names that were inserted.
+func (r *RBACRepository) EnsureSystemPermissions(ctx context.Context, names []string) ([]string, error) {
+	const query = `
+		INSERT INTO permissions (name, is_system)
+		SELECT unnest($1::text[]), true
+		ON CONFLICT (name) DO UPDATE SET is_system = true
+		RETURNING name, xmax = 0
+	`
+
+	rows, err := r.pool.Query(ctx, query, names)
+	if err != nil {
+		return nil, mapRBACError(err)
+	}
+	defer rows.Close()
+
+	var created []string
+	for rows.Next() {
+		var name string
+		var inserted bool
+		if err := rows.Scan(&name, &inserted); err != nil {
+			return nil, err
+		}
+		if inserted {
+			created = append(created, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapRBACError(err)
+	}
+	sort.Strings(created)
+	return created, nil
+}
+
+// ExportPolicy reads every role, permission, grant and parent edge from a
+// single snapshot.
+func (r *RBACRepository) ExportPolicy(ctx context.Context) (rbacdomain.Policy, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return rbacdomain.Policy{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var policy rbacdomain.Policy
+	rows, err := tx.Query(ctx, `SELECT name, COALESCE(description, ''), is_system FROM permissions ORDER BY name`)
+	if err != nil {
+		return rbacdomain.Policy{}, mapRBACError(err)
+	}
+	for rows.Next() {
+		var permission rbacdomain.PolicyPermission
+		if err := rows.Scan(&permission.Name, &permission.Description, &permission.System); err != nil {
+			rows.Close()
+			return rbacdomain.Policy{}, err
+		}
+		policy.Permissions = append(policy.Permissions, permission)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return rbacdomain.Policy{}, mapRBACError(err)
+	}
+
+	index := make(map[string]int)
+	rows, err = tx.Query(ctx, `SELECT name, COALESCE(description, ''), is_system FROM roles ORDER BY name`)
+	if err != nil {
+		return rbacdomain.Policy{}, mapRBACError(err)
+	}
+	for rows.Next() {
+		role := rbacdomain.PolicyRole{Permissions: []string{}}
+		if err := rows.Scan(&role.Name, &role.Description, &role.System); err != nil {
+			rows.Close()
+			return rbacdomain.Policy{}, err
+		}
+		index[role.Name] = len(policy.Roles)
+		policy.Roles = append(policy.Roles, role)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return rbacdomain.Policy{}, mapRBACError(err)
+	}
+
+	const grantsQuery = `
+		SELECT r.name, p.name
+		FROM role_permissions rp
+		JOIN roles r ON r.id = rp.role_id
+		JOIN permissions p ON p.id = rp.permission_id
+		ORDER BY r.name, p.name
+	`
+	rows, err = tx.Query(ctx, grantsQuery)
+	if err != nil {
+		return rbacdomain.Policy{}, mapRBACError(err)
+	}
+	for rows.Next() {
+		var roleName, permissionName string
+		if err := rows.Scan(&roleName, &permissionName); err != nil {
+			rows.Close()
+			return rbacdomain.Policy{}, err
+		}
+		if i, ok := index[roleName]; ok {
+			policy.Roles[i].Permissions = append(policy.Roles[i].Permissions, permissionName)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return rbacdomain.Policy{}, mapRBACError(err)
+	}
+
+	const parentsQuery = `
+		SELECT r.name, parent.name
+		FROM role_parents rp
+		JOIN roles r ON r.id = rp.role_id
+		JOIN roles parent ON parent.id = rp.parent_role_id
+		ORDER BY r.name, parent.name
+	`
+	rows, err = tx.Query(ctx, parentsQuery)
+	if err != nil {
+		return rbacdomain.Policy{}, mapRBACError(err)
+	}
+	for rows.Next() {
+		var roleName, parentName string
+		if err := rows.Scan(&roleName, &parentName); err != nil {
+			rows.Close()
+			return rbacdomain.Policy{}, err
+		}
+		if i, ok := index[roleName]; ok {
+			policy.Roles[i].Parents = append(policy.Roles[i].Parents, parentName)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return rbacdomain.Policy{}, mapRBACError(err)
+	}
+
+	return policy, nil
+}
+
+// ApplyPolicy makes the stored roles and permissions match policy in one
+// transaction: declared entries are created or have their description
+// updated, and declared roles get exactly the listed permissions and
+// parents. With prune, undeclared non-system roles and permissions are
+// deleted. The policy must already be validated.
+func (r *RBACRepository) ApplyPolicy(ctx context.Context, policy rbacdomain.Policy, prune bool) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	permissionNames := make([]string, 0, len(policy.Permissions))
+	permissionDescriptions := make([]string, 0, len(policy.Permissions))
+	for _, permission := range policy.Permissions {
+		permissionNames = append(permissionNames, permission.Name)
+		permissionDescriptions = append(permissionDescriptions, permission.Description)
+	}
+	roleNames := make([]string, 0, len(policy.Roles))
+	roleDescriptions := make([]string, 0, len(policy.Roles))
+	var grantRoles, grantPermissions, parentRoles, parentNames []string
+	for _, role := range policy.Roles {
+		roleNames = append(roleNames, role.Name)
+		roleDescriptions = append(roleDescriptions, role.Description)
+		for _, permission := range role.Permissions {
+			grantRoles = append(grantRoles, role.Name)
+			grantPermissions = append(grantPermissions, permission)
+		}
+		for _, parent := range role.Parents {
+			parentRoles = append(parentRoles, role.Name)
+			parentNames = append(parentNames, parent)
+		}
+	}
+
+	const upsertPermissions = `
+		INSERT INTO permissions (name, description)
+		SELECT name, NULLIF(description, '')
+		FROM unnest($1::text[], $2::text[]) AS t(name, description)
+		ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description
+	`
+	if _, err := tx.Exec(ctx, upsertPermissions, permissionNames, permissionDescriptions); err != nil {
+		return mapRBACError(err)
+	}
+
+	const upsertRoles = `
+		INSERT INTO roles (name, description)
+		SELECT name, NULLIF(description, '')
+		FROM unnest($1::text[], $2::text[]) AS t(name, description)
+		ON CONFLICT (name) DO UPDATE SET description = EXCLUDED.description
+	`
+	if _, err := tx.Exec(ctx, upsertRoles, roleNames, roleDescriptions); err != nil {
+		return mapRBACError(err)
+	}
+
+	if prune {
+		if _, err := tx.Exec(ctx, `DELETE FROM roles WHERE NOT is_system AND name <> ALL($1::text[])`, roleNames); err != nil {
+			return mapRBACError(err)
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM permissions WHERE NOT is_system AND name <> ALL($1::text[])`, permissionNames); err != nil {
+			return mapRBACError(err)
+		}
+	}
+
+	const clearGrants = `
+		DELETE FROM role_permissions
+		WHERE role_id IN (SELECT id FROM roles WHERE name = ANY($1::text[]))
+	`
+	if _, err := tx.Exec(ctx, clearGrants, roleNames); err != nil {
+		return mapRBACError(err)
+	}
+	const insertGrants = `
+		INSERT INTO role_permissions (role_id, permission_id)
+		SELECT r.id, p.id
+		FROM unnest($1::text[], $2::text[]) AS g(role_name, permission_name)
+		JOIN roles r ON r.name = g.role_name
+		JOIN permissions p ON p.name = g.permission_name
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, insertGrants, grantRoles, grantPermissions); err != nil {
+		return mapRBACError(err)
+	}
+
+	const clearParents = `
+		DELETE FROM role_parents
+		WHERE role_id IN (SELECT id FROM roles WHERE name = ANY($1::text[]))
+	`
+	if _, err := tx.Exec(ctx, clearParents, roleNames); err != nil {
+		return mapRBACError(err)
+	}
+	const insertParents = `
+		INSERT INTO role_parents (role_id, parent_role_id)
+		SELECT r.id, parent.id
+		FROM unnest($1::text[], $2::text[]) AS e(role_name, parent_name)
+		JOIN roles r ON r.name = e.role_name
+		JOIN roles parent ON parent.name = e.parent_name
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, insertParents, parentRoles, parentNames); err != nil {
+		return mapRBACError(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListUserResourceRoles returns the roles userID holds on individual
+// resources, ordered by resource then role name.
+func (r *RBACRepository) ListUserResourceRoles(ctx context.Context, userID string) ([]rbacdomain.ResourceRole, error) {
+	if err := ensureRBACUserExists(ctx, r.pool, userID); err != nil {
+		return nil, err
+	}
+
+	const query = `
+		SELECT ur.user_id::text, r.id::text, r.name, COALESCE(r.description, ''), r.created_at, r.is_system,
+			ur.resource_type, ur.resource_id, ur.created_at
+		FROM user_roles ur
+		JOIN roles r ON r.id = ur.role_id
+		WHERE ur.user_id = $1 AND ur.resource_type <> ''
+			AND (ur.valid_until IS NULL OR ur.valid_until > now())
+		ORDER BY ur.resource_type, ur.resource_id, r.name
+	`
+
+	rows, err := r.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var assignments []rbacdomain.ResourceRole
+	for rows.Next() {
+		var assignment rbacdomain.ResourceRole
+		if err := rows.Scan(
+			&assignment.UserID,
+			&assignment.Role.ID,
+			&assignment.Role.Name,
+			&assignment.Role.Description,
+			&assignment.Role.CreatedAt,
+			&assignment.Role.IsSystem,
+			&assignment.ResourceType,
+			&assignment.ResourceID,
+			&assignment.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, rows.Err()
+}
+
+// GrantResourceRole gives userID roleID on one resource. Granting the same
+// assignment twice is a no-op. It returns ErrExclusionHeld if the user holds
+// a role excluded with roleID, globally or on any resource.
+func (r *RBACRepository) GrantResourceRole(ctx context.Context, userID, roleID, resourceType, resourceID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := ensureRBACUserExists(ctx, tx, userID); err != nil {
+		return err
+	}
+	if err := ensureRoleExistsTx(ctx, tx, roleID); err != nil {
+		return err
+	}
+
+	const excludedQuery = `
+		SELECT EXISTS (
+			SELECT 1
+			FROM user_roles ur
+			JOIN role_exclusions e
+				ON (e.role_id = $2 AND e.excluded_role_id = ur.role_id)
+				OR (e.excluded_role_id = $2 AND e.role_id = ur.role_id)
+			WHERE ur.user_id = $1 AND (ur.valid_until IS NULL OR ur.valid_until > now())
+		)
+	`
+	var excluded bool
+	if err := tx.QueryRow(ctx, excludedQuery, userID, roleID).Scan(&excluded); err != nil {
+		return mapRBACError(err)
+	}
+	if excluded {
+		return rbacdomain.ErrExclusionHeld
+	}
+
+	const query = `
+		INSERT INTO user_roles (user_id, role_id, resource_type, resource_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, query, userID, roleID, resourceType, resourceID); err != nil {
+		return mapRBACError(err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE users SET token_version = token_version + 1 WHERE id = $1`, userID); err != nil {
+		return mapRBACError(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RevokeResourceRole removes one resource-scoped assignment and invalidates
+// the user's access tokens.
+func (r *RBACRepository) RevokeResourceRole(ctx context.Context, userID, roleID, resourceType, resourceID string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	const query = `
+		DELETE FROM user_roles
+		WHERE user_id = $1 AND role_id = $2 AND resource_type = $3 AND resource_id = $4
+	`
+	tag, err := tx.Exec(ctx, query, userID, roleID, resourceType, resourceID)
+	if err != nil {
+		return mapRBACError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return rbacdomain.ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `UPDATE users SET token_version = token_version + 1 WHERE id = $1`, userID); err != nil {
+		return mapRBACError(err)
+	}
+
 	return tx.Commit(ctx)
 }
 
@@ -413,6 +1088,19 @@ func ensureRoleExistsTx(ctx context.Context, querier interface {
 	return nil
 }
 
+func ensureRBACUserExists(ctx context.Context, querier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}, userID string) error {
+	var exists bool
+	if err := querier.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists); err != nil {
+		return mapRBACError(err)
+	}
+	if !exists {
+		return rbacdomain.ErrNotFound
+	}
+	return nil
+}
+
 func normalizeDescription(value string) interface{} {
 	trimmed := strings.TrimSpace(value)
 	if trimmed == "" {
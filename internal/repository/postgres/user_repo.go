@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
 	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 	userdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/user"
 )
@@ -127,8 +129,8 @@ func (r *UserRepository) CreateUser(ctx context.Context, email, passwordHash str
 	}()
 
 	const query = `
-		INSERT INTO users (email, password_hash, is_active)
-		VALUES ($1, $2, $3)
+		INSERT INTO users (email, password_hash, is_active, email_verified_at)
+		VALUES ($1, $2, $3, now())
 		RETURNING id::text, email, password_hash, is_active, created_at, updated_at
 	`
 
@@ -138,6 +140,9 @@ func (r *UserRepository) CreateUser(ctx context.Context, email, passwordHash str
 	if err != nil {
 		return userdomain.User{}, mapUserError(err)
 	}
+	if err := recordPasswordHistory(ctx, tx, user.ID, passwordHash); err != nil {
+		return userdomain.User{}, err
+	}
 
 	if len(roleIDs) > 0 {
 		batch := &pgx.Batch{}
@@ -171,10 +176,28 @@ func (r *UserRepository) CreateUser(ctx context.Context, email, passwordHash str
 	return user, nil
 }
 
-func (r *UserRepository) UpdateUser(ctx context.Context, id, email, passwordHash string, isActive bool, bumpTokenVersion bool) (userdomain.User, error) {
+// UpdateUser stores the new values and, in the same transaction, any events
+// describing the change.
+func (r *UserRepository) UpdateUser(ctx context.Context, id, email, passwordHash string, isActive bool, bumpTokenVersion bool, events ...eventdomain.Event) (userdomain.User, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return userdomain.User{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var admins int
+	if !isActive {
+		if admins, err = lockActiveAdmins(ctx, tx); err != nil {
+			return userdomain.User{}, err
+		}
+	}
+
 	const query = `
 		UPDATE users
 		SET email = $2,
+			email_verified_at = CASE WHEN email = $2 THEN email_verified_at END,
 			password_hash = $3,
 			is_active = $4,
 			updated_at = now(),
@@ -184,7 +207,7 @@ func (r *UserRepository) UpdateUser(ctx context.Context, id, email, passwordHash
 	`
 
 	var user userdomain.User
-	err := r.pool.QueryRow(ctx, query, id, email, passwordHash, isActive, bumpTokenVersion).
+	err = tx.QueryRow(ctx, query, id, email, passwordHash, isActive, bumpTokenVersion).
 		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.IsActive, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -192,35 +215,109 @@ func (r *UserRepository) UpdateUser(ctx context.Context, id, email, passwordHash
 		}
 		return userdomain.User{}, mapUserError(err)
 	}
+	if !isActive {
+		if err := ensureActiveAdminRemains(ctx, tx, admins); err != nil {
+			return userdomain.User{}, err
+		}
+	}
+	if err := recordPasswordHistory(ctx, tx, user.ID, passwordHash); err != nil {
+		return userdomain.User{}, err
+	}
+
+	if err := insertOutboxEvents(ctx, tx, events); err != nil {
+		return userdomain.User{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return userdomain.User{}, err
+	}
 	return user, nil
 }
 
+// ListPasswordHistory returns up to limit of the user's password hashes,
+// newest first, including the current one.
+func (r *UserRepository) ListPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error) {
+	return listPasswordHistory(ctx, r.pool, userID, limit)
+}
+
+func (r *UserRepository) ResetMFA(ctx context.Context, id string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	const query = `
+		UPDATE users
+		SET mfa_totp_secret = NULL,
+			mfa_enabled_at = NULL,
+			mfa_last_used_step = NULL,
+			updated_at = now()
+		WHERE id = $1
+	`
+
+	tag, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return mapUserError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return userdomain.ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, id); err != nil {
+		return mapUserError(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
 func (r *UserRepository) DeleteUser(ctx context.Context, id string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	admins, err := lockActiveAdmins(ctx, tx)
+	if err != nil {
+		return err
+	}
+
 	const query = `
 		DELETE FROM users
 		WHERE id = $1
 	`
 
-	tag, err := r.pool.Exec(ctx, query, id)
+	tag, err := tx.Exec(ctx, query, id)
 	if err != nil {
 		return mapUserError(err)
 	}
 	if tag.RowsAffected() == 0 {
 		return userdomain.ErrNotFound
 	}
-	return nil
+	if err := ensureActiveAdminRemains(ctx, tx, admins); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (r *UserRepository) ListUserRoles(ctx context.Context, userID string) ([]rbacdomain.Role, error) {
+// ListUserRoles returns the user's global role assignments, including
+// temporary ones that have not started yet but not ones that have lapsed.
+func (r *UserRepository) ListUserRoles(ctx context.Context, userID string) ([]rbacdomain.UserRole, error) {
 	if err := r.ensureUserExists(ctx, userID); err != nil {
 		return nil, err
 	}
 
 	const query = `
-		SELECT r.id::text, r.name, COALESCE(r.description, ''), r.created_at
+		SELECT r.id::text, r.name, COALESCE(r.description, ''), r.created_at, ur.valid_from, ur.valid_until
 		FROM roles r
 		JOIN user_roles ur ON ur.role_id = r.id
-		WHERE ur.user_id = $1
+		WHERE ur.user_id = $1 AND ur.resource_type = ''
+			AND (ur.valid_until IS NULL OR ur.valid_until > now())
 		ORDER BY r.name
 	`
 
@@ -230,10 +327,17 @@ func (r *UserRepository) ListUserRoles(ctx context.Context, userID string) ([]rb
 	}
 	defer rows.Close()
 
-	var roles []rbacdomain.Role
+	var roles []rbacdomain.UserRole
 	for rows.Next() {
-		var role rbacdomain.Role
-		if err := rows.Scan(&role.ID, &role.Name, &role.Description, &role.CreatedAt); err != nil {
+		var role rbacdomain.UserRole
+		if err := rows.Scan(
+			&role.Role.ID,
+			&role.Role.Name,
+			&role.Role.Description,
+			&role.Role.CreatedAt,
+			&role.ValidFrom,
+			&role.ValidUntil,
+		); err != nil {
 			return nil, err
 		}
 		roles = append(roles, role)
@@ -241,7 +345,91 @@ func (r *UserRepository) ListUserRoles(ctx context.Context, userID string) ([]rb
 	return roles, rows.Err()
 }
 
+// GrantUserRole assigns roleID to the user between validFrom and validUntil,
+// replacing the window of an existing global assignment of the same role.
+// Existing access tokens are invalidated so a shortened grant takes effect.
+func (r *UserRepository) GrantUserRole(ctx context.Context, userID, roleID string, validFrom, validUntil *time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := ensureUserExistsTx(ctx, tx, userID); err != nil {
+		return err
+	}
+	admins, err := lockActiveAdmins(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	const query = `
+		INSERT INTO user_roles (user_id, role_id, valid_from, valid_until)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, role_id, resource_type, resource_id)
+		DO UPDATE SET valid_from = EXCLUDED.valid_from, valid_until = EXCLUDED.valid_until
+	`
+	if _, err := tx.Exec(ctx, query, userID, roleID, validFrom, validUntil); err != nil {
+		return mapUserError(err)
+	}
+	if err := ensureActiveAdminRemains(ctx, tx, admins); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE users SET token_version = token_version + 1 WHERE id = $1`, userID); err != nil {
+		return mapUserError(err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteExpiredUserRoles removes assignments whose validity window has
+// ended and bumps token_version for their users, so access tokens issued
+// while the grant was active stop working.
+func (r *UserRepository) DeleteExpiredUserRoles(ctx context.Context) ([]rbacdomain.RoleAssignment, error) {
+	const query = `
+		WITH expired AS (
+			DELETE FROM user_roles
+			WHERE valid_until IS NOT NULL AND valid_until <= now()
+			RETURNING user_id, role_id, resource_type, resource_id
+		), bumped AS (
+			UPDATE users
+			SET token_version = token_version + 1
+			WHERE id IN (SELECT user_id FROM expired)
+		)
+		SELECT user_id::text, role_id::text, resource_type, resource_id
+		FROM expired
+		ORDER BY user_id
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expired []rbacdomain.RoleAssignment
+	for rows.Next() {
+		var assignment rbacdomain.RoleAssignment
+		if err := rows.Scan(&assignment.UserID, &assignment.RoleID, &assignment.ResourceType, &assignment.ResourceID); err != nil {
+			return nil, err
+		}
+		expired = append(expired, assignment)
+	}
+	return expired, rows.Err()
+}
+
+// ReplaceUserRoles sets the user's global roles to roleIDs. Roles the user
+// already holds keep their validity window; only new roles are permanent.
 func (r *UserRepository) ReplaceUserRoles(ctx context.Context, userID string, roleIDs []string) error {
+	keep := make([]string, 0, len(roleIDs))
+	for _, roleID := range roleIDs {
+		if strings.TrimSpace(roleID) != "" {
+			keep = append(keep, roleID)
+		}
+	}
+
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -253,27 +441,29 @@ func (r *UserRepository) ReplaceUserRoles(ctx context.Context, userID string, ro
 	if err := ensureUserExistsTx(ctx, tx, userID); err != nil {
 		return err
 	}
+	admins, err := lockActiveAdmins(ctx, tx)
+	if err != nil {
+		return err
+	}
 
-	if _, err := tx.Exec(ctx, `DELETE FROM user_roles WHERE user_id = $1`, userID); err != nil {
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM user_roles
+		WHERE user_id = $1 AND resource_type = '' AND NOT (role_id = ANY($2::uuid[]))
+	`, userID, keep); err != nil {
 		return mapUserError(err)
 	}
 
-	if len(roleIDs) > 0 {
+	if len(keep) > 0 {
 		batch := &pgx.Batch{}
-		queued := 0
-		for _, roleID := range roleIDs {
-			if strings.TrimSpace(roleID) == "" {
-				continue
-			}
+		for _, roleID := range keep {
 			batch.Queue(
 				`INSERT INTO user_roles (user_id, role_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
 				userID,
 				roleID,
 			)
-			queued++
 		}
 		results := tx.SendBatch(ctx, batch)
-		for i := 0; i < queued; i++ {
+		for i := 0; i < len(keep); i++ {
 			if _, err := results.Exec(); err != nil {
 				_ = results.Close()
 				return mapUserError(err)
@@ -283,6 +473,9 @@ func (r *UserRepository) ReplaceUserRoles(ctx context.Context, userID string, ro
 			return mapUserError(err)
 		}
 	}
+	if err := ensureActiveAdminRemains(ctx, tx, admins); err != nil {
+		return err
+	}
 
 	if _, err := tx.Exec(ctx, `UPDATE users SET token_version = token_version + 1 WHERE id = $1`, userID); err != nil {
 		return mapUserError(err)
@@ -291,6 +484,275 @@ func (r *UserRepository) ReplaceUserRoles(ctx context.Context, userID string, ro
 	return tx.Commit(ctx)
 }
 
+// HasExclusiveRoles reports whether roleIDs, together with the roles userID
+// holds on individual resources, contain both roles of a mutually exclusive
+// pair. userID may be empty for a user that does not exist yet.
+func (r *UserRepository) HasExclusiveRoles(ctx context.Context, userID string, roleIDs []string) (bool, error) {
+	if len(roleIDs) == 0 {
+		return false, nil
+	}
+
+	const query = `
+		WITH held AS (
+			SELECT unnest($1::uuid[]) AS role_id
+			UNION
+			SELECT role_id
+			FROM user_roles
+			WHERE user_id = NULLIF($2, '')::uuid AND resource_type <> ''
+				AND (valid_until IS NULL OR valid_until > now())
+		)
+		SELECT EXISTS (
+			SELECT 1
+			FROM role_exclusions
+			WHERE role_id IN (SELECT role_id FROM held) AND excluded_role_id IN (SELECT role_id FROM held)
+		)
+	`
+	var exists bool
+	if err := r.pool.QueryRow(ctx, query, roleIDs, userID).Scan(&exists); err != nil {
+		return false, mapUserError(err)
+	}
+	return exists, nil
+}
+
+// ListApprovalRoles returns the IDs among roleIDs of roles whose grants need
+// approval.
+func (r *UserRepository) ListApprovalRoles(ctx context.Context, roleIDs []string) ([]string, error) {
+	if len(roleIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT id::text FROM roles WHERE id = ANY($1::uuid[]) AND requires_approval ORDER BY id`, roleIDs)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+const roleGrantRequestColumns = `
+	q.id::text, q.user_id::text, q.role_id::text, r.name, q.valid_from, q.valid_until,
+	COALESCE(q.requested_by::text, ''), q.status, COALESCE(q.decided_by::text, ''), q.decided_at, q.created_at
+`
+
+func scanRoleGrantRequest(row pgx.Row) (userdomain.RoleGrantRequest, error) {
+	var request userdomain.RoleGrantRequest
+	err := row.Scan(
+		&request.ID,
+		&request.UserID,
+		&request.RoleID,
+		&request.RoleName,
+		&request.ValidFrom,
+		&request.ValidUntil,
+		&request.RequestedBy,
+		&request.Status,
+		&request.DecidedBy,
+		&request.DecidedAt,
+		&request.CreatedAt,
+	)
+	return request, err
+}
+
+// CreateRoleGrantRequest stores a pending grant. A pending request for the
+// same user and role is replaced rather than duplicated.
+func (r *UserRepository) CreateRoleGrantRequest(ctx context.Context, request userdomain.RoleGrantRequest) (userdomain.RoleGrantRequest, error) {
+	query := `
+		WITH q AS (
+			INSERT INTO role_grant_requests (user_id, role_id, valid_from, valid_until, requested_by)
+			VALUES ($1, $2, $3, $4, NULLIF($5, '')::uuid)
+			ON CONFLICT (user_id, role_id) WHERE status = 'pending'
+			DO UPDATE SET valid_from = EXCLUDED.valid_from,
+				valid_until = EXCLUDED.valid_until,
+				requested_by = EXCLUDED.requested_by,
+				created_at = now()
+			RETURNING *
+		)
+		SELECT ` + roleGrantRequestColumns + `
+		FROM q
+		JOIN roles r ON r.id = q.role_id
+	`
+
+	created, err := scanRoleGrantRequest(r.pool.QueryRow(ctx, query,
+		request.UserID,
+		request.RoleID,
+		request.ValidFrom,
+		request.ValidUntil,
+		request.RequestedBy,
+	))
+	if err != nil {
+		return userdomain.RoleGrantRequest{}, mapUserError(err)
+	}
+	return created, nil
+}
+
+// ListRoleGrantRequests returns requests with the given status, newest
+// first; an empty status returns all of them.
+func (r *UserRepository) ListRoleGrantRequests(ctx context.Context, status string) ([]userdomain.RoleGrantRequest, error) {
+	query := `
+		SELECT ` + roleGrantRequestColumns + `
+		FROM role_grant_requests q
+		JOIN roles r ON r.id = q.role_id
+		WHERE $1 = '' OR q.status = $1
+		ORDER BY q.created_at DESC, q.id
+	`
+
+	rows, err := r.pool.Query(ctx, query, status)
+	if err != nil {
+		return nil, mapUserError(err)
+	}
+	defer rows.Close()
+
+	var requests []userdomain.RoleGrantRequest
+	for rows.Next() {
+		request, err := scanRoleGrantRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
+
+func (r *UserRepository) GetRoleGrantRequest(ctx context.Context, id string) (userdomain.RoleGrantRequest, error) {
+	query := `
+		SELECT ` + roleGrantRequestColumns + `
+		FROM role_grant_requests q
+		JOIN roles r ON r.id = q.role_id
+		WHERE q.id = $1
+	`
+
+	request, err := scanRoleGrantRequest(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return userdomain.RoleGrantRequest{}, userdomain.ErrRequestNotFound
+		}
+		return userdomain.RoleGrantRequest{}, mapUserError(err)
+	}
+	return request, nil
+}
+
+// DecideRoleGrantRequest approves or rejects a pending request on behalf of
+// deciderID. Approval assigns the role with the requested window and
+// invalidates the user's access tokens in the same transaction.
+func (r *UserRepository) DecideRoleGrantRequest(ctx context.Context, id, deciderID string, approve bool) (userdomain.RoleGrantRequest, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return userdomain.RoleGrantRequest{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var userID, roleID, status string
+	var validFrom, validUntil *time.Time
+	const lockQuery = `
+		SELECT user_id::text, role_id::text, status, valid_from, valid_until
+		FROM role_grant_requests
+		WHERE id = $1
+		FOR UPDATE
+	`
+	if err := tx.QueryRow(ctx, lockQuery, id).Scan(&userID, &roleID, &status, &validFrom, &validUntil); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return userdomain.RoleGrantRequest{}, userdomain.ErrRequestNotFound
+		}
+		return userdomain.RoleGrantRequest{}, mapUserError(err)
+	}
+	if status != userdomain.RoleRequestPending {
+		return userdomain.RoleGrantRequest{}, userdomain.ErrRequestNotPending
+	}
+
+	decision := userdomain.RoleRequestRejected
+	if approve {
+		decision = userdomain.RoleRequestApproved
+		const grantQuery = `
+			INSERT INTO user_roles (user_id, role_id, valid_from, valid_until)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, role_id, resource_type, resource_id)
+			DO UPDATE SET valid_from = EXCLUDED.valid_from, valid_until = EXCLUDED.valid_until
+		`
+		if _, err := tx.Exec(ctx, grantQuery, userID, roleID, validFrom, validUntil); err != nil {
+			return userdomain.RoleGrantRequest{}, mapUserError(err)
+		}
+		if _, err := tx.Exec(ctx, `UPDATE users SET token_version = token_version + 1 WHERE id = $1`, userID); err != nil {
+			return userdomain.RoleGrantRequest{}, mapUserError(err)
+		}
+	}
+
+	query := `
+		WITH q AS (
+			UPDATE role_grant_requests
+			SET status = $2, decided_by = NULLIF($3, '')::uuid, decided_at = now()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT ` + roleGrantRequestColumns + `
+		FROM q
+		JOIN roles r ON r.id = q.role_id
+	`
+	decided, err := scanRoleGrantRequest(tx.QueryRow(ctx, query, id, decision, deciderID))
+	if err != nil {
+		return userdomain.RoleGrantRequest{}, mapUserError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return userdomain.RoleGrantRequest{}, err
+	}
+	return decided, nil
+}
+
+// lockActiveAdmins locks the admin role row, serializing every change that
+// could remove the last admin, and returns the current number of active
+// admins. Only active users holding the admin role globally and permanently
+// count; a temporary grant will lapse on its own.
+func lockActiveAdmins(ctx context.Context, tx pgx.Tx) (int, error) {
+	if _, err := tx.Exec(ctx, `SELECT id FROM roles WHERE name = $1 FOR UPDATE`, rbacdomain.AdminRole); err != nil {
+		return 0, mapUserError(err)
+	}
+	return countActiveAdmins(ctx, tx)
+}
+
+// ensureActiveAdminRemains returns ErrLastAdmin when there were active admins
+// before the change made in tx and there are none after it.
+func ensureActiveAdminRemains(ctx context.Context, tx pgx.Tx, before int) error {
+	if before == 0 {
+		return nil
+	}
+	after, err := countActiveAdmins(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if after == 0 {
+		return userdomain.ErrLastAdmin
+	}
+	return nil
+}
+
+func countActiveAdmins(ctx context.Context, tx pgx.Tx) (int, error) {
+	const query = `
+		SELECT COUNT(DISTINCT u.id)
+		FROM users u
+		JOIN user_roles ur ON ur.user_id = u.id
+		JOIN roles r ON r.id = ur.role_id
+		WHERE r.name = $1 AND u.is_active
+			AND ur.resource_type = ''
+			AND ur.valid_until IS NULL
+			AND (ur.valid_from IS NULL OR ur.valid_from <= now())
+	`
+	var count int
+	if err := tx.QueryRow(ctx, query, rbacdomain.AdminRole).Scan(&count); err != nil {
+		return 0, mapUserError(err)
+	}
+	return count, nil
+}
+
 func (r *UserRepository) ensureUserExists(ctx context.Context, userID string) error {
 	return ensureUserExistsTx(ctx, r.pool, userID)
 }
@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"context"
+
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+)
+
+type Repository interface {
+	InsertEntry(ctx context.Context, entry auditdomain.Entry) error
+	ListEntries(ctx context.Context, filter auditdomain.ListFilter) (auditdomain.ListResult, error)
+}
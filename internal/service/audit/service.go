@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	"github.com/sirupsen/logrus"
+)
+
+// recordTimeout bounds the audit insert; it runs detached from the request
+// context so a client disconnect does not drop the entry.
+const recordTimeout = 5 * time.Second
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) (*Service, error) {
+	if repo == nil {
+		return nil, errors.New("audit: repository is nil")
+	}
+	return &Service{repo: repo}, nil
+}
+
+// Record stores an audit entry for a change that has already been committed.
+// The actor, IP, user agent and request ID come from the context. Failures
+// are logged rather than returned so auditing never undoes a completed action.
+func (s *Service) Record(ctx context.Context, action, targetType, targetID string, before, after any) {
+	if s == nil {
+		return
+	}
+
+	actor := auditdomain.ActorFromContext(ctx)
+	entry := auditdomain.Entry{
+		ActorUserID:   actor.UserID,
+		ActorAPIKeyID: actor.APIKeyID,
+		Action:        action,
+		TargetType:    targetType,
+		TargetID:      targetID,
+		IP:            actor.IP,
+		UserAgent:     actor.UserAgent,
+		RequestID:     actor.RequestID,
+	}
+	fields := logrus.Fields{
+		"action":      action,
+		"target_type": targetType,
+		"target_id":   targetID,
+		"request_id":  actor.RequestID,
+	}
+
+	changes, err := auditdomain.Diff(before, after)
+	if err != nil {
+		logrus.WithError(err).WithFields(fields).Warn("audit diff failed")
+	}
+	entry.Changes = changes
+
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+	if err := s.repo.InsertEntry(recordCtx, entry); err != nil {
+		logrus.WithError(err).WithFields(fields).Error("audit record failed")
+	}
+}
+
+func (s *Service) List(ctx context.Context, filter auditdomain.ListFilter) (auditdomain.ListResult, error) {
+	filter.ActorUserID = strings.TrimSpace(filter.ActorUserID)
+	filter.Action = strings.TrimSpace(filter.Action)
+	filter.TargetType = strings.TrimSpace(filter.TargetType)
+	filter.TargetID = strings.TrimSpace(filter.TargetID)
+	return s.repo.ListEntries(ctx, filter)
+}
@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
+
+var (
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrInvalidAPIKey       = errors.New("invalid api key")
+	ErrInvalidAPIKeyName   = errors.New("invalid api key name")
+	ErrInvalidAPIKeyExpiry = errors.New("invalid api key expiry")
+	ErrAPIKeyScopeDenied   = errors.New("api key scope exceeds granted permissions")
+)
+
+// apiKeyPrefix marks API keys so they are recognisable in logs and secret scanners.
+const apiKeyPrefix = "ak_"
+
+// apiKeyDisplayLength is how many leading characters of a key are kept in
+// clear text so owners can tell their keys apart.
+const apiKeyDisplayLength = 11
+
+type CreatedAPIKey struct {
+	authdomain.APIKey
+	Key string
+}
+
+// APIKeyPrincipal is the identity an API key authenticates as. Permissions are
+// the key's scopes still held by the owner, and Grants the owner's
+// resource-scoped permissions narrowed the same way.
+type APIKeyPrincipal struct {
+	KeyID       string
+	UserID      string
+	Permissions []string
+	Grants      []rbacdomain.ResourceGrant
+}
+
+// CreateAPIKey issues a key for userID limited to scopes, which must be a
+// subset of the user's current permissions. The raw key is only returned here.
+func (s *Service) CreateAPIKey(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (CreatedAPIKey, error) {
+	trimmedName := strings.TrimSpace(name)
+	if trimmedName == "" {
+		return CreatedAPIKey{}, ErrInvalidAPIKeyName
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return CreatedAPIKey{}, ErrInvalidAPIKeyExpiry
+	}
+
+	normalized := normalizeScopes(scopes)
+	if len(normalized) == 0 {
+		return CreatedAPIKey{}, ErrAPIKeyScopeDenied
+	}
+	granted, err := s.repo.ListUserPermissions(ctx, userID)
+	if err != nil {
+		return CreatedAPIKey{}, err
+	}
+	for _, scope := range normalized {
+		if !rbacdomain.Can(granted, scope) {
+			return CreatedAPIKey{}, ErrAPIKeyScopeDenied
+		}
+	}
+
+	secret, err := generateToken(32)
+	if err != nil {
+		return CreatedAPIKey{}, err
+	}
+	rawKey := apiKeyPrefix + secret
+
+	created, err := s.repo.CreateAPIKey(ctx, authdomain.APIKey{
+		UserID:    userID,
+		Name:      trimmedName,
+		Prefix:    rawKey[:apiKeyDisplayLength],
+		KeyHash:   hashToken(rawKey),
+		Scopes:    normalized,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return CreatedAPIKey{}, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionAPIKeyCreate, auditdomain.TargetAPIKey, created.ID, nil, map[string]any{
+		"user_id":    created.UserID,
+		"name":       created.Name,
+		"prefix":     created.Prefix,
+		"scopes":     created.Scopes,
+		"expires_at": created.ExpiresAt,
+	})
+	return CreatedAPIKey{APIKey: created, Key: rawKey}, nil
+}
+
+func (s *Service) ListAPIKeys(ctx context.Context, userID string) ([]authdomain.APIKey, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, ErrAPIKeyNotFound
+	}
+	return s.repo.ListAPIKeys(ctx, userID)
+}
+
+func (s *Service) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	trimmedID := strings.TrimSpace(keyID)
+	if strings.TrimSpace(userID) == "" || trimmedID == "" {
+		return ErrAPIKeyNotFound
+	}
+
+	if err := s.repo.RevokeAPIKey(ctx, userID, trimmedID); err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	s.recordAudit(ctx, auditdomain.ActionAPIKeyRevoke, auditdomain.TargetAPIKey, trimmedID, map[string]any{"revoked": false}, map[string]any{"revoked": true, "user_id": userID})
+	return nil
+}
+
+// AuthenticateAPIKey resolves a raw key to its owner. The owner's permissions
+// are re-read on every call so removing a role also narrows existing keys.
+func (s *Service) AuthenticateAPIKey(ctx context.Context, rawKey string) (APIKeyPrincipal, error) {
+	trimmed := strings.TrimSpace(rawKey)
+	if !strings.HasPrefix(trimmed, apiKeyPrefix) {
+		return APIKeyPrincipal{}, ErrInvalidAPIKey
+	}
+
+	key, err := s.repo.GetAPIKeyByHash(ctx, hashToken(trimmed))
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return APIKeyPrincipal{}, ErrInvalidAPIKey
+		}
+		return APIKeyPrincipal{}, err
+	}
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt)) {
+		return APIKeyPrincipal{}, ErrInvalidAPIKey
+	}
+
+	state, err := s.repo.GetUserAuthState(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return APIKeyPrincipal{}, ErrInvalidAPIKey
+		}
+		return APIKeyPrincipal{}, err
+	}
+	if !state.IsActive {
+		return APIKeyPrincipal{}, ErrUserDisabled
+	}
+
+	granted, err := s.repo.ListUserPermissions(ctx, key.UserID)
+	if err != nil {
+		return APIKeyPrincipal{}, err
+	}
+	resourceGrants, err := s.repo.ListUserResourceGrants(ctx, key.UserID)
+	if err != nil {
+		return APIKeyPrincipal{}, err
+	}
+
+	if err := s.repo.TouchAPIKey(ctx, key.ID); err != nil {
+		logrus.WithError(err).WithField("api_key_id", key.ID).Warn("failed to record api key usage")
+	}
+
+	return APIKeyPrincipal{
+		KeyID:       key.ID,
+		UserID:      key.UserID,
+		Permissions: intersectScopes(key.Scopes, granted),
+		Grants:      scopeGrants(key.Scopes, resourceGrants),
+	}, nil
+}
+
+// scopeGrants narrows each resource grant to the key's scopes and drops the
+// grants left empty.
+func scopeGrants(scopes []string, grants []rbacdomain.ResourceGrant) []rbacdomain.ResourceGrant {
+	var result []rbacdomain.ResourceGrant
+	for _, grant := range grants {
+		permissions := intersectScopes(scopes, grant.Permissions)
+		if len(permissions) == 0 {
+			continue
+		}
+		grant.Permissions = permissions
+		result = append(result, grant)
+	}
+	return result
+}
+
+func normalizeScopes(scopes []string) []string {
+	seen := make(map[string]struct{}, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		normalized := strings.ToLower(strings.TrimSpace(scope))
+		if normalized == "" {
+			continue
+		}
+		if _, ok := seen[normalized]; ok {
+			continue
+		}
+		seen[normalized] = struct{}{}
+		result = append(result, normalized)
+	}
+	return result
+}
+
+// intersectScopes returns what a key may do: the scopes its owner still
+// holds, plus the owner's grants that fall under a wildcard scope, so a
+// "user.*" key keeps working for "user.read" without granting more.
+func intersectScopes(scopes, granted []string) []string {
+	seen := make(map[string]struct{}, len(scopes))
+	result := make([]string, 0, len(scopes))
+	add := func(permission string) {
+		if _, ok := seen[permission]; ok {
+			return
+		}
+		seen[permission] = struct{}{}
+		result = append(result, permission)
+	}
+	for _, scope := range scopes {
+		if rbacdomain.Can(granted, scope) {
+			add(scope)
+		}
+	}
+	for _, permission := range granted {
+		normalized := rbacdomain.NormalizePermission(permission)
+		if normalized != "" && rbacdomain.Can(scopes, normalized) {
+			add(normalized)
+		}
+	}
+	return result
+}
@@ -4,20 +4,64 @@ import (
 	"context"
 
 	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 )
 
 type Repository interface {
 	FindUserByEmail(ctx context.Context, email string) (authdomain.User, error)
 	FindUserByID(ctx context.Context, id string) (authdomain.User, error)
+	CreateUser(ctx context.Context, email, passwordHash string) (authdomain.User, error)
+	MarkEmailVerified(ctx context.Context, userID, email string) error
+	FindUserByIdentity(ctx context.Context, provider, subject string) (authdomain.User, error)
+	LinkIdentity(ctx context.Context, userID string, identity authdomain.ExternalIdentity) error
+	CreateUserWithIdentity(ctx context.Context, email string, identity authdomain.ExternalIdentity) (authdomain.User, error)
 	GetUserAuthState(ctx context.Context, id string) (authdomain.AuthState, error)
 	ListUserRoles(ctx context.Context, userID string) ([]string, error)
 	ListUserPermissions(ctx context.Context, userID string) ([]string, error)
+	ListUserResourceGrants(ctx context.Context, userID string) ([]rbacdomain.ResourceGrant, error)
 	CreateRefreshToken(ctx context.Context, token authdomain.RefreshToken) error
 	GetRefreshToken(ctx context.Context, tokenHash string) (authdomain.RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, tokenHash, replacedByHash string) error
 	RevokeAllRefreshTokens(ctx context.Context, userID string) error
 	DeleteExpiredRefreshTokens(ctx context.Context) (int64, error)
+	ListSessions(ctx context.Context, userID string) ([]authdomain.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	RevokeSessionsExcept(ctx context.Context, userID, keepSessionID string) ([]string, error)
+	CreateAPIKey(ctx context.Context, key authdomain.APIKey) (authdomain.APIKey, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]authdomain.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (authdomain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID string) error
+	TouchAPIKey(ctx context.Context, keyID string) error
 	RecordLoginFailure(ctx context.Context, userID string, maxAttempts int, lockoutSeconds int64) error
 	ResetLoginFailures(ctx context.Context, userID string) error
 	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	UpgradePasswordHash(ctx context.Context, userID, currentHash, newHash string) error
+	ListPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error)
+	SetMFASecret(ctx context.Context, userID, secret string) error
+	EnableMFA(ctx context.Context, userID string, usedStep int64, recoveryCodeHashes []string) error
+	DisableMFA(ctx context.Context, userID string) error
+	MarkTOTPStepUsed(ctx context.Context, userID string, step int64) (bool, error)
+	ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+}
+
+// PasswordHasher hashes and verifies passwords. Compare accepts hashes from
+// any supported algorithm; NeedsRehash reports hashes that should be
+// replaced with one using the current settings.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(encoded, password string) error
+	NeedsRehash(encoded string) bool
+}
+
+// PasswordPolicy checks new passwords. Check returns a
+// *authdomain.PasswordPolicyError listing the rules password fails;
+// previousHashes are the user's recent password hashes, newest first.
+type PasswordPolicy interface {
+	Check(password, email string, previousHashes []string) error
+	HistorySize() int
+}
+
+// AuditRecorder stores audit entries for completed changes.
+type AuditRecorder interface {
+	Record(ctx context.Context, action, targetType, targetID string, before, after any)
 }
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
+
+var ErrUserNotFound = errors.New("user not found")
+
+// EffectiveAccess is what a user may do right now, read from the database
+// rather than from the claims of an access token that may predate a role
+// change.
+type EffectiveAccess struct {
+	User        authdomain.User
+	Roles       []string
+	Permissions []string
+	Grants      []rbacdomain.ResourceGrant
+}
+
+// PermissionCheck asks whether a permission is held, optionally on a single
+// resource.
+type PermissionCheck struct {
+	Permission   string
+	ResourceType string
+	ResourceID   string
+}
+
+type PermissionDecision struct {
+	PermissionCheck
+	Allowed bool
+}
+
+// EffectiveAccess resolves the user's current roles, permissions (including
+// inherited ones) and resource grants.
+func (s *Service) EffectiveAccess(ctx context.Context, userID string) (EffectiveAccess, error) {
+	if strings.TrimSpace(userID) == "" {
+		return EffectiveAccess{}, ErrUserNotFound
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return EffectiveAccess{}, ErrUserNotFound
+		}
+		return EffectiveAccess{}, err
+	}
+	roles, err := s.repo.ListUserRoles(ctx, user.ID)
+	if err != nil {
+		return EffectiveAccess{}, err
+	}
+	permissions, err := s.repo.ListUserPermissions(ctx, user.ID)
+	if err != nil {
+		return EffectiveAccess{}, err
+	}
+	grants, err := s.repo.ListUserResourceGrants(ctx, user.ID)
+	if err != nil {
+		return EffectiveAccess{}, err
+	}
+
+	sort.Strings(roles)
+	sort.Strings(permissions)
+	return EffectiveAccess{
+		User:        user,
+		Roles:       roles,
+		Permissions: permissions,
+		Grants:      grants,
+	}, nil
+}
+
+// Check evaluates each check with the same matching rules as the route
+// middleware, so a client can ask before rendering an action.
+func (a EffectiveAccess) Check(checks []PermissionCheck) []PermissionDecision {
+	decisions := make([]PermissionDecision, 0, len(checks))
+	for _, check := range checks {
+		decisions = append(decisions, PermissionDecision{
+			PermissionCheck: check,
+			Allowed: rbacdomain.CanOn(a.Permissions, a.Grants,
+				rbacdomain.NormalizePermission(check.Permission), check.ResourceType, check.ResourceID),
+		})
+	}
+	return decisions
+}
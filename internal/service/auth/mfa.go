@@ -0,0 +1,366 @@
+package auth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	redisinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/redis"
+	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/totp"
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+)
+
+var (
+	ErrMFAAlreadyEnabled   = errors.New("mfa is already enabled")
+	ErrMFANotEnabled       = errors.New("mfa is not enabled")
+	ErrMFASetupRequired    = errors.New("mfa setup has not been started")
+	ErrInvalidMFACode      = errors.New("invalid mfa code")
+	ErrInvalidMFAChallenge = errors.New("invalid mfa challenge")
+)
+
+const (
+	recoveryCodeCount = 10
+	totpSkew          = 1
+	mfaSecretPrefix   = "v1:"
+)
+
+// incrementWithTTLScript increments a counter and sets its expiry on first use.
+const incrementWithTTLScript = `
+local current = redis.call("INCR", KEYS[1])
+if current == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return current
+`
+
+type MFASetup struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+type LoginResult struct {
+	TokenPair
+	MFARequired  bool
+	MFAToken     string
+	MFAExpiresAt time.Time
+}
+
+// SetupTOTP generates a new pending TOTP secret. It only takes effect once
+// confirmed with a valid code.
+func (s *Service) SetupTOTP(ctx context.Context, userID string) (MFASetup, error) {
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return MFASetup{}, ErrInvalidAccessToken
+		}
+		return MFASetup{}, err
+	}
+	if user.MFAEnabledAt != nil {
+		return MFASetup{}, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return MFASetup{}, err
+	}
+	sealed, err := s.sealMFASecret(secret)
+	if err != nil {
+		return MFASetup{}, err
+	}
+	if err := s.repo.SetMFASecret(ctx, user.ID, sealed); err != nil {
+		if errors.Is(err, authdomain.ErrConflict) {
+			return MFASetup{}, ErrMFAAlreadyEnabled
+		}
+		return MFASetup{}, err
+	}
+
+	return MFASetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, s.mfaIssuer, user.Email),
+	}, nil
+}
+
+// ConfirmTOTP enables MFA after the first valid code and returns the
+// recovery codes. They are only shown once.
+func (s *Service) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return nil, ErrInvalidAccessToken
+		}
+		return nil, err
+	}
+	if user.MFAEnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == "" {
+		return nil, ErrMFASetupRequired
+	}
+
+	secret, err := s.openMFASecret(user.MFASecret)
+	if err != nil {
+		return nil, err
+	}
+	step, err := totp.Validate(secret, code, time.Now(), totpSkew)
+	if err != nil {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.EnableMFA(ctx, user.ID, step, hashes); err != nil {
+		if errors.Is(err, authdomain.ErrConflict) {
+			return nil, ErrMFAAlreadyEnabled
+		}
+		return nil, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionMFAEnable, auditdomain.TargetUser, user.ID, map[string]any{"mfa_enabled": false}, map[string]any{"mfa_enabled": true})
+	return codes, nil
+}
+
+// DisableTOTP turns MFA off. The caller must prove possession with a current
+// TOTP code or an unused recovery code.
+func (s *Service) DisableTOTP(ctx context.Context, userID, code string) error {
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return ErrInvalidAccessToken
+		}
+		return err
+	}
+	if user.MFAEnabledAt == nil {
+		return ErrMFANotEnabled
+	}
+	if err := s.verifyMFACode(ctx, user, code); err != nil {
+		return err
+	}
+	if err := s.repo.DisableMFA(ctx, user.ID); err != nil {
+		return err
+	}
+	s.recordAudit(ctx, auditdomain.ActionMFADisable, auditdomain.TargetUser, user.ID, map[string]any{"mfa_enabled": true}, map[string]any{"mfa_enabled": false})
+	return nil
+}
+
+// VerifyMFA exchanges a login challenge and a TOTP or recovery code for a
+// token pair.
+func (s *Service) VerifyMFA(ctx context.Context, challengeToken, code, ip, userAgent string) (TokenPair, error) {
+	trimmedToken := strings.TrimSpace(challengeToken)
+	if trimmedToken == "" {
+		return TokenPair{}, ErrInvalidMFAChallenge
+	}
+	if s.cache == nil {
+		return TokenPair{}, errors.New("auth: cache is nil")
+	}
+
+	tokenHash := hashToken(trimmedToken)
+	challengeKey := fmt.Sprintf("auth:mfa:challenge:%s", tokenHash)
+	attemptsKey := fmt.Sprintf("auth:mfa:challenge_attempts:%s", tokenHash)
+
+	userID, err := s.cache.GetString(ctx, challengeKey)
+	if err != nil {
+		if errors.Is(err, redisinfra.ErrKeyNotFound) {
+			return TokenPair{}, ErrInvalidMFAChallenge
+		}
+		return TokenPair{}, err
+	}
+
+	if s.mfaMaxAttempts > 0 {
+		ttl := s.mfaChallengeTTL
+		if ttl <= 0 {
+			ttl = 5 * time.Minute
+		}
+		attempts, err := s.cache.Eval(ctx, incrementWithTTLScript, []string{attemptsKey}, ttl.Milliseconds())
+		if err != nil {
+			return TokenPair{}, err
+		}
+		if count, ok := attempts.(int64); ok && count > int64(s.mfaMaxAttempts) {
+			_ = s.cache.Delete(ctx, challengeKey)
+			_ = s.cache.Delete(ctx, attemptsKey)
+			return TokenPair{}, ErrInvalidMFAChallenge
+		}
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return TokenPair{}, ErrInvalidMFAChallenge
+		}
+		return TokenPair{}, err
+	}
+	if !user.IsActive {
+		return TokenPair{}, ErrUserDisabled
+	}
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return TokenPair{}, ErrUserLocked
+	}
+	if user.MFAEnabledAt == nil {
+		return TokenPair{}, ErrInvalidMFAChallenge
+	}
+	if err := s.verifyMFACode(ctx, user, code); err != nil {
+		return TokenPair{}, err
+	}
+
+	_ = s.cache.Delete(ctx, challengeKey)
+	_ = s.cache.Delete(ctx, attemptsKey)
+
+	return s.issueTokenPair(ctx, user, ip, userAgent)
+}
+
+func (s *Service) issueMFAChallenge(ctx context.Context, user authdomain.User) (LoginResult, error) {
+	if s.cache == nil {
+		return LoginResult{}, errors.New("auth: cache is nil")
+	}
+
+	rawToken, err := generateToken(32)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	ttl := s.mfaChallengeTTL
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	challengeKey := fmt.Sprintf("auth:mfa:challenge:%s", hashToken(rawToken))
+	if err := s.cache.SetWithTTL(ctx, challengeKey, user.ID, ttl); err != nil {
+		return LoginResult{}, err
+	}
+
+	return LoginResult{
+		MFARequired:  true,
+		MFAToken:     rawToken,
+		MFAExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+func (s *Service) verifyMFACode(ctx context.Context, user authdomain.User, code string) error {
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return ErrInvalidMFACode
+	}
+
+	if len(normalized) == totp.Digits && isDigits(normalized) {
+		secret, err := s.openMFASecret(user.MFASecret)
+		if err != nil {
+			return err
+		}
+		step, err := totp.Validate(secret, normalized, time.Now(), totpSkew)
+		if err != nil {
+			return ErrInvalidMFACode
+		}
+		ok, err := s.repo.MarkTOTPStepUsed(ctx, user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	ok, err := s.repo.ConsumeRecoveryCode(ctx, user.ID, hashToken(normalized))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *Service) sealMFASecret(secret string) (string, error) {
+	gcm, err := newMFACipher(s.mfaKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return mfaSecretPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (s *Service) openMFASecret(value string) (string, error) {
+	if !strings.HasPrefix(value, mfaSecretPrefix) {
+		return "", errors.New("auth: unsupported mfa secret format")
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(value, mfaSecretPrefix))
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newMFACipher(s.mfaKey)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < gcm.NonceSize() {
+		return "", errors.New("auth: mfa secret is malformed")
+	}
+	nonce, ciphertext := raw[:gcm.NonceSize()], raw[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func newMFACipher(key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, errors.New("auth: mfa encryption key is empty")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveMFAKey turns AUTH_MFA_ENCRYPTION_KEY into an AES-256 key. It never
+// falls back to the JWT secret: rotating that would make every stored TOTP
+// secret unreadable.
+func deriveMFAKey(encryptionKey string) []byte {
+	source := strings.TrimSpace(encryptionKey)
+	if source == "" {
+		return nil
+	}
+	sum := sha256.Sum256([]byte("mfa:" + source))
+	return sum[:]
+}
+
+func generateRecoveryCodes(count int) ([]string, []string, error) {
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := base32.StdEncoding.EncodeToString(buf)
+		codes = append(codes, raw[:4]+"-"+raw[4:])
+		hashes = append(hashes, hashToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	replacer := strings.NewReplacer("-", "", " ", "")
+	return strings.ToUpper(replacer.Replace(strings.TrimSpace(code)))
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
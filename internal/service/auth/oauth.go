@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+)
+
+var (
+	ErrUnknownIdentityProvider = errors.New("unknown identity provider")
+	ErrInvalidOAuthState       = errors.New("invalid oauth state")
+	ErrExternalLoginFailed     = errors.New("external login failed")
+	ErrExternalEmailUnverified = errors.New("external email is not verified")
+	ErrIdentityNotLinked       = errors.New("no account linked to external identity")
+	ErrIdentityConflict        = errors.New("account is linked to another external identity")
+	ErrIdentityLinkUnverified  = errors.New("account email is not verified")
+)
+
+// takeScript returns a key's value and deletes it in one step, so an OAuth
+// state or a single-use token can only be redeemed once. A missing key
+// yields an empty string.
+const takeScript = `
+local value = redis.call("GET", KEYS[1])
+if not value then
+	return ""
+end
+redis.call("DEL", KEYS[1])
+return value
+`
+
+// IdentityProvider signs users in with an external OpenID Connect provider
+// using the authorization code flow with PKCE.
+type IdentityProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (authdomain.ExternalIdentity, error)
+}
+
+// OAuthStart is where to send the user to sign in, and the state the
+// callback must echo.
+type OAuthStart struct {
+	URL       string
+	State     string
+	ExpiresAt time.Time
+}
+
+type oauthState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// RegisterIdentityProvider enables external login through provider under
+// name, which appears in the /auth/oauth/:provider routes.
+func (s *Service) RegisterIdentityProvider(name string, provider IdentityProvider) {
+	if s.identityProviders == nil {
+		s.identityProviders = make(map[string]IdentityProvider)
+	}
+	s.identityProviders[name] = provider
+}
+
+// StartOAuthLogin creates a single-use state, nonce and PKCE verifier for
+// providerName and returns the authorization URL.
+func (s *Service) StartOAuthLogin(ctx context.Context, providerName string) (OAuthStart, error) {
+	provider, ok := s.identityProviders[providerName]
+	if !ok {
+		return OAuthStart{}, ErrUnknownIdentityProvider
+	}
+	if s.cache == nil {
+		return OAuthStart{}, errors.New("auth: cache is nil")
+	}
+
+	state, err := generateToken(32)
+	if err != nil {
+		return OAuthStart{}, err
+	}
+	nonce, err := generateToken(32)
+	if err != nil {
+		return OAuthStart{}, err
+	}
+	verifier, err := generateToken(32)
+	if err != nil {
+		return OAuthStart{}, err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return OAuthStart{}, fmt.Errorf("%w: %v", ErrExternalLoginFailed, err)
+	}
+
+	payload, err := json.Marshal(oauthState{Provider: providerName, Nonce: nonce, Verifier: verifier})
+	if err != nil {
+		return OAuthStart{}, err
+	}
+	ttl := s.oauthStateTTL
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	if err := s.cache.SetWithTTL(ctx, oauthStateKey(state), string(payload), ttl); err != nil {
+		return OAuthStart{}, err
+	}
+
+	return OAuthStart{URL: authURL, State: state, ExpiresAt: time.Now().Add(ttl)}, nil
+}
+
+// CompleteOAuthLogin redeems the authorization code returned to the callback
+// and signs the user in. The external identity is matched by provider and
+// subject first; otherwise a verified email links it to the existing account
+// with that email or, when sign-up is allowed, to a new account.
+func (s *Service) CompleteOAuthLogin(ctx context.Context, providerName, state, code, ip, userAgent string) (LoginResult, error) {
+	provider, ok := s.identityProviders[providerName]
+	if !ok {
+		return LoginResult{}, ErrUnknownIdentityProvider
+	}
+	state, code = strings.TrimSpace(state), strings.TrimSpace(code)
+	if state == "" || code == "" {
+		return LoginResult{}, ErrInvalidOAuthState
+	}
+	if s.cache == nil {
+		return LoginResult{}, errors.New("auth: cache is nil")
+	}
+
+	stored, err := s.cache.Eval(ctx, takeScript, []string{oauthStateKey(state)})
+	if err != nil {
+		return LoginResult{}, err
+	}
+	raw, _ := stored.(string)
+	if raw == "" {
+		return LoginResult{}, ErrInvalidOAuthState
+	}
+	var pending oauthState
+	if err := json.Unmarshal([]byte(raw), &pending); err != nil || pending.Provider != providerName {
+		return LoginResult{}, ErrInvalidOAuthState
+	}
+
+	identity, err := provider.Exchange(ctx, code, pending.Verifier, pending.Nonce)
+	if err != nil {
+		return LoginResult{}, fmt.Errorf("%w: %v", ErrExternalLoginFailed, err)
+	}
+	identity.Provider = providerName
+
+	user, err := s.resolveExternalUser(ctx, identity)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if !user.IsActive {
+		return LoginResult{}, ErrUserDisabled
+	}
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return LoginResult{}, ErrUserLocked
+	}
+	if s.requireVerifiedEmail && user.EmailVerifiedAt == nil {
+		return LoginResult{}, ErrEmailNotVerified
+	}
+
+	if user.MFAEnabledAt != nil {
+		return s.issueMFAChallenge(ctx, user)
+	}
+
+	tokens, err := s.issueTokenPair(ctx, user, ip, userAgent)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	return LoginResult{TokenPair: tokens}, nil
+}
+
+func (s *Service) resolveExternalUser(ctx context.Context, identity authdomain.ExternalIdentity) (authdomain.User, error) {
+	user, err := s.repo.FindUserByIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, authdomain.ErrNotFound) {
+		return authdomain.User{}, err
+	}
+
+	// Linking by email is only safe when the provider vouches for it;
+	// otherwise anyone could claim an existing account.
+	email := strings.TrimSpace(strings.ToLower(identity.Email))
+	if email == "" || !identity.EmailVerified {
+		return authdomain.User{}, ErrExternalEmailUnverified
+	}
+
+	user, err = s.repo.FindUserByEmail(ctx, email)
+	switch {
+	case err == nil:
+		// An unverified account may have been registered by someone else
+		// ahead of the email's owner, so it is never linked automatically.
+		if user.EmailVerifiedAt == nil {
+			return authdomain.User{}, ErrIdentityLinkUnverified
+		}
+		if err := s.repo.LinkIdentity(ctx, user.ID, identity); err != nil {
+			switch {
+			case errors.Is(err, authdomain.ErrConflict):
+				return authdomain.User{}, ErrIdentityConflict
+			case errors.Is(err, authdomain.ErrNotFound):
+				return authdomain.User{}, ErrIdentityLinkUnverified
+			}
+			return authdomain.User{}, err
+		}
+	case errors.Is(err, authdomain.ErrNotFound):
+		if !s.oauthAllowSignup {
+			return authdomain.User{}, ErrIdentityNotLinked
+		}
+		user, err = s.repo.CreateUserWithIdentity(ctx, email, identity)
+		if err != nil {
+			if errors.Is(err, authdomain.ErrConflict) {
+				return authdomain.User{}, ErrIdentityConflict
+			}
+			return authdomain.User{}, err
+		}
+	default:
+		return authdomain.User{}, err
+	}
+
+	s.recordAudit(ctx, auditdomain.ActionIdentityLink, auditdomain.TargetUser, user.ID, nil, map[string]any{
+		"provider": identity.Provider,
+		"subject":  identity.Subject,
+	})
+	return user, nil
+}
+
+func oauthStateKey(state string) string {
+	return fmt.Sprintf("auth:oauth:state:%s", hashToken(state))
+}
@@ -6,39 +6,57 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
-
 	redisinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/redis"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
 	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrInvalidToken       = errors.New("invalid refresh token")
-	ErrInvalidAccessToken = errors.New("invalid access token")
-	ErrUserDisabled       = errors.New("user is disabled")
-	ErrUserLocked         = errors.New("user is locked")
-	ErrInvalidResetToken  = errors.New("invalid reset token")
-	ErrInvalidPassword    = errors.New("invalid password")
+	ErrInvalidCredentials     = errors.New("invalid credentials")
+	ErrInvalidToken           = errors.New("invalid refresh token")
+	ErrInvalidAccessToken     = errors.New("invalid access token")
+	ErrUserDisabled           = errors.New("user is disabled")
+	ErrUserLocked             = errors.New("user is locked")
+	ErrInvalidResetToken      = errors.New("invalid reset token")
+	ErrInvalidPassword        = errors.New("invalid password")
+	ErrInvalidCurrentPassword = errors.New("invalid current password")
+
+	ErrEmailAlreadyRegistered   = errors.New("email already registered")
+	ErrEmailNotVerified         = errors.New("email is not verified")
+	ErrInvalidVerificationToken = errors.New("invalid verification token")
 )
 
-const passwordHashCost = 12
-
 type Service struct {
-	repo             Repository
-	tokenManager     TokenManager
-	cache            redisinfra.Cache
-	refreshTTL       time.Duration
-	passwordResetTTL time.Duration
-	resetCooldown    time.Duration
-	maxLoginAttempts int
-	lockoutDuration  time.Duration
+	repo                 Repository
+	tokenManager         TokenManager
+	hasher               PasswordHasher
+	passwordPolicy       PasswordPolicy
+	cache                redisinfra.Cache
+	accessTTL            time.Duration
+	refreshTTL           time.Duration
+	passwordResetTTL     time.Duration
+	resetCooldown        time.Duration
+	maxLoginAttempts     int
+	lockoutDuration      time.Duration
+	requireVerifiedEmail bool
+	verificationTTL      time.Duration
+	verificationCooldown time.Duration
+	mfaIssuer            string
+	mfaKey               []byte
+	mfaChallengeTTL      time.Duration
+	mfaMaxAttempts       int
+	oauthStateTTL        time.Duration
+	oauthAllowSignup     bool
+	identityProviders    map[string]IdentityProvider
+	audit                AuditRecorder
 }
 
 type TokenPair struct {
@@ -48,28 +66,42 @@ type TokenPair struct {
 	ExpiresIn    int64
 }
 
-func NewService(cfg config.Config, repo Repository, tokenManager TokenManager) (*Service, error) {
+func NewService(cfg config.Config, repo Repository, tokenManager TokenManager, hasher PasswordHasher) (*Service, error) {
 	if repo == nil {
 		return nil, errors.New("auth: repository is nil")
 	}
 	if tokenManager == nil {
 		return nil, errors.New("auth: token manager is nil")
 	}
+	if hasher == nil {
+		return nil, errors.New("auth: password hasher is nil")
+	}
 
 	return &Service{
-		repo:             repo,
-		tokenManager:     tokenManager,
-		cache:            nil,
-		refreshTTL:       cfg.RefreshTokenTTL,
-		passwordResetTTL: cfg.AuthPasswordResetTTL,
-		resetCooldown:    cfg.AuthPasswordResetCooldown,
-		maxLoginAttempts: cfg.AuthMaxLoginAttempts,
-		lockoutDuration:  cfg.AuthLockoutDuration,
+		repo:                 repo,
+		tokenManager:         tokenManager,
+		hasher:               hasher,
+		cache:                nil,
+		accessTTL:            cfg.AccessTokenTTL,
+		refreshTTL:           cfg.RefreshTokenTTL,
+		passwordResetTTL:     cfg.AuthPasswordResetTTL,
+		resetCooldown:        cfg.AuthPasswordResetCooldown,
+		maxLoginAttempts:     cfg.AuthMaxLoginAttempts,
+		lockoutDuration:      cfg.AuthLockoutDuration,
+		requireVerifiedEmail: cfg.AuthRequireEmailVerification,
+		verificationTTL:      cfg.AuthEmailVerificationTTL,
+		verificationCooldown: cfg.AuthEmailVerificationCooldown,
+		mfaIssuer:            cfg.AuthMFAIssuer,
+		mfaKey:               deriveMFAKey(cfg.AuthMFAEncryptionKey),
+		mfaChallengeTTL:      cfg.AuthMFAChallengeTTL,
+		mfaMaxAttempts:       cfg.AuthMFAMaxAttempts,
+		oauthStateTTL:        cfg.OAuthStateTTL,
+		oauthAllowSignup:     cfg.OAuthAllowSignup,
 	}, nil
 }
 
-func NewServiceWithCache(cfg config.Config, repo Repository, tokenManager TokenManager, cache redisinfra.Cache) (*Service, error) {
-	service, err := NewService(cfg, repo, tokenManager)
+func NewServiceWithCache(cfg config.Config, repo Repository, tokenManager TokenManager, hasher PasswordHasher, cache redisinfra.Cache) (*Service, error) {
+	service, err := NewService(cfg, repo, tokenManager, hasher)
 	if err != nil {
 		return nil, err
 	}
@@ -77,47 +109,112 @@ func NewServiceWithCache(cfg config.Config, repo Repository, tokenManager TokenM
 	return service, nil
 }
 
-func (s *Service) Login(ctx context.Context, email, password, ip, userAgent string) (TokenPair, error) {
+// SetAuditRecorder enables audit entries for password, MFA and API key changes.
+func (s *Service) SetAuditRecorder(recorder AuditRecorder) {
+	s.audit = recorder
+}
+
+// SetPasswordPolicy checks passwords set through registration, password
+// reset and password change. Without a policy any non-blank password is accepted.
+func (s *Service) SetPasswordPolicy(policy PasswordPolicy) {
+	s.passwordPolicy = policy
+}
+
+func (s *Service) recordAudit(ctx context.Context, action, targetType, targetID string, before, after any) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(ctx, action, targetType, targetID, before, after)
+}
+
+func (s *Service) Login(ctx context.Context, email, password, ip, userAgent string) (LoginResult, error) {
 	normalizedEmail := strings.TrimSpace(strings.ToLower(email))
 	if normalizedEmail == "" || password == "" {
-		return TokenPair{}, ErrInvalidCredentials
+		return LoginResult{}, ErrInvalidCredentials
 	}
 
 	user, err := s.repo.FindUserByEmail(ctx, normalizedEmail)
 	if err != nil {
 		if errors.Is(err, authdomain.ErrNotFound) {
-			return TokenPair{}, ErrInvalidCredentials
+			return LoginResult{}, ErrInvalidCredentials
 		}
-		return TokenPair{}, err
+		return LoginResult{}, err
 	}
 	if !user.IsActive {
-		return TokenPair{}, ErrUserDisabled
+		return LoginResult{}, ErrUserDisabled
 	}
 	if user.LockedUntil != nil {
 		if user.LockedUntil.After(time.Now()) {
-			return TokenPair{}, ErrUserLocked
+			return LoginResult{}, ErrUserLocked
 		}
 		if s.maxLoginAttempts > 0 {
 			if err := s.repo.ResetLoginFailures(ctx, user.ID); err != nil {
-				return TokenPair{}, err
+				return LoginResult{}, err
 			}
 		}
 	}
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+	if err := s.hasher.Compare(user.PasswordHash, password); err != nil {
 		if s.maxLoginAttempts > 0 {
 			lockSeconds := int64(s.lockoutDuration.Seconds())
 			if err := s.repo.RecordLoginFailure(ctx, user.ID, s.maxLoginAttempts, lockSeconds); err != nil {
-				return TokenPair{}, err
+				return LoginResult{}, err
 			}
 		}
-		return TokenPair{}, ErrInvalidCredentials
+		return LoginResult{}, ErrInvalidCredentials
 	}
 	if s.maxLoginAttempts > 0 {
 		if err := s.repo.ResetLoginFailures(ctx, user.ID); err != nil {
-			return TokenPair{}, err
+			return LoginResult{}, err
 		}
 	}
+	s.upgradePasswordHash(ctx, user, password)
+	if s.requireVerifiedEmail && user.EmailVerifiedAt == nil {
+		return LoginResult{}, ErrEmailNotVerified
+	}
+
+	if user.MFAEnabledAt != nil {
+		return s.issueMFAChallenge(ctx, user)
+	}
+
+	tokens, err := s.issueTokenPair(ctx, user, ip, userAgent)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	return LoginResult{TokenPair: tokens}, nil
+}
+
+// checkPassword applies the password policy, if one is set. For an existing
+// user (userID not empty) the recent passwords are checked too.
+func (s *Service) checkPassword(ctx context.Context, userID, email, password string) error {
+	if s.passwordPolicy == nil {
+		return nil
+	}
+	var history []string
+	if userID != "" && s.passwordPolicy.HistorySize() > 0 {
+		var err error
+		history, err = s.repo.ListPasswordHistory(ctx, userID, s.passwordPolicy.HistorySize())
+		if err != nil {
+			return err
+		}
+	}
+	return s.passwordPolicy.Check(password, email, history)
+}
 
+// upgradePasswordHash replaces a hash made with an older algorithm or weaker
+// parameters once the password has been verified. It is best effort: a
+// failure leaves the old hash, which is retried on the next login.
+func (s *Service) upgradePasswordHash(ctx context.Context, user authdomain.User, password string) {
+	if !s.hasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+	hashed, err := s.hasher.Hash(password)
+	if err != nil {
+		return
+	}
+	_ = s.repo.UpgradePasswordHash(ctx, user.ID, user.PasswordHash, hashed)
+}
+
+func (s *Service) issueTokenPair(ctx context.Context, user authdomain.User, ip, userAgent string) (TokenPair, error) {
 	roles, err := s.repo.ListUserRoles(ctx, user.ID)
 	if err != nil {
 		return TokenPair{}, err
@@ -126,8 +223,17 @@ func (s *Service) Login(ctx context.Context, email, password, ip, userAgent stri
 	if err != nil {
 		return TokenPair{}, err
 	}
+	grants, err := s.repo.ListUserResourceGrants(ctx, user.ID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	sessionID, err := newSessionID()
+	if err != nil {
+		return TokenPair{}, err
+	}
 
-	accessToken, expiresIn, err := s.createAccessToken(user.ID, roles, perms, user.TokenVersion)
+	accessToken, expiresIn, err := s.createAccessToken(user.ID, sessionID, roles, perms, grants, user.TokenVersion)
 	if err != nil {
 		return TokenPair{}, err
 	}
@@ -139,6 +245,7 @@ func (s *Service) Login(ctx context.Context, email, password, ip, userAgent stri
 
 	if err := s.repo.CreateRefreshToken(ctx, authdomain.RefreshToken{
 		UserID:    user.ID,
+		SessionID: sessionID,
 		TokenHash: tokenHash,
 		ExpiresAt: expiresAt,
 		IPAddress: ip,
@@ -199,8 +306,12 @@ func (s *Service) Refresh(ctx context.Context, refreshToken, ip, userAgent strin
 	if err != nil {
 		return TokenPair{}, err
 	}
+	grants, err := s.repo.ListUserResourceGrants(ctx, user.ID)
+	if err != nil {
+		return TokenPair{}, err
+	}
 
-	accessToken, expiresIn, err := s.createAccessToken(user.ID, roles, perms, user.TokenVersion)
+	accessToken, expiresIn, err := s.createAccessToken(user.ID, storedToken.SessionID, roles, perms, grants, user.TokenVersion)
 	if err != nil {
 		return TokenPair{}, err
 	}
@@ -215,6 +326,7 @@ func (s *Service) Refresh(ctx context.Context, refreshToken, ip, userAgent strin
 	}
 	if err := s.repo.CreateRefreshToken(ctx, authdomain.RefreshToken{
 		UserID:    user.ID,
+		SessionID: storedToken.SessionID,
 		TokenHash: newTokenHash,
 		ExpiresAt: expiresAt,
 		IPAddress: ip,
@@ -276,10 +388,32 @@ func (s *Service) ValidateAccessToken(ctx context.Context, tokenString string) (
 	if state.TokenVersion != claims.TokenVersion {
 		return AccessClaims{}, ErrInvalidAccessToken
 	}
+	if claims.SessionID != "" {
+		revoked, err := s.isSessionRevoked(ctx, claims.SessionID)
+		if err != nil {
+			return AccessClaims{}, err
+		}
+		if revoked {
+			return AccessClaims{}, ErrInvalidAccessToken
+		}
+	}
 
 	return claims, nil
 }
 
+// PublicKeys returns the JWKS entries used to verify access tokens. It is
+// empty when tokens are signed with a shared secret.
+func (s *Service) PublicKeys() []JSONWebKey {
+	if s == nil || s.tokenManager == nil {
+		return nil
+	}
+	provider, ok := s.tokenManager.(KeySetProvider)
+	if !ok {
+		return nil
+	}
+	return provider.PublicKeys()
+}
+
 func (s *Service) CleanupExpiredRefreshTokens(ctx context.Context) (int64, error) {
 	return s.repo.DeleteExpiredRefreshTokens(ctx)
 }
@@ -388,13 +522,16 @@ func (s *Service) ResetPassword(ctx context.Context, token, newPassword string)
 	if !user.IsActive {
 		return ErrInvalidResetToken
 	}
+	if err := s.checkPassword(ctx, user.ID, user.Email, trimmedPassword); err != nil {
+		return err
+	}
 
-	hashed, err := bcrypt.GenerateFromPassword([]byte(trimmedPassword), passwordHashCost)
+	hashed, err := s.hasher.Hash(trimmedPassword)
 	if err != nil {
 		return err
 	}
 
-	if err := s.repo.UpdatePassword(ctx, user.ID, string(hashed)); err != nil {
+	if err := s.repo.UpdatePassword(ctx, user.ID, hashed); err != nil {
 		return err
 	}
 	if err := s.repo.RevokeAllRefreshTokens(ctx, user.ID); err != nil {
@@ -403,14 +540,298 @@ func (s *Service) ResetPassword(ctx context.Context, token, newPassword string)
 
 	_ = s.cache.Delete(ctx, tokenKey)
 	_ = s.cache.Delete(ctx, fmt.Sprintf("auth:password_reset:user:%s", user.ID))
+	s.recordAudit(ctx, auditdomain.ActionPasswordReset, auditdomain.TargetUser, user.ID, nil, map[string]any{"password_changed": true})
+	return nil
+}
+
+type PasswordChange struct {
+	Email           string
+	RevokedSessions int
+}
+
+// ChangePassword replaces the password of a signed-in user after checking
+// the current one. Bumping the token version invalidates every access token,
+// including the caller's; all sessions except currentSessionID are revoked,
+// so the caller keeps its refresh token and refreshes to continue.
+func (s *Service) ChangePassword(ctx context.Context, userID, currentSessionID, currentPassword, newPassword string) (PasswordChange, error) {
+	if strings.TrimSpace(userID) == "" {
+		return PasswordChange{}, ErrUserNotFound
+	}
+	if currentPassword == "" {
+		return PasswordChange{}, ErrInvalidCurrentPassword
+	}
+	if strings.TrimSpace(newPassword) == "" {
+		return PasswordChange{}, ErrInvalidPassword
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return PasswordChange{}, ErrUserNotFound
+		}
+		return PasswordChange{}, err
+	}
+	if err := s.verifyCurrentPassword(ctx, user, currentPassword); err != nil {
+		return PasswordChange{}, err
+	}
+	if err := s.checkPassword(ctx, user.ID, user.Email, newPassword); err != nil {
+		return PasswordChange{}, err
+	}
+
+	hashed, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return PasswordChange{}, err
+	}
+	if err := s.repo.UpdatePassword(ctx, user.ID, hashed); err != nil {
+		return PasswordChange{}, err
+	}
+
+	revoked, err := s.repo.RevokeSessionsExcept(ctx, user.ID, strings.TrimSpace(currentSessionID))
+	if err != nil {
+		return PasswordChange{}, err
+	}
+	if err := s.markSessionsRevoked(ctx, revoked); err != nil {
+		return PasswordChange{}, err
+	}
+
+	s.recordAudit(ctx, auditdomain.ActionPasswordChange, auditdomain.TargetUser, user.ID, nil, map[string]any{
+		"password_changed": true,
+		"revoked_sessions": len(revoked),
+	})
+	return PasswordChange{Email: user.Email, RevokedSessions: len(revoked)}, nil
+}
+
+// VerifyPassword re-authenticates a signed-in user before a sensitive
+// change, such as a new email address.
+func (s *Service) VerifyPassword(ctx context.Context, userID, password string) error {
+	if strings.TrimSpace(userID) == "" {
+		return ErrUserNotFound
+	}
+	if password == "" {
+		return ErrInvalidCurrentPassword
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return s.verifyCurrentPassword(ctx, user, password)
+}
+
+// verifyCurrentPassword checks the password of a signed-in user. A wrong
+// password counts towards the login lockout, so a stolen access token cannot
+// be used to guess it.
+func (s *Service) verifyCurrentPassword(ctx context.Context, user authdomain.User, password string) error {
+	if !user.IsActive {
+		return ErrUserDisabled
+	}
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		return ErrUserLocked
+	}
+	if err := s.hasher.Compare(user.PasswordHash, password); err != nil {
+		if s.maxLoginAttempts > 0 {
+			lockSeconds := int64(s.lockoutDuration.Seconds())
+			if err := s.repo.RecordLoginFailure(ctx, user.ID, s.maxLoginAttempts, lockSeconds); err != nil {
+				return err
+			}
+		}
+		return ErrInvalidCurrentPassword
+	}
+	return nil
+}
+
+type EmailVerificationRequest struct {
+	UserID     string
+	Email      string
+	Token      string
+	ExpiresAt  time.Time
+	ShouldSend bool
+}
+
+// emailVerification is stored under a verification token. The email pins
+// the token to the address it was sent to.
+type emailVerification struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+func (s *Service) Register(ctx context.Context, email, password string) (EmailVerificationRequest, error) {
+	normalized := strings.TrimSpace(strings.ToLower(email))
+	if normalized == "" {
+		return EmailVerificationRequest{}, ErrInvalidCredentials
+	}
+	if strings.TrimSpace(password) == "" {
+		return EmailVerificationRequest{}, ErrInvalidPassword
+	}
+	if s.cache == nil {
+		return EmailVerificationRequest{}, errors.New("auth: cache is nil")
+	}
+	if err := s.checkPassword(ctx, "", normalized, password); err != nil {
+		return EmailVerificationRequest{}, err
+	}
+
+	hashed, err := s.hasher.Hash(password)
+	if err != nil {
+		return EmailVerificationRequest{}, err
+	}
+
+	user, err := s.repo.CreateUser(ctx, normalized, hashed)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrConflict) {
+			return EmailVerificationRequest{}, ErrEmailAlreadyRegistered
+		}
+		return EmailVerificationRequest{}, err
+	}
+
+	return s.issueEmailVerification(ctx, user)
+}
+
+func (s *Service) ResendEmailVerification(ctx context.Context, email string) (EmailVerificationRequest, error) {
+	normalized := strings.TrimSpace(strings.ToLower(email))
+	if normalized == "" {
+		return EmailVerificationRequest{}, ErrInvalidCredentials
+	}
+	if s.cache == nil {
+		return EmailVerificationRequest{}, errors.New("auth: cache is nil")
+	}
+
+	user, err := s.repo.FindUserByEmail(ctx, normalized)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return EmailVerificationRequest{ShouldSend: false}, nil
+		}
+		return EmailVerificationRequest{}, err
+	}
+	if !user.IsActive || user.EmailVerifiedAt != nil {
+		return EmailVerificationRequest{ShouldSend: false}, nil
+	}
+
+	if s.verificationCooldown > 0 {
+		cooldownKey := fmt.Sprintf("auth:email_verification:cooldown:%s", user.ID)
+		ok, err := s.cache.SetIfNotExists(ctx, cooldownKey, "1", s.verificationCooldown)
+		if err != nil {
+			return EmailVerificationRequest{}, err
+		}
+		if !ok {
+			return EmailVerificationRequest{ShouldSend: false}, nil
+		}
+	}
+
+	return s.issueEmailVerification(ctx, user)
+}
+
+// RestartEmailVerification issues a new verification token after the user's
+// email changed, replacing any token sent to the previous address. Nothing is
+// issued when the email is already verified.
+func (s *Service) RestartEmailVerification(ctx context.Context, userID string) (EmailVerificationRequest, error) {
+	if s.cache == nil {
+		return EmailVerificationRequest{}, errors.New("auth: cache is nil")
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return EmailVerificationRequest{}, ErrUserNotFound
+		}
+		return EmailVerificationRequest{}, err
+	}
+	if user.EmailVerifiedAt != nil {
+		return EmailVerificationRequest{ShouldSend: false}, nil
+	}
+	// A new token is issued even for a disabled user, so the link sent to
+	// the previous address cannot verify the new one.
+	result, err := s.issueEmailVerification(ctx, user)
+	if err != nil {
+		return EmailVerificationRequest{}, err
+	}
+	result.ShouldSend = user.IsActive
+	return result, nil
+}
+
+func (s *Service) VerifyEmail(ctx context.Context, token string) error {
+	trimmedToken := strings.TrimSpace(token)
+	if trimmedToken == "" {
+		return ErrInvalidVerificationToken
+	}
+	if s.cache == nil {
+		return errors.New("auth: cache is nil")
+	}
+
+	tokenHash := hashToken(trimmedToken)
+	tokenKey := fmt.Sprintf("auth:email_verification:token:%s", tokenHash)
+
+	stored, err := s.cache.Eval(ctx, takeScript, []string{tokenKey})
+	if err != nil {
+		return err
+	}
+	raw, _ := stored.(string)
+	if raw == "" {
+		return ErrInvalidVerificationToken
+	}
+	var pending emailVerification
+	if err := json.Unmarshal([]byte(raw), &pending); err != nil || pending.UserID == "" || pending.Email == "" {
+		return ErrInvalidVerificationToken
+	}
+
+	// The token only verifies the address it was mailed to; if the email
+	// changed since, the update matches no row.
+	if err := s.repo.MarkEmailVerified(ctx, pending.UserID, pending.Email); err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	_ = s.cache.Delete(ctx, fmt.Sprintf("auth:email_verification:user:%s", pending.UserID))
 	return nil
 }
 
-func (s *Service) createAccessToken(userID string, roles, permissions []string, tokenVersion int) (string, int64, error) {
+func (s *Service) issueEmailVerification(ctx context.Context, user authdomain.User) (EmailVerificationRequest, error) {
+	rawToken, err := generateToken(32)
+	if err != nil {
+		return EmailVerificationRequest{}, err
+	}
+	tokenHash := hashToken(rawToken)
+	tokenKey := fmt.Sprintf("auth:email_verification:token:%s", tokenHash)
+	userKey := fmt.Sprintf("auth:email_verification:user:%s", user.ID)
+
+	if existing, err := s.cache.GetString(ctx, userKey); err == nil && existing != "" {
+		_ = s.cache.Delete(ctx, fmt.Sprintf("auth:email_verification:token:%s", existing))
+	}
+
+	ttl := s.verificationTTL
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	payload, err := json.Marshal(emailVerification{UserID: user.ID, Email: user.Email})
+	if err != nil {
+		return EmailVerificationRequest{}, err
+	}
+	if err := s.cache.SetWithTTL(ctx, tokenKey, string(payload), ttl); err != nil {
+		return EmailVerificationRequest{}, err
+	}
+	if err := s.cache.SetWithTTL(ctx, userKey, tokenHash, ttl); err != nil {
+		return EmailVerificationRequest{}, err
+	}
+
+	return EmailVerificationRequest{
+		UserID:     user.ID,
+		Email:      user.Email,
+		Token:      rawToken,
+		ExpiresAt:  time.Now().Add(ttl),
+		ShouldSend: true,
+	}, nil
+}
+
+func (s *Service) createAccessToken(userID, sessionID string, roles, permissions []string, grants []rbacdomain.ResourceGrant, tokenVersion int) (string, int64, error) {
 	if s == nil || s.tokenManager == nil {
 		return "", 0, errors.New("auth: token manager is nil")
 	}
-	return s.tokenManager.GenerateAccessToken(userID, roles, permissions, tokenVersion)
+	return s.tokenManager.GenerateAccessToken(userID, sessionID, roles, permissions, grants, tokenVersion)
 }
 
 func (s *Service) newRefreshToken() (string, string, time.Time, error) {
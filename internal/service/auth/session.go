@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	redisinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/redis"
+	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type Session struct {
+	authdomain.Session
+	Current bool
+}
+
+// ListSessions returns the user's active sessions, flagging currentSessionID.
+func (s *Service) ListSessions(ctx context.Context, userID, currentSessionID string) ([]Session, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, ErrSessionNotFound
+	}
+
+	sessions, err := s.repo.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Session, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, Session{
+			Session: session,
+			Current: currentSessionID != "" && session.ID == currentSessionID,
+		})
+	}
+	return result, nil
+}
+
+// RevokeSession signs one session out: its refresh token stops working and
+// access tokens carrying its id are rejected until they expire.
+func (s *Service) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	trimmedID := strings.TrimSpace(sessionID)
+	if strings.TrimSpace(userID) == "" || trimmedID == "" {
+		return ErrSessionNotFound
+	}
+
+	if err := s.repo.RevokeSession(ctx, userID, trimmedID); err != nil {
+		if errors.Is(err, authdomain.ErrNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return s.markSessionsRevoked(ctx, []string{trimmedID})
+}
+
+// RevokeOtherSessions revokes every session except keepSessionID. An empty
+// keepSessionID revokes all sessions. It returns the number revoked.
+func (s *Service) RevokeOtherSessions(ctx context.Context, userID, keepSessionID string) (int, error) {
+	if strings.TrimSpace(userID) == "" {
+		return 0, ErrSessionNotFound
+	}
+
+	revoked, err := s.repo.RevokeSessionsExcept(ctx, userID, strings.TrimSpace(keepSessionID))
+	if err != nil {
+		return 0, err
+	}
+	if err := s.markSessionsRevoked(ctx, revoked); err != nil {
+		return 0, err
+	}
+	return len(revoked), nil
+}
+
+func (s *Service) markSessionsRevoked(ctx context.Context, sessionIDs []string) error {
+	if s.cache == nil || len(sessionIDs) == 0 {
+		return nil
+	}
+	ttl := s.accessTTL
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+	for _, sessionID := range sessionIDs {
+		key := fmt.Sprintf("auth:session:revoked:%s", sessionID)
+		if err := s.cache.SetWithTTL(ctx, key, "1", ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) isSessionRevoked(ctx context.Context, sessionID string) (bool, error) {
+	if s.cache == nil {
+		return false, nil
+	}
+	_, err := s.cache.GetString(ctx, fmt.Sprintf("auth:session:revoked:%s", sessionID))
+	if err != nil {
+		if errors.Is(err, redisinfra.ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	buf[6] = (buf[6] & 0x0f) | 0x40
+	buf[8] = (buf[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:16]), nil
+}
@@ -1,17 +1,41 @@
 package auth
 
-import "time"
+import (
+	"time"
+
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
 
 type AccessClaims struct {
 	Subject      string
+	SessionID    string
 	Issuer       string
 	ExpiresAt    time.Time
 	Roles        []string
 	Permissions  []string
+	Grants       []rbacdomain.ResourceGrant
 	TokenVersion int
 }
 
 type TokenManager interface {
-	GenerateAccessToken(userID string, roles, permissions []string, tokenVersion int) (string, int64, error)
+	GenerateAccessToken(userID, sessionID string, roles, permissions []string, grants []rbacdomain.ResourceGrant, tokenVersion int) (string, int64, error)
 	ParseAccessToken(tokenString string) (AccessClaims, error)
 }
+
+// JSONWebKey is a public verification key as published in a JWKS document.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// KeySetProvider is implemented by token managers that sign with asymmetric keys.
+type KeySetProvider interface {
+	PublicKeys() []JSONWebKey
+}
@@ -46,6 +46,14 @@ type RedisQueue struct {
 	deadKey       string
 }
 
+// QueueDepths is the number of jobs in each queue state.
+type QueueDepths struct {
+	Pending    int64
+	Processing int64
+	Retry      int64
+	Dead       int64
+}
+
 func NewRedisQueue(cache redisinfra.Cache, opts QueueOptions) *RedisQueue {
 	prefix := strings.TrimSpace(opts.Prefix)
 	if prefix == "" {
@@ -146,6 +154,28 @@ func (q *RedisQueue) DeadLetter(ctx context.Context, job Job, reason string) err
 	return err
 }
 
+func (q *RedisQueue) Depths(ctx context.Context) (QueueDepths, error) {
+	if q == nil || q.cache == nil {
+		return QueueDepths{}, errors.New("email queue: cache is nil")
+	}
+
+	var depths QueueDepths
+	var err error
+	if depths.Pending, err = q.cache.LLen(ctx, q.pendingKey); err != nil {
+		return QueueDepths{}, err
+	}
+	if depths.Processing, err = q.cache.LLen(ctx, q.processingKey); err != nil {
+		return QueueDepths{}, err
+	}
+	if depths.Retry, err = q.cache.ZCard(ctx, q.retryKey); err != nil {
+		return QueueDepths{}, err
+	}
+	if depths.Dead, err = q.cache.LLen(ctx, q.deadKey); err != nil {
+		return QueueDepths{}, err
+	}
+	return depths, nil
+}
+
 func (q *RedisQueue) RequeueDue(ctx context.Context, limit int64) (int, error) {
 	if q == nil || q.cache == nil {
 		return 0, errors.New("email queue: cache is nil")
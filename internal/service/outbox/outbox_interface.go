@@ -0,0 +1,22 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
+)
+
+type Repository interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]eventdomain.Event, error)
+	MarkPublished(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id, lastError string, retryAt time.Time) error
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Sink receives relayed events. Delivery is at-least-once, so sinks must
+// tolerate the same event ID arriving more than once.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event eventdomain.Event) error
+}
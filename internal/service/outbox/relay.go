@@ -0,0 +1,174 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
+	"github.com/sirupsen/logrus"
+)
+
+type RelayOptions struct {
+	PollInterval    time.Duration
+	BatchSize       int
+	Lease           time.Duration
+	PublishTimeout  time.Duration
+	RetryDelays     []time.Duration
+	Retention       time.Duration
+	CleanupInterval time.Duration
+}
+
+// Relay moves committed outbox events to every sink. An event is marked
+// published only after all sinks accept it; otherwise it is retried later.
+type Relay struct {
+	repo  Repository
+	sinks []Sink
+	opts  RelayOptions
+}
+
+func NewRelay(repo Repository, sinks []Sink, opts RelayOptions) (*Relay, error) {
+	if repo == nil {
+		return nil, errors.New("outbox: repository is nil")
+	}
+	if len(sinks) == 0 {
+		return nil, errors.New("outbox: no sinks configured")
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.PublishTimeout <= 0 {
+		opts.PublishTimeout = 10 * time.Second
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = time.Duration(opts.BatchSize)*opts.PublishTimeout + time.Minute
+	}
+	if len(opts.RetryDelays) == 0 {
+		opts.RetryDelays = []time.Duration{
+			5 * time.Second,
+			30 * time.Second,
+			2 * time.Minute,
+			10 * time.Minute,
+		}
+	}
+	if opts.CleanupInterval <= 0 {
+		opts.CleanupInterval = time.Hour
+	}
+
+	return &Relay{
+		repo:  repo,
+		sinks: sinks,
+		opts:  opts,
+	}, nil
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	if r == nil {
+		return
+	}
+
+	if r.opts.Retention > 0 {
+		go r.cleanupLoop(ctx)
+	}
+
+	ticker := time.NewTicker(r.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while batches come back full.
+		for ctx.Err() == nil {
+			relayed, err := r.relayBatch(ctx)
+			if err != nil {
+				logrus.WithError(err).Warn("outbox relay claim failed")
+				break
+			}
+			if relayed < r.opts.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	events, err := r.repo.Claim(ctx, r.opts.BatchSize, r.opts.Lease)
+	if err != nil {
+		return 0, err
+	}
+	for _, event := range events {
+		if ctx.Err() != nil {
+			// Unhandled events are claimed again once their lease expires.
+			break
+		}
+		r.deliver(ctx, event)
+	}
+	return len(events), nil
+}
+
+func (r *Relay) deliver(ctx context.Context, event eventdomain.Event) {
+	for _, sink := range r.sinks {
+		publishCtx, cancel := context.WithTimeout(ctx, r.opts.PublishTimeout)
+		err := sink.Publish(publishCtx, event)
+		cancel()
+		if err == nil {
+			continue
+		}
+
+		delay := r.retryDelay(event.Attempts)
+		if markErr := r.repo.MarkFailed(ctx, event.ID, sink.Name()+": "+err.Error(), time.Now().Add(delay)); markErr != nil {
+			logrus.WithError(markErr).Warn("outbox relay retry scheduling failed")
+		}
+		logrus.WithFields(logrus.Fields{
+			"event_id":   event.ID,
+			"event_type": event.Type,
+			"sink":       sink.Name(),
+			"attempts":   event.Attempts,
+			"delay":      delay.String(),
+			"error":      err.Error(),
+		}).Warn("outbox relay publish failed")
+		return
+	}
+
+	if err := r.repo.MarkPublished(ctx, event.ID); err != nil {
+		logrus.WithError(err).WithField("event_id", event.ID).Warn("outbox relay mark published failed")
+	}
+}
+
+func (r *Relay) retryDelay(attempt int) time.Duration {
+	if attempt <= 0 {
+		return r.opts.RetryDelays[0]
+	}
+	if attempt <= len(r.opts.RetryDelays) {
+		return r.opts.RetryDelays[attempt-1]
+	}
+	return r.opts.RetryDelays[len(r.opts.RetryDelays)-1]
+}
+
+func (r *Relay) cleanupLoop(ctx context.Context) {
+	ticker := time.NewTicker(r.opts.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := r.repo.DeletePublishedBefore(ctx, time.Now().Add(-r.opts.Retention))
+			if err != nil {
+				logrus.WithError(err).Warn("outbox cleanup failed")
+				continue
+			}
+			if deleted > 0 {
+				logrus.WithField("deleted", deleted).Info("outbox cleanup completed")
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package outbox
+
+import (
+	"context"
+
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
+	"github.com/sirupsen/logrus"
+)
+
+// LogSink writes every event to the application log. It is the default sink
+// and a starting point for wiring a real broker.
+type LogSink struct{}
+
+func (LogSink) Name() string {
+	return "log"
+}
+
+func (LogSink) Publish(_ context.Context, event eventdomain.Event) error {
+	logrus.WithFields(logrus.Fields{
+		"event_id":       event.ID,
+		"event_type":     event.Type,
+		"aggregate_type": event.AggregateType,
+		"aggregate_id":   event.AggregateID,
+		"occurred_at":    event.OccurredAt,
+		"payload":        string(event.Payload),
+	}).Info("domain event")
+	return nil
+}
+
+// SinkFunc adapts a function to the Sink interface.
+type SinkFunc struct {
+	SinkName string
+	Fn       func(ctx context.Context, event eventdomain.Event) error
+}
+
+func (s SinkFunc) Name() string {
+	return s.SinkName
+}
+
+func (s SinkFunc) Publish(ctx context.Context, event eventdomain.Event) error {
+	return s.Fn(ctx, event)
+}
@@ -0,0 +1,20 @@
+package payment
+
+import (
+	"context"
+
+	paymentdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/payment"
+)
+
+type Repository interface {
+	SaveInvoice(ctx context.Context, invoice paymentdomain.Invoice, source string) (paymentdomain.Invoice, error)
+	ApplyStatusTransition(ctx context.Context, input paymentdomain.TransitionInput) (paymentdomain.Invoice, bool, error)
+	GetInvoice(ctx context.Context, xenditInvoiceID string) (paymentdomain.Invoice, error)
+	ListInvoicesByExternalID(ctx context.Context, externalID string) ([]paymentdomain.Invoice, error)
+	ListStatusTransitions(ctx context.Context, invoiceID string) ([]paymentdomain.StatusTransition, error)
+}
+
+// AuditRecorder stores audit entries for completed changes.
+type AuditRecorder interface {
+	Record(ctx context.Context, action, targetType, targetID string, before, after any)
+}
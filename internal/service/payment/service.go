@@ -8,6 +8,7 @@ import (
 
 	redisinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/redis"
 	xenditinfra "github.com/mzulfanw/boilerplate-go-fiber/infrastructure/xendit"
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
 	paymentdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/payment"
 	"github.com/sirupsen/logrus"
 	"github.com/xendit/xendit-go/v7/invoice"
@@ -23,18 +24,36 @@ const (
 type Service struct {
 	xenditClient xenditinfra.XenditClient
 	cache        redisinfra.Cache
+	repo         Repository
+	audit        AuditRecorder
 }
 
-func NewService(xenditClient xenditinfra.XenditClient, cache redisinfra.Cache) (*Service, error) {
+func NewService(xenditClient xenditinfra.XenditClient, cache redisinfra.Cache, repo Repository) (*Service, error) {
 	if xenditClient == nil {
 		return nil, errors.New("payment: xendit client is nil")
 	}
+	if repo == nil {
+		return nil, errors.New("payment: repository is nil")
+	}
 	return &Service{
 		xenditClient: xenditClient,
 		cache:        cache,
+		repo:         repo,
 	}, nil
 }
 
+// SetAuditRecorder enables audit entries for invoice creation and expiry.
+func (s *Service) SetAuditRecorder(recorder AuditRecorder) {
+	s.audit = recorder
+}
+
+func (s *Service) recordAudit(ctx context.Context, action, targetID string, before, after any) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(ctx, action, auditdomain.TargetInvoice, targetID, before, after)
+}
+
 func (s *Service) CheckBalance(ctx context.Context) (float64, error) {
 	return s.xenditClient.BalanceInquiry(ctx)
 }
@@ -168,6 +187,21 @@ func (s *Service) CreateInvoice(ctx context.Context, input paymentdomain.CreateI
 		}
 	}
 
+	// The webhook path upserts unknown invoices, so a failed write here is
+	// recovered on the first callback instead of failing the request.
+	stored := mapXenditInvoice(createdInvoice)
+	if _, err := s.repo.SaveInvoice(ctx, stored, paymentdomain.TransitionSourceAPI); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"invoice_id":  createdInvoice.Id,
+			"external_id": externalID,
+		}).WithError(err).Warn("payment invoice store failed")
+	}
+
+	s.recordAudit(ctx, auditdomain.ActionInvoiceCreate, stored.XenditInvoiceID, nil, map[string]any{
+		"external_id": stored.ExternalID,
+		"amount":      stored.Amount,
+		"status":      string(stored.Status),
+	})
 	return createdInvoice, false, nil
 }
 
@@ -176,7 +210,58 @@ func (s *Service) ExpireInvoice(ctx context.Context, invoiceId string) (invoice.
 	if normalizedID == "" {
 		return invoice.Invoice{}, paymentdomain.ErrInvalidInput
 	}
-	return s.xenditClient.ExpireInvoice(ctx, normalizedID)
+	var before map[string]any
+	if stored, err := s.repo.GetInvoice(ctx, normalizedID); err == nil {
+		before = map[string]any{"status": string(stored.Status)}
+	}
+	expiredInvoice, err := s.xenditClient.ExpireInvoice(ctx, normalizedID)
+	if err != nil {
+		return invoice.Invoice{}, err
+	}
+
+	snapshot := mapXenditInvoice(expiredInvoice)
+	_, _, err = s.repo.ApplyStatusTransition(ctx, paymentdomain.TransitionInput{
+		Invoice:  snapshot,
+		ToStatus: snapshot.Status,
+		Source:   paymentdomain.TransitionSourceAPI,
+	})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"invoice_id":  normalizedID,
+			"external_id": snapshot.ExternalID,
+			"status":      snapshot.Status,
+		}).WithError(err).Warn("payment invoice status store failed")
+	}
+
+	s.recordAudit(ctx, auditdomain.ActionInvoiceExpire, normalizedID, before, map[string]any{"status": string(snapshot.Status)})
+	return expiredInvoice, nil
+}
+
+// GetStoredInvoice returns the locally persisted invoice and its status
+// history without calling Xendit.
+func (s *Service) GetStoredInvoice(ctx context.Context, invoiceId string) (paymentdomain.Invoice, []paymentdomain.StatusTransition, error) {
+	normalizedID := strings.TrimSpace(invoiceId)
+	if normalizedID == "" {
+		return paymentdomain.Invoice{}, nil, paymentdomain.ErrInvalidInput
+	}
+
+	stored, err := s.repo.GetInvoice(ctx, normalizedID)
+	if err != nil {
+		return paymentdomain.Invoice{}, nil, err
+	}
+	transitions, err := s.repo.ListStatusTransitions(ctx, stored.ID)
+	if err != nil {
+		return paymentdomain.Invoice{}, nil, err
+	}
+	return stored, transitions, nil
+}
+
+func (s *Service) ListInvoicesByExternalID(ctx context.Context, externalID string) ([]paymentdomain.Invoice, error) {
+	normalizedID := strings.TrimSpace(externalID)
+	if normalizedID == "" {
+		return nil, paymentdomain.ErrInvalidInput
+	}
+	return s.repo.ListInvoicesByExternalID(ctx, normalizedID)
 }
 
 func (s *Service) HandleInvoiceWebhook(ctx context.Context, payload invoice.InvoiceCallback, payloadHash string) error {
@@ -189,9 +274,15 @@ func (s *Service) HandleInvoiceWebhook(ctx context.Context, payload invoice.Invo
 	if strings.TrimSpace(payloadHash) == "" {
 		return paymentdomain.ErrInvalidInput
 	}
+	status, err := paymentdomain.ParseInvoiceStatus(payload.Status)
+	if err != nil {
+		return err
+	}
 
+	dedupKey := ""
 	if s.cache != nil {
-		key := buildWebhookDedupKey(payload, payloadHash)
+		dedupKey = buildWebhookDedupKey(payload, payloadHash)
+		key := dedupKey
 		acquired, err := s.cache.SetIfNotExists(ctx, key, time.Now().UTC().Format(time.RFC3339Nano), webhookDedupTTL)
 		if err != nil {
 			return err
@@ -206,7 +297,27 @@ func (s *Service) HandleInvoiceWebhook(ctx context.Context, payload invoice.Invo
 		}
 	}
 
-	// TODO: persist payment status transition when payment storage is available.
+	_, applied, err := s.repo.ApplyStatusTransition(ctx, paymentdomain.TransitionInput{
+		Invoice:     mapInvoiceCallback(payload, status),
+		ToStatus:    status,
+		Source:      paymentdomain.TransitionSourceWebhook,
+		PayloadHash: payloadHash,
+	})
+	if err != nil {
+		// Release the dedup key so a retry of the same delivery is processed
+		// again; rejected transitions stay deduplicated.
+		if dedupKey != "" && !errors.Is(err, paymentdomain.ErrInvalidTransition) {
+			_ = s.cache.Delete(ctx, dedupKey)
+		}
+		return err
+	}
+	if !applied {
+		logrus.WithFields(logrus.Fields{
+			"invoice_id":  payload.Id,
+			"external_id": payload.ExternalId,
+			"status":      status,
+		}).Info("xendit webhook status unchanged")
+	}
 	return nil
 }
 
@@ -234,6 +345,62 @@ func buildWebhookDedupKey(payload invoice.InvoiceCallback, payloadHash string) s
 	return "payment:webhook:xendit:invoice:" + invoiceID + ":status:" + status + ":hash:" + payloadHash
 }
 
+func mapXenditInvoice(source invoice.Invoice) paymentdomain.Invoice {
+	result := paymentdomain.Invoice{
+		ExternalID: source.ExternalId,
+		Amount:     source.Amount,
+		Status:     paymentdomain.InvoiceStatus(strings.ToUpper(string(source.Status))),
+		InvoiceURL: source.InvoiceUrl,
+	}
+	if source.Id != nil {
+		result.XenditInvoiceID = *source.Id
+	}
+	if source.Currency != nil {
+		result.Currency = string(*source.Currency)
+	}
+	if source.PayerEmail != nil {
+		result.PayerEmail = *source.PayerEmail
+	}
+	if source.Description != nil {
+		result.Description = *source.Description
+	}
+	if !source.ExpiryDate.IsZero() {
+		expiresAt := source.ExpiryDate.UTC()
+		result.ExpiresAt = &expiresAt
+	}
+	return result
+}
+
+func mapInvoiceCallback(payload invoice.InvoiceCallback, status paymentdomain.InvoiceStatus) paymentdomain.Invoice {
+	result := paymentdomain.Invoice{
+		XenditInvoiceID: strings.TrimSpace(payload.Id),
+		ExternalID:      strings.TrimSpace(payload.ExternalId),
+		Amount:          payload.Amount,
+		PaidAmount:      payload.PaidAmount,
+		Currency:        payload.Currency,
+		Status:          status,
+	}
+	if payload.PayerEmail != nil {
+		result.PayerEmail = *payload.PayerEmail
+	}
+	if payload.Description != nil {
+		result.Description = *payload.Description
+	}
+	if payload.PaymentMethod != nil {
+		result.PaymentMethod = *payload.PaymentMethod
+	}
+	if payload.PaymentChannel != nil {
+		result.PaymentChannel = *payload.PaymentChannel
+	}
+	if payload.PaidAt != nil {
+		if paidAt, err := time.Parse(time.RFC3339, *payload.PaidAt); err == nil {
+			paidAt = paidAt.UTC()
+			result.PaidAt = &paidAt
+		}
+	}
+	return result
+}
+
 func normalizeOptionalString(value *string) *string {
 	if value == nil {
 		return nil
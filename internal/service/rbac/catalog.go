@@ -0,0 +1,70 @@
+package rbac
+
+import (
+	"context"
+
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
+
+// CatalogStatus compares the permissions routes require with the stored
+// ones. Missing are required but not stored; Orphaned are stored but cover
+// no required permission, not even through a wildcard.
+type CatalogStatus struct {
+	Missing  []string
+	Orphaned []string
+}
+
+// CatalogStatus reports how the stored permissions differ from required.
+func (s *Service) CatalogStatus(ctx context.Context, required []string) (CatalogStatus, error) {
+	stored, err := s.repo.ListPermissionNames(ctx)
+	if err != nil {
+		return CatalogStatus{}, err
+	}
+	return compareCatalog(stored, required), nil
+}
+
+// SyncCatalog inserts the required permissions that are missing and marks
+// every required permission as a system permission, since routes depend on
+// it. It returns the inserted names and the orphaned ones, which are left
+// in place.
+func (s *Service) SyncCatalog(ctx context.Context, required []string) ([]string, []string, error) {
+	required = normalizeIDs(required)
+	if len(required) == 0 {
+		return nil, nil, nil
+	}
+	created, err := s.repo.EnsureSystemPermissions(ctx, required)
+	if err != nil {
+		return nil, nil, err
+	}
+	status, err := s.CatalogStatus(ctx, required)
+	if err != nil {
+		return nil, nil, err
+	}
+	return created, status.Orphaned, nil
+}
+
+func compareCatalog(stored, required []string) CatalogStatus {
+	var status CatalogStatus
+	storedSet := make(map[string]struct{}, len(stored))
+	for _, name := range stored {
+		storedSet[name] = struct{}{}
+	}
+	for _, name := range required {
+		if _, ok := storedSet[name]; !ok {
+			status.Missing = append(status.Missing, name)
+		}
+	}
+	for _, name := range stored {
+		orphaned := true
+		for _, permission := range required {
+			if rbacdomain.PermissionMatches(name, permission) {
+				orphaned = false
+				break
+			}
+		}
+		if orphaned {
+			status.Orphaned = append(status.Orphaned, name)
+		}
+	}
+	return status
+}
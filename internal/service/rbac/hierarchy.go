@@ -0,0 +1,233 @@
+package rbac
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
+
+// ListRoleParents returns the roles roleID directly inherits from.
+func (s *Service) ListRoleParents(ctx context.Context, roleID string) ([]rbacdomain.Role, error) {
+	if strings.TrimSpace(roleID) == "" {
+		return nil, rbacdomain.ErrInvalidInput
+	}
+	return s.repo.ListRoleParents(ctx, roleID)
+}
+
+// ReplaceRoleParents sets the direct parents of roleID. It returns ErrCycle
+// if the change would make a role inherit from itself, and, with role
+// approval enabled, ErrApprovalRequired if a new parent is or inherits from
+// a role flagged requires_approval. The checks read the hierarchy before
+// writing, so two concurrent edits can still close a loop; permission
+// resolution tolerates that, and the next edit will reject it.
+func (s *Service) ReplaceRoleParents(ctx context.Context, roleID string, parentIDs []string) error {
+	roleID = strings.TrimSpace(roleID)
+	if roleID == "" {
+		return rbacdomain.ErrInvalidInput
+	}
+	normalized := normalizeIDs(parentIDs)
+
+	current, err := s.repo.ListRoleParents(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	edges, err := s.repo.ListRoleHierarchy(ctx)
+	if err != nil {
+		return err
+	}
+	if createsCycle(edges, roleID, normalized) {
+		return rbacdomain.ErrCycle
+	}
+	if s.roleApproval {
+		approvalIDs, err := s.repo.ListApprovalRoleIDs(ctx)
+		if err != nil {
+			return err
+		}
+		if inheritsApprovalRole(edges, addedIDs(roleIDs(current), normalized), approvalIDs) {
+			return rbacdomain.ErrApprovalRequired
+		}
+	}
+	if err := s.repo.ReplaceRoleParents(ctx, roleID, normalized); err != nil {
+		return err
+	}
+
+	after := normalized
+	if after == nil {
+		after = []string{}
+	}
+	s.recordAudit(ctx, auditdomain.ActionRoleParentsReplace, auditdomain.TargetRole, roleID,
+		map[string]any{"parent_ids": roleIDs(current)},
+		map[string]any{"parent_ids": after})
+	return nil
+}
+
+// AddRoleParent makes roleID inherit from parentID.
+func (s *Service) AddRoleParent(ctx context.Context, roleID, parentID string) error {
+	parentID = strings.TrimSpace(parentID)
+	if strings.TrimSpace(roleID) == "" || parentID == "" {
+		return rbacdomain.ErrInvalidInput
+	}
+	current, err := s.repo.ListRoleParents(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	return s.ReplaceRoleParents(ctx, roleID, append(roleIDs(current), parentID))
+}
+
+// RemoveRoleParent stops roleID inheriting from parentID. It returns
+// ErrNotFound if parentID is not a direct parent.
+func (s *Service) RemoveRoleParent(ctx context.Context, roleID, parentID string) error {
+	parentID = strings.TrimSpace(parentID)
+	if strings.TrimSpace(roleID) == "" || parentID == "" {
+		return rbacdomain.ErrInvalidInput
+	}
+	current, err := s.repo.ListRoleParents(ctx, roleID)
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]string, 0, len(current))
+	found := false
+	for _, parent := range current {
+		if parent.ID == parentID {
+			found = true
+			continue
+		}
+		remaining = append(remaining, parent.ID)
+	}
+	if !found {
+		return rbacdomain.ErrNotFound
+	}
+	return s.ReplaceRoleParents(ctx, roleID, remaining)
+}
+
+// GetRoleConstraints returns the approval flag and exclusions of roleID.
+func (s *Service) GetRoleConstraints(ctx context.Context, roleID string) (rbacdomain.RoleConstraints, error) {
+	if strings.TrimSpace(roleID) == "" {
+		return rbacdomain.RoleConstraints{}, rbacdomain.ErrInvalidInput
+	}
+	return s.repo.GetRoleConstraints(ctx, roleID)
+}
+
+// ReplaceRoleConstraints sets whether grants of roleID need approval and
+// which roles a user may not hold together with it. Exclusions are
+// symmetric, so the excluded roles list roleID in turn.
+func (s *Service) ReplaceRoleConstraints(ctx context.Context, roleID string, requiresApproval bool, excludedIDs []string) error {
+	roleID = strings.TrimSpace(roleID)
+	if roleID == "" {
+		return rbacdomain.ErrInvalidInput
+	}
+	normalized := normalizeIDs(excludedIDs)
+	for _, id := range normalized {
+		if id == roleID {
+			return rbacdomain.ErrInvalidInput
+		}
+	}
+
+	current, err := s.repo.GetRoleConstraints(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ReplaceRoleConstraints(ctx, roleID, requiresApproval, normalized); err != nil {
+		return err
+	}
+
+	after := normalized
+	if after == nil {
+		after = []string{}
+	}
+	s.recordAudit(ctx, auditdomain.ActionRoleConstraintsReplace, auditdomain.TargetRole, roleID,
+		map[string]any{"requires_approval": current.RequiresApproval, "excluded_role_ids": roleIDs(current.ExcludedRoles)},
+		map[string]any{"requires_approval": requiresApproval, "excluded_role_ids": after})
+	return nil
+}
+
+// createsCycle reports whether giving roleID the parents parentIDs, in place
+// of its current ones, would let roleID reach itself by following parent
+// edges.
+func createsCycle(edges []rbacdomain.RoleParent, roleID string, parentIDs []string) bool {
+	parents := make(map[string][]string, len(edges))
+	for _, edge := range edges {
+		if edge.RoleID == roleID {
+			continue
+		}
+		parents[edge.RoleID] = append(parents[edge.RoleID], edge.ParentRoleID)
+	}
+	parents[roleID] = parentIDs
+
+	visited := make(map[string]struct{}, len(parents))
+	stack := append([]string(nil), parentIDs...)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == roleID {
+			return true
+		}
+		if _, ok := visited[current]; ok {
+			continue
+		}
+		visited[current] = struct{}{}
+		stack = append(stack, parents[current]...)
+	}
+	return false
+}
+
+// inheritsApprovalRole reports whether any of parentIDs is, or inherits from,
+// one of approvalIDs.
+func inheritsApprovalRole(edges []rbacdomain.RoleParent, parentIDs, approvalIDs []string) bool {
+	if len(parentIDs) == 0 || len(approvalIDs) == 0 {
+		return false
+	}
+	approval := make(map[string]struct{}, len(approvalIDs))
+	for _, id := range approvalIDs {
+		approval[id] = struct{}{}
+	}
+	parents := make(map[string][]string, len(edges))
+	for _, edge := range edges {
+		parents[edge.RoleID] = append(parents[edge.RoleID], edge.ParentRoleID)
+	}
+
+	visited := make(map[string]struct{}, len(parents))
+	stack := append([]string(nil), parentIDs...)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if _, ok := approval[current]; ok {
+			return true
+		}
+		if _, ok := visited[current]; ok {
+			continue
+		}
+		visited[current] = struct{}{}
+		stack = append(stack, parents[current]...)
+	}
+	return false
+}
+
+// addedIDs returns the ids in next that are not in current. Existing edges
+// are left alone so they can still be removed.
+func addedIDs(current, next []string) []string {
+	existing := make(map[string]struct{}, len(current))
+	for _, id := range current {
+		existing[id] = struct{}{}
+	}
+	var added []string
+	for _, id := range next {
+		if _, ok := existing[id]; !ok {
+			added = append(added, id)
+		}
+	}
+	return added
+}
+
+func roleIDs(roles []rbacdomain.Role) []string {
+	ids := make([]string, 0, len(roles))
+	for _, role := range roles {
+		ids = append(ids, role.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,49 @@
+package rbac
+
+import (
+	"testing"
+
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
+
+func TestCreatesCycle(t *testing.T) {
+	// editor -> viewer, admin -> editor, auditor -> viewer
+	edges := []rbacdomain.RoleParent{
+		{RoleID: "editor", ParentRoleID: "viewer"},
+		{RoleID: "admin", ParentRoleID: "editor"},
+		{RoleID: "auditor", ParentRoleID: "viewer"},
+	}
+
+	cases := []struct {
+		name    string
+		edges   []rbacdomain.RoleParent
+		roleID  string
+		parents []string
+		want    bool
+	}{
+		{"self parent", edges, "viewer", []string{"viewer"}, true},
+		{"direct loop", edges, "viewer", []string{"editor"}, true},
+		{"indirect loop", edges, "viewer", []string{"admin"}, true},
+		{"new acyclic parent", edges, "auditor", []string{"editor"}, false},
+		{"no parents", edges, "admin", nil, false},
+		// editor's current parent viewer is replaced, so the old edge
+		// editor -> viewer no longer closes viewer -> editor.
+		{"replacing existing parents", []rbacdomain.RoleParent{
+			{RoleID: "editor", ParentRoleID: "viewer"},
+			{RoleID: "viewer", ParentRoleID: "base"},
+		}, "editor", []string{"base"}, false},
+		{"replacing keeps other loops", []rbacdomain.RoleParent{
+			{RoleID: "editor", ParentRoleID: "viewer"},
+			{RoleID: "viewer", ParentRoleID: "admin"},
+		}, "admin", []string{"editor"}, true},
+		// admin -> {editor, auditor} -> viewer shares an ancestor without
+		// looping.
+		{"diamond", edges, "admin", []string{"editor", "auditor"}, false},
+		{"diamond with unknown parent", edges, "admin", []string{"editor", "auditor", "billing"}, false},
+	}
+	for _, tc := range cases {
+		if got := createsCycle(tc.edges, tc.roleID, tc.parents); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
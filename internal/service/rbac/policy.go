@@ -0,0 +1,100 @@
+package rbac
+
+import (
+	"context"
+
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
+
+// PolicyImportOptions controls ImportPolicy. DryRun only computes the
+// changes; Prune also deletes roles and permissions missing from the policy.
+type PolicyImportOptions struct {
+	DryRun bool
+	Prune  bool
+}
+
+// PolicyImportResult lists the changes an import made, or would make when
+// Applied is false.
+type PolicyImportResult struct {
+	Changes []rbacdomain.PolicyChange
+	Applied bool
+}
+
+// ExportPolicy returns every role and permission with their grants and
+// parents, sorted by name.
+func (s *Service) ExportPolicy(ctx context.Context) (rbacdomain.Policy, error) {
+	policy, err := s.repo.ExportPolicy(ctx)
+	if err != nil {
+		return rbacdomain.Policy{}, err
+	}
+	return policy.Normalize(), nil
+}
+
+// ImportPolicy makes the stored roles and permissions match policy. System
+// entries cannot be pruned and system roles must keep the system permissions
+// they hold; either violation returns ErrProtected before anything changes.
+func (s *Service) ImportPolicy(ctx context.Context, policy rbacdomain.Policy, opts PolicyImportOptions) (PolicyImportResult, error) {
+	policy = policy.Normalize()
+	if err := policy.Validate(); err != nil {
+		return PolicyImportResult{}, err
+	}
+	current, err := s.ExportPolicy(ctx)
+	if err != nil {
+		return PolicyImportResult{}, err
+	}
+	if err := checkPolicyProtection(current, policy, opts.Prune); err != nil {
+		return PolicyImportResult{}, err
+	}
+
+	changes := rbacdomain.DiffPolicy(current, policy, opts.Prune)
+	if opts.DryRun || len(changes) == 0 {
+		return PolicyImportResult{Changes: changes}, nil
+	}
+	if err := s.repo.ApplyPolicy(ctx, policy, opts.Prune); err != nil {
+		return PolicyImportResult{}, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionPolicyImport, auditdomain.TargetPolicy, "rbac", nil,
+		map[string]any{"prune": opts.Prune, "changes": changes})
+	return PolicyImportResult{Changes: changes, Applied: true}, nil
+}
+
+func checkPolicyProtection(current, desired rbacdomain.Policy, prune bool) error {
+	declaredPermissions := make(map[string]struct{}, len(desired.Permissions))
+	for _, permission := range desired.Permissions {
+		declaredPermissions[permission.Name] = struct{}{}
+	}
+	systemPermissions := make(map[string]struct{})
+	for _, permission := range current.Permissions {
+		if !permission.System {
+			continue
+		}
+		systemPermissions[permission.Name] = struct{}{}
+		if _, ok := declaredPermissions[permission.Name]; prune && !ok {
+			return rbacdomain.ErrProtected
+		}
+	}
+
+	declaredRoles := make(map[string]rbacdomain.PolicyRole, len(desired.Roles))
+	for _, role := range desired.Roles {
+		declaredRoles[role.Name] = role
+	}
+	for _, role := range current.Roles {
+		if !role.System {
+			continue
+		}
+		next, ok := declaredRoles[role.Name]
+		if !ok {
+			if prune {
+				return rbacdomain.ErrProtected
+			}
+			continue
+		}
+		for _, name := range role.Permissions {
+			if _, system := systemPermissions[name]; system && !rbacdomain.Can(next.Permissions, name) {
+				return rbacdomain.ErrProtected
+			}
+		}
+	}
+	return nil
+}
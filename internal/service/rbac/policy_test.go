@@ -0,0 +1,80 @@
+package rbac
+
+import (
+	"errors"
+	"testing"
+
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
+
+func TestCheckPolicyProtection(t *testing.T) {
+	current := rbacdomain.Policy{
+		Permissions: []rbacdomain.PolicyPermission{
+			{Name: "report.read"},
+			{Name: "user.read", System: true},
+			{Name: "user.update", System: true},
+		},
+		Roles: []rbacdomain.PolicyRole{
+			{Name: "admin", System: true, Permissions: []string{"report.read", "user.read", "user.update"}},
+			{Name: "viewer", Permissions: []string{"report.read", "user.read"}},
+		},
+	}
+	permissions := current.Permissions
+	role := func(name string, perms ...string) rbacdomain.PolicyRole {
+		return rbacdomain.PolicyRole{Name: name, Permissions: perms}
+	}
+
+	cases := []struct {
+		name    string
+		desired rbacdomain.Policy
+		prune   bool
+		wantErr bool
+	}{
+		{"unchanged", current, true, false},
+		{"non-system role loses system permission", rbacdomain.Policy{
+			Permissions: permissions,
+			Roles:       []rbacdomain.PolicyRole{role("admin", "user.read", "user.update"), role("viewer")},
+		}, true, false},
+		{"system role loses non-system permission", rbacdomain.Policy{
+			Permissions: permissions,
+			Roles:       []rbacdomain.PolicyRole{role("admin", "user.read", "user.update")},
+		}, false, false},
+		{"system role loses system permission", rbacdomain.Policy{
+			Permissions: permissions,
+			Roles:       []rbacdomain.PolicyRole{role("admin", "user.read")},
+		}, false, true},
+		{"wildcard covers system permissions", rbacdomain.Policy{
+			Permissions: append([]rbacdomain.PolicyPermission{{Name: "*"}}, permissions...),
+			Roles:       []rbacdomain.PolicyRole{role("admin", "*")},
+		}, false, false},
+		{"segment wildcard covers system permissions", rbacdomain.Policy{
+			Permissions: append([]rbacdomain.PolicyPermission{{Name: "user.*"}}, permissions...),
+			Roles:       []rbacdomain.PolicyRole{role("admin", "user.*")},
+		}, false, false},
+		{"system role missing without prune", rbacdomain.Policy{
+			Permissions: permissions,
+			Roles:       []rbacdomain.PolicyRole{role("viewer")},
+		}, false, false},
+		{"system role pruned", rbacdomain.Policy{
+			Permissions: permissions,
+			Roles:       []rbacdomain.PolicyRole{role("viewer")},
+		}, true, true},
+		{"system permission missing without prune", rbacdomain.Policy{
+			Permissions: []rbacdomain.PolicyPermission{{Name: "*"}},
+			Roles:       []rbacdomain.PolicyRole{role("admin", "*")},
+		}, false, false},
+		{"system permission pruned", rbacdomain.Policy{
+			Permissions: []rbacdomain.PolicyPermission{{Name: "*"}},
+			Roles:       []rbacdomain.PolicyRole{role("admin", "*")},
+		}, true, true},
+	}
+	for _, tc := range cases {
+		err := checkPolicyProtection(current, tc.desired, tc.prune)
+		if tc.wantErr && !errors.Is(err, rbacdomain.ErrProtected) {
+			t.Fatalf("%s: expected ErrProtected, got %v", tc.name, err)
+		}
+		if !tc.wantErr && err != nil {
+			t.Fatalf("%s: expected no error, got %v", tc.name, err)
+		}
+	}
+}
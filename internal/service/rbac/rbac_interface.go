@@ -3,22 +3,49 @@ package rbac
 import (
 	"context"
 
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
 	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 )
 
 type Repository interface {
 	ListRoles(ctx context.Context, filter rbacdomain.ListFilterRole) (rbacdomain.ListRole, error)
 	GetRole(ctx context.Context, id string) (rbacdomain.Role, error)
+	GetRoleByName(ctx context.Context, name string) (rbacdomain.Role, error)
+	EnsureRole(ctx context.Context, name, description string) (rbacdomain.Role, error)
 	CreateRole(ctx context.Context, name, description string) (rbacdomain.Role, error)
 	UpdateRole(ctx context.Context, id, name, description string) (rbacdomain.Role, error)
 	DeleteRole(ctx context.Context, id string) error
 
 	ListPermissions(ctx context.Context, filter rbacdomain.ListFilterPermission) (rbacdomain.ListPermission, error)
 	GetPermission(ctx context.Context, id string) (rbacdomain.Permission, error)
+	GetPermissionsByIDs(ctx context.Context, ids []string) ([]rbacdomain.Permission, error)
+	ListPermissionNames(ctx context.Context) ([]string, error)
+	EnsureSystemPermissions(ctx context.Context, names []string) ([]string, error)
 	CreatePermission(ctx context.Context, name, description string) (rbacdomain.Permission, error)
 	UpdatePermission(ctx context.Context, id, name, description string) (rbacdomain.Permission, error)
 	DeletePermission(ctx context.Context, id string) error
 
 	ListRolePermissions(ctx context.Context, roleID string) ([]rbacdomain.Permission, error)
-	ReplaceRolePermissions(ctx context.Context, roleID string, permissionIDs []string) error
-}
\ No newline at end of file
+	ReplaceRolePermissions(ctx context.Context, roleID string, permissionIDs []string, events ...eventdomain.Event) error
+	GrantAllPermissions(ctx context.Context, roleID string) (int64, error)
+
+	ListRoleParents(ctx context.Context, roleID string) ([]rbacdomain.Role, error)
+	ListRoleHierarchy(ctx context.Context) ([]rbacdomain.RoleParent, error)
+	ReplaceRoleParents(ctx context.Context, roleID string, parentIDs []string) error
+
+	GetRoleConstraints(ctx context.Context, roleID string) (rbacdomain.RoleConstraints, error)
+	ListApprovalRoleIDs(ctx context.Context) ([]string, error)
+	ReplaceRoleConstraints(ctx context.Context, roleID string, requiresApproval bool, excludedIDs []string) error
+
+	ExportPolicy(ctx context.Context) (rbacdomain.Policy, error)
+	ApplyPolicy(ctx context.Context, policy rbacdomain.Policy, prune bool) error
+
+	ListUserResourceRoles(ctx context.Context, userID string) ([]rbacdomain.ResourceRole, error)
+	GrantResourceRole(ctx context.Context, userID, roleID, resourceType, resourceID string) error
+	RevokeResourceRole(ctx context.Context, userID, roleID, resourceType, resourceID string) error
+}
+
+// AuditRecorder stores audit entries for completed changes.
+type AuditRecorder interface {
+	Record(ctx context.Context, action, targetType, targetID string, before, after any)
+}
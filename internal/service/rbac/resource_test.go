@@ -0,0 +1,61 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
+
+type fakeResourceRepo struct {
+	Repository
+	approvalIDs []string
+	edges       []rbacdomain.RoleParent
+	granted     []string
+}
+
+func (f *fakeResourceRepo) ListApprovalRoleIDs(_ context.Context) ([]string, error) {
+	return f.approvalIDs, nil
+}
+
+func (f *fakeResourceRepo) ListRoleHierarchy(_ context.Context) ([]rbacdomain.RoleParent, error) {
+	return f.edges, nil
+}
+
+func (f *fakeResourceRepo) GrantResourceRole(_ context.Context, _, roleID, _, _ string) error {
+	f.granted = append(f.granted, roleID)
+	return nil
+}
+
+func TestGrantResourceRoleRejectsApprovalRoles(t *testing.T) {
+	edges := []rbacdomain.RoleParent{{RoleID: "ops", ParentRoleID: "admin"}}
+
+	cases := []struct {
+		name     string
+		approval bool
+		roleID   string
+		wantErr  error
+	}{
+		{"plain role", true, "billing", nil},
+		{"approval role", true, "admin", rbacdomain.ErrApprovalRequired},
+		{"inherits approval role", true, "ops", rbacdomain.ErrApprovalRequired},
+		{"approval disabled", false, "admin", nil},
+	}
+	for _, tc := range cases {
+		repo := &fakeResourceRepo{approvalIDs: []string{"admin"}, edges: edges}
+		service, err := NewService(repo)
+		if err != nil {
+			t.Fatalf("expected service, got error: %v", err)
+		}
+		service.SetRoleApproval(tc.approval)
+
+		err = service.GrantResourceRole(context.Background(), "u1", tc.roleID, "merchant", "m-1")
+		if !errors.Is(err, tc.wantErr) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.wantErr, err)
+		}
+		if granted := len(repo.granted) > 0; granted != (tc.wantErr == nil) {
+			t.Fatalf("%s: expected granted %v, got %v", tc.name, tc.wantErr == nil, repo.granted)
+		}
+	}
+}
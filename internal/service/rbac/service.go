@@ -6,11 +6,15 @@ import (
 	"sort"
 	"strings"
 
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
 	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 )
 
 type Service struct {
-	repo Repository
+	repo         Repository
+	audit        AuditRecorder
+	roleApproval bool
 }
 
 func NewService(repo Repository) (*Service, error) {
@@ -20,6 +24,18 @@ func NewService(repo Repository) (*Service, error) {
 	return &Service{repo: repo}, nil
 }
 
+// SetAuditRecorder enables audit entries for role and permission changes.
+func (s *Service) SetAuditRecorder(recorder AuditRecorder) {
+	s.audit = recorder
+}
+
+// SetRoleApproval rejects new parent edges and resource-scoped grants that
+// would hand out a role flagged requires_approval, so neither the hierarchy
+// nor resource grants can be used to skip the approval of a grant.
+func (s *Service) SetRoleApproval(enabled bool) {
+	s.roleApproval = enabled
+}
+
 func (s *Service) ListRoles(ctx context.Context, filter rbacdomain.ListFilterRole) (rbacdomain.ListRole, error) {
 	filter.Search = strings.TrimSpace(filter.Search)
 	return s.repo.ListRoles(ctx, filter)
@@ -32,12 +48,34 @@ func (s *Service) GetRole(ctx context.Context, id string) (rbacdomain.Role, erro
 	return s.repo.GetRole(ctx, id)
 }
 
+func (s *Service) GetRoleByName(ctx context.Context, name string) (rbacdomain.Role, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return rbacdomain.Role{}, rbacdomain.ErrInvalidInput
+	}
+	return s.repo.GetRoleByName(ctx, name)
+}
+
+// EnsureRole returns the role with the given name, creating it if needed.
+func (s *Service) EnsureRole(ctx context.Context, name, description string) (rbacdomain.Role, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return rbacdomain.Role{}, rbacdomain.ErrInvalidInput
+	}
+	return s.repo.EnsureRole(ctx, name, strings.TrimSpace(description))
+}
+
 func (s *Service) CreateRole(ctx context.Context, name, description string) (rbacdomain.Role, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return rbacdomain.Role{}, rbacdomain.ErrInvalidInput
 	}
-	return s.repo.CreateRole(ctx, name, strings.TrimSpace(description))
+	role, err := s.repo.CreateRole(ctx, name, strings.TrimSpace(description))
+	if err != nil {
+		return rbacdomain.Role{}, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionRoleCreate, auditdomain.TargetRole, role.ID, nil, newRoleSnapshot(role))
+	return role, nil
 }
 
 func (s *Service) UpdateRole(ctx context.Context, id, name, description string) (rbacdomain.Role, error) {
@@ -48,14 +86,37 @@ func (s *Service) UpdateRole(ctx context.Context, id, name, description string)
 	if name == "" {
 		return rbacdomain.Role{}, rbacdomain.ErrInvalidInput
 	}
-	return s.repo.UpdateRole(ctx, id, name, strings.TrimSpace(description))
+	current, err := s.repo.GetRole(ctx, id)
+	if err != nil {
+		return rbacdomain.Role{}, err
+	}
+	if current.IsSystem && current.Name != name {
+		return rbacdomain.Role{}, rbacdomain.ErrProtected
+	}
+	role, err := s.repo.UpdateRole(ctx, id, name, strings.TrimSpace(description))
+	if err != nil {
+		return rbacdomain.Role{}, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionRoleUpdate, auditdomain.TargetRole, role.ID, newRoleSnapshot(current), newRoleSnapshot(role))
+	return role, nil
 }
 
 func (s *Service) DeleteRole(ctx context.Context, id string) error {
 	if strings.TrimSpace(id) == "" {
 		return rbacdomain.ErrInvalidInput
 	}
-	return s.repo.DeleteRole(ctx, id)
+	current, err := s.repo.GetRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current.IsSystem {
+		return rbacdomain.ErrProtected
+	}
+	if err := s.repo.DeleteRole(ctx, id); err != nil {
+		return err
+	}
+	s.recordAudit(ctx, auditdomain.ActionRoleDelete, auditdomain.TargetRole, id, newRoleSnapshot(current), nil)
+	return nil
 }
 
 func (s *Service) ListPermissions(ctx context.Context, filter rbacdomain.ListFilterPermission) (rbacdomain.ListPermission, error) {
@@ -75,7 +136,12 @@ func (s *Service) CreatePermission(ctx context.Context, name, description string
 	if name == "" {
 		return rbacdomain.Permission{}, rbacdomain.ErrInvalidInput
 	}
-	return s.repo.CreatePermission(ctx, name, strings.TrimSpace(description))
+	permission, err := s.repo.CreatePermission(ctx, name, strings.TrimSpace(description))
+	if err != nil {
+		return rbacdomain.Permission{}, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionPermissionCreate, auditdomain.TargetPermission, permission.ID, nil, newPermissionSnapshot(permission))
+	return permission, nil
 }
 
 func (s *Service) UpdatePermission(ctx context.Context, id, name, description string) (rbacdomain.Permission, error) {
@@ -86,14 +152,37 @@ func (s *Service) UpdatePermission(ctx context.Context, id, name, description st
 	if name == "" {
 		return rbacdomain.Permission{}, rbacdomain.ErrInvalidInput
 	}
-	return s.repo.UpdatePermission(ctx, id, name, strings.TrimSpace(description))
+	current, err := s.repo.GetPermission(ctx, id)
+	if err != nil {
+		return rbacdomain.Permission{}, err
+	}
+	if current.IsSystem && current.Name != name {
+		return rbacdomain.Permission{}, rbacdomain.ErrProtected
+	}
+	permission, err := s.repo.UpdatePermission(ctx, id, name, strings.TrimSpace(description))
+	if err != nil {
+		return rbacdomain.Permission{}, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionPermissionUpdate, auditdomain.TargetPermission, permission.ID, newPermissionSnapshot(current), newPermissionSnapshot(permission))
+	return permission, nil
 }
 
 func (s *Service) DeletePermission(ctx context.Context, id string) error {
 	if strings.TrimSpace(id) == "" {
 		return rbacdomain.ErrInvalidInput
 	}
-	return s.repo.DeletePermission(ctx, id)
+	current, err := s.repo.GetPermission(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current.IsSystem {
+		return rbacdomain.ErrProtected
+	}
+	if err := s.repo.DeletePermission(ctx, id); err != nil {
+		return err
+	}
+	s.recordAudit(ctx, auditdomain.ActionPermissionDelete, auditdomain.TargetPermission, id, newPermissionSnapshot(current), nil)
+	return nil
 }
 
 func (s *Service) ListRolePermissions(ctx context.Context, roleID string) ([]rbacdomain.Permission, error) {
@@ -103,12 +192,103 @@ func (s *Service) ListRolePermissions(ctx context.Context, roleID string) ([]rba
 	return s.repo.ListRolePermissions(ctx, roleID)
 }
 
+// ReplaceRolePermissions sets the role's permissions. A system role must keep
+// every system permission it holds, either directly or through a wildcard in
+// the new set, so it can never lose access the application relies on.
 func (s *Service) ReplaceRolePermissions(ctx context.Context, roleID string, permissionIDs []string) error {
 	if strings.TrimSpace(roleID) == "" {
 		return rbacdomain.ErrInvalidInput
 	}
 	normalized := normalizeIDs(permissionIDs)
-	return s.repo.ReplaceRolePermissions(ctx, roleID, normalized)
+	eventPermissionIDs := normalized
+	if eventPermissionIDs == nil {
+		eventPermissionIDs = []string{}
+	}
+	event, err := eventdomain.New(eventdomain.TypeRolePermissionsReplaced, eventdomain.AggregateRole, roleID, eventdomain.RolePermissionsReplaced{
+		RoleID:        roleID,
+		PermissionIDs: eventPermissionIDs,
+	})
+	if err != nil {
+		return err
+	}
+	currentPermissions, err := s.repo.ListRolePermissions(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkSystemGrants(ctx, roleID, currentPermissions, normalized); err != nil {
+		return err
+	}
+	if err := s.repo.ReplaceRolePermissions(ctx, roleID, normalized, event); err != nil {
+		return err
+	}
+	currentIDs := make([]string, 0, len(currentPermissions))
+	for _, permission := range currentPermissions {
+		currentIDs = append(currentIDs, permission.ID)
+	}
+	sort.Strings(currentIDs)
+	s.recordAudit(ctx, auditdomain.ActionRolePermsReplace, auditdomain.TargetRole, roleID,
+		map[string]any{"permission_ids": currentIDs},
+		map[string]any{"permission_ids": eventPermissionIDs})
+	return nil
+}
+
+// GrantAllPermissions adds every existing permission to the role.
+func (s *Service) GrantAllPermissions(ctx context.Context, roleID string) (int64, error) {
+	if strings.TrimSpace(roleID) == "" {
+		return 0, rbacdomain.ErrInvalidInput
+	}
+	return s.repo.GrantAllPermissions(ctx, roleID)
+}
+
+// checkSystemGrants returns ErrProtected when roleID is a system role and the
+// permissions in next no longer cover one of its current system permissions.
+func (s *Service) checkSystemGrants(ctx context.Context, roleID string, current []rbacdomain.Permission, next []string) error {
+	role, err := s.repo.GetRole(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if !role.IsSystem {
+		return nil
+	}
+	permissions, err := s.repo.GetPermissionsByIDs(ctx, next)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, permission.Name)
+	}
+	for _, permission := range current {
+		if permission.IsSystem && !rbacdomain.Can(names, permission.Name) {
+			return rbacdomain.ErrProtected
+		}
+	}
+	return nil
+}
+
+type roleSnapshot struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func newRoleSnapshot(role rbacdomain.Role) roleSnapshot {
+	return roleSnapshot{Name: role.Name, Description: role.Description}
+}
+
+type permissionSnapshot struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func newPermissionSnapshot(permission rbacdomain.Permission) permissionSnapshot {
+	return permissionSnapshot{Name: permission.Name, Description: permission.Description}
+}
+
+func (s *Service) recordAudit(ctx context.Context, action, targetType, targetID string, before, after any) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(ctx, action, targetType, targetID, before, after)
 }
 
 func normalizeIDs(ids []string) []string {
@@ -5,24 +5,47 @@ import (
 	"errors"
 	"sort"
 	"strings"
+	"time"
 
-	"golang.org/x/crypto/bcrypt"
-
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
 	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 	userdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/user"
 )
 
-const passwordHashCost = 12
-
 type Service struct {
-	repo Repository
+	repo           Repository
+	hasher         PasswordHasher
+	passwordPolicy PasswordPolicy
+	audit          AuditRecorder
+	roleApproval   bool
 }
 
-func NewService(repo Repository) (*Service, error) {
+func NewService(repo Repository, hasher PasswordHasher) (*Service, error) {
 	if repo == nil {
 		return nil, errors.New("user: repository is nil")
 	}
-	return &Service{repo: repo}, nil
+	if hasher == nil {
+		return nil, errors.New("user: password hasher is nil")
+	}
+	return &Service{repo: repo, hasher: hasher}, nil
+}
+
+// SetAuditRecorder enables audit entries for user management changes.
+func (s *Service) SetAuditRecorder(recorder AuditRecorder) {
+	s.audit = recorder
+}
+
+// SetPasswordPolicy checks passwords set through CreateUser and UpdateUser.
+// Without a policy any non-blank password is accepted.
+func (s *Service) SetPasswordPolicy(policy PasswordPolicy) {
+	s.passwordPolicy = policy
+}
+
+// SetRoleApproval makes grants of roles flagged requires_approval wait for
+// a second administrator instead of applying immediately.
+func (s *Service) SetRoleApproval(enabled bool) {
+	s.roleApproval = enabled
 }
 
 func (s *Service) ListUsers(ctx context.Context, filter userdomain.ListFilter) (userdomain.ListResult, error) {
@@ -42,8 +65,11 @@ func (s *Service) CreateUser(ctx context.Context, email, password string, isActi
 	if normalizedEmail == "" || strings.TrimSpace(password) == "" {
 		return userdomain.User{}, userdomain.ErrInvalidInput
 	}
+	if err := s.checkPassword(ctx, "", normalizedEmail, password); err != nil {
+		return userdomain.User{}, err
+	}
 
-	passwordHash, err := hashPassword(password)
+	passwordHash, err := s.hasher.Hash(password)
 	if err != nil {
 		return userdomain.User{}, err
 	}
@@ -54,7 +80,26 @@ func (s *Service) CreateUser(ctx context.Context, email, password string, isActi
 	}
 
 	normalizedRoles := normalizeIDs(roleIDs)
-	return s.repo.CreateUser(ctx, normalizedEmail, passwordHash, active, normalizedRoles)
+	if err := s.checkExclusiveRoles(ctx, "", normalizedRoles); err != nil {
+		return userdomain.User{}, err
+	}
+	if s.roleApproval {
+		approvalIDs, err := s.repo.ListApprovalRoles(ctx, normalizedRoles)
+		if err != nil {
+			return userdomain.User{}, err
+		}
+		if len(approvalIDs) > 0 {
+			return userdomain.User{}, userdomain.ErrApprovalRequired
+		}
+	}
+	user, err := s.repo.CreateUser(ctx, normalizedEmail, passwordHash, active, normalizedRoles)
+	if err != nil {
+		return userdomain.User{}, err
+	}
+	after := newUserSnapshot(user)
+	after.RoleIDs = normalizedRoles
+	s.recordAudit(ctx, auditdomain.ActionUserCreate, user.ID, nil, after)
+	return user, nil
 }
 
 func (s *Service) UpdateUser(ctx context.Context, id string, email, password *string, isActive *bool) (userdomain.User, error) {
@@ -69,6 +114,7 @@ func (s *Service) UpdateUser(ctx context.Context, id string, email, password *st
 	if err != nil {
 		return userdomain.User{}, err
 	}
+	before := newUserSnapshot(current)
 
 	bumpTokenVersion := false
 	if email != nil {
@@ -83,7 +129,10 @@ func (s *Service) UpdateUser(ctx context.Context, id string, email, password *st
 		if strings.TrimSpace(*password) == "" {
 			return userdomain.User{}, userdomain.ErrInvalidInput
 		}
-		passwordHash, err := hashPassword(*password)
+		if err := s.checkPassword(ctx, current.ID, current.Email, *password); err != nil {
+			return userdomain.User{}, err
+		}
+		passwordHash, err := s.hasher.Hash(*password)
 		if err != nil {
 			return userdomain.User{}, err
 		}
@@ -91,43 +140,396 @@ func (s *Service) UpdateUser(ctx context.Context, id string, email, password *st
 		bumpTokenVersion = true
 	}
 
+	var events []eventdomain.Event
 	if isActive != nil {
 		if current.IsActive != *isActive {
 			bumpTokenVersion = true
+			event, err := userStatusEvent(current.ID, current.Email, *isActive)
+			if err != nil {
+				return userdomain.User{}, err
+			}
+			events = append(events, event)
 		}
 		current.IsActive = *isActive
 	}
 
-	return s.repo.UpdateUser(ctx, id, current.Email, current.PasswordHash, current.IsActive, bumpTokenVersion)
+	updated, err := s.repo.UpdateUser(ctx, id, current.Email, current.PasswordHash, current.IsActive, bumpTokenVersion, events...)
+	if err != nil {
+		return userdomain.User{}, err
+	}
+	after := newUserSnapshot(updated)
+	after.PasswordChanged = password != nil
+	s.recordAudit(ctx, auditdomain.ActionUserUpdate, updated.ID, before, after)
+	return updated, nil
 }
 
 func (s *Service) DeleteUser(ctx context.Context, id string) error {
 	if strings.TrimSpace(id) == "" {
 		return userdomain.ErrInvalidInput
 	}
-	return s.repo.DeleteUser(ctx, id)
+	current, err := s.repo.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteUser(ctx, id); err != nil {
+		return err
+	}
+	s.recordAudit(ctx, auditdomain.ActionUserDelete, id, newUserSnapshot(current), nil)
+	return nil
+}
+
+// ResetMFA removes the user's TOTP enrollment and recovery codes so they can
+// sign in with a password and enroll again.
+func (s *Service) ResetMFA(ctx context.Context, id string) error {
+	if strings.TrimSpace(id) == "" {
+		return userdomain.ErrInvalidInput
+	}
+	if err := s.repo.ResetMFA(ctx, id); err != nil {
+		return err
+	}
+	s.recordAudit(ctx, auditdomain.ActionUserMFAReset, id, nil, nil)
+	return nil
 }
 
-func (s *Service) ListUserRoles(ctx context.Context, userID string) ([]rbacdomain.Role, error) {
+func (s *Service) ListUserRoles(ctx context.Context, userID string) ([]rbacdomain.UserRole, error) {
 	if strings.TrimSpace(userID) == "" {
 		return nil, userdomain.ErrInvalidInput
 	}
 	return s.repo.ListUserRoles(ctx, userID)
 }
 
-func (s *Service) ReplaceUserRoles(ctx context.Context, userID string, roleIDs []string) error {
+// ReplaceUserRoles sets the user's global roles to roleIDs. Roles the user
+// already holds keep their validity window. Sets holding both roles of an
+// exclusive pair are rejected. With role approval enabled, roles that
+// require approval and are not held permanently yet are returned as pending
+// requests instead of being granted.
+func (s *Service) ReplaceUserRoles(ctx context.Context, userID string, roleIDs []string) ([]userdomain.RoleGrantRequest, error) {
 	if strings.TrimSpace(userID) == "" {
-		return userdomain.ErrInvalidInput
+		return nil, userdomain.ErrInvalidInput
+	}
+	currentRoles, err := s.repo.ListUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	normalized := normalizeIDs(roleIDs)
+	if err := s.checkExclusiveRoles(ctx, userID, normalized); err != nil {
+		return nil, err
+	}
+	currentIDs := make([]string, 0, len(currentRoles))
+	for _, role := range currentRoles {
+		currentIDs = append(currentIDs, role.Role.ID)
+	}
+
+	applied, deferred := normalized, []string(nil)
+	if s.roleApproval {
+		applied, deferred, err = s.splitApprovalRoles(ctx, currentRoles, normalized)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.ReplaceUserRoles(ctx, userID, applied); err != nil {
+		return nil, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionUserRolesReplace, userID, roleIDsSnapshot(currentIDs), roleIDsSnapshot(applied))
+
+	requests := make([]userdomain.RoleGrantRequest, 0, len(deferred))
+	for _, roleID := range deferred {
+		request, err := s.requestRoleGrant(ctx, userID, roleID, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// GrantUserRole gives the user roleID for the window between validFrom and
+// validUntil, e.g. an on-call admin role for four hours. Nil bounds are open,
+// so passing neither makes the grant permanent. Granting a role the user
+// already holds replaces its window. When the role requires approval the
+// grant is stored as a pending request and returned instead.
+func (s *Service) GrantUserRole(ctx context.Context, userID, roleID string, validFrom, validUntil *time.Time) (*userdomain.RoleGrantRequest, error) {
+	userID, roleID = strings.TrimSpace(userID), strings.TrimSpace(roleID)
+	if userID == "" || roleID == "" {
+		return nil, userdomain.ErrInvalidInput
+	}
+	if validUntil != nil {
+		if !validUntil.After(time.Now()) {
+			return nil, userdomain.ErrInvalidInput
+		}
+		if validFrom != nil && !validUntil.After(*validFrom) {
+			return nil, userdomain.ErrInvalidInput
+		}
+	}
+	if err := s.checkExclusiveGrant(ctx, userID, roleID); err != nil {
+		return nil, err
+	}
+	if s.roleApproval {
+		approvalIDs, err := s.repo.ListApprovalRoles(ctx, []string{roleID})
+		if err != nil {
+			return nil, err
+		}
+		if len(approvalIDs) > 0 {
+			request, err := s.requestRoleGrant(ctx, userID, roleID, validFrom, validUntil)
+			if err != nil {
+				return nil, err
+			}
+			return &request, nil
+		}
+	}
+	if err := s.repo.GrantUserRole(ctx, userID, roleID, validFrom, validUntil); err != nil {
+		return nil, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionUserRoleGrant, userID, nil, map[string]any{
+		"role_id":     roleID,
+		"valid_from":  validFrom,
+		"valid_until": validUntil,
+	})
+	return nil, nil
+}
+
+// ListRoleGrantRequests returns grant requests with status, pending ones
+// when status is empty.
+func (s *Service) ListRoleGrantRequests(ctx context.Context, status string) ([]userdomain.RoleGrantRequest, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	switch status {
+	case "":
+		status = userdomain.RoleRequestPending
+	case "all":
+		status = ""
+	case userdomain.RoleRequestPending, userdomain.RoleRequestApproved, userdomain.RoleRequestRejected:
+	default:
+		return nil, userdomain.ErrInvalidInput
+	}
+	return s.repo.ListRoleGrantRequests(ctx, status)
+}
+
+// ApproveRoleGrantRequest assigns the requested role. The approver is the
+// acting user, who must be neither the requester nor the grantee, and the
+// role must still be compatible with the roles the grantee holds.
+func (s *Service) ApproveRoleGrantRequest(ctx context.Context, id string) (userdomain.RoleGrantRequest, error) {
+	request, approverID, err := s.loadDecision(ctx, id)
+	if err != nil {
+		return userdomain.RoleGrantRequest{}, err
+	}
+	if err := s.checkExclusiveGrant(ctx, request.UserID, request.RoleID); err != nil {
+		return userdomain.RoleGrantRequest{}, err
+	}
+	approved, err := s.repo.DecideRoleGrantRequest(ctx, request.ID, approverID, true)
+	if err != nil {
+		return userdomain.RoleGrantRequest{}, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionUserRoleApprove, approved.UserID, nil, roleRequestSnapshot(approved))
+	return approved, nil
+}
+
+// RejectRoleGrantRequest closes a pending request without assigning the
+// role. The same separation rules as for approval apply.
+func (s *Service) RejectRoleGrantRequest(ctx context.Context, id string) (userdomain.RoleGrantRequest, error) {
+	request, deciderID, err := s.loadDecision(ctx, id)
+	if err != nil {
+		return userdomain.RoleGrantRequest{}, err
+	}
+	rejected, err := s.repo.DecideRoleGrantRequest(ctx, request.ID, deciderID, false)
+	if err != nil {
+		return userdomain.RoleGrantRequest{}, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionUserRoleReject, rejected.UserID, nil, roleRequestSnapshot(rejected))
+	return rejected, nil
+}
+
+func (s *Service) loadDecision(ctx context.Context, id string) (userdomain.RoleGrantRequest, string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return userdomain.RoleGrantRequest{}, "", userdomain.ErrInvalidInput
+	}
+	// A decision needs an identifiable user to compare with the requester;
+	// API keys act as their owner.
+	deciderID := auditdomain.ActorFromContext(ctx).UserID
+	if deciderID == "" {
+		return userdomain.RoleGrantRequest{}, "", userdomain.ErrSelfApproval
+	}
+	request, err := s.repo.GetRoleGrantRequest(ctx, id)
+	if err != nil {
+		return userdomain.RoleGrantRequest{}, "", err
+	}
+	if request.Status != userdomain.RoleRequestPending {
+		return userdomain.RoleGrantRequest{}, "", userdomain.ErrRequestNotPending
+	}
+	if deciderID == request.RequestedBy || deciderID == request.UserID {
+		return userdomain.RoleGrantRequest{}, "", userdomain.ErrSelfApproval
+	}
+	return request, deciderID, nil
+}
+
+func (s *Service) requestRoleGrant(ctx context.Context, userID, roleID string, validFrom, validUntil *time.Time) (userdomain.RoleGrantRequest, error) {
+	request, err := s.repo.CreateRoleGrantRequest(ctx, userdomain.RoleGrantRequest{
+		UserID:      userID,
+		RoleID:      roleID,
+		ValidFrom:   validFrom,
+		ValidUntil:  validUntil,
+		RequestedBy: auditdomain.ActorFromContext(ctx).UserID,
+	})
+	if err != nil {
+		return userdomain.RoleGrantRequest{}, err
+	}
+	s.recordAudit(ctx, auditdomain.ActionUserRoleRequest, userID, nil, roleRequestSnapshot(request))
+	return request, nil
+}
+
+// splitApprovalRoles separates desired into roles applied now and roles
+// that need an approved request first. Only permanent grants that are
+// already active count as held: a temporary or not yet started grant of an
+// approval role is requested again, so replacing a role set cannot turn it
+// into a permanent one. Such a grant stays applied as well, and the
+// repository keeps its window until the request is approved.
+func (s *Service) splitApprovalRoles(ctx context.Context, current []rbacdomain.UserRole, desired []string) ([]string, []string, error) {
+	approvalIDs, err := s.repo.ListApprovalRoles(ctx, desired)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(approvalIDs) == 0 {
+		return desired, nil, nil
+	}
+	now := time.Now()
+	held := make(map[string]struct{}, len(current))
+	permanent := make(map[string]struct{}, len(current))
+	for _, role := range current {
+		held[role.Role.ID] = struct{}{}
+		if role.ValidUntil == nil && (role.ValidFrom == nil || !role.ValidFrom.After(now)) {
+			permanent[role.Role.ID] = struct{}{}
+		}
+	}
+	needsApproval := make(map[string]struct{}, len(approvalIDs))
+	for _, id := range approvalIDs {
+		if _, ok := permanent[id]; !ok {
+			needsApproval[id] = struct{}{}
+		}
+	}
+
+	applied := make([]string, 0, len(desired))
+	var deferred []string
+	for _, id := range desired {
+		if _, ok := needsApproval[id]; ok {
+			deferred = append(deferred, id)
+			if _, ok := held[id]; !ok {
+				continue
+			}
+		}
+		applied = append(applied, id)
 	}
-	return s.repo.ReplaceUserRoles(ctx, userID, normalizeIDs(roleIDs))
+	return applied, deferred, nil
 }
 
-func hashPassword(password string) (string, error) {
-	hashed, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
+// checkExclusiveRoles checks roleIDs as the user's global roles against
+// each other and against the roles the user holds on individual resources.
+func (s *Service) checkExclusiveRoles(ctx context.Context, userID string, roleIDs []string) error {
+	exclusive, err := s.repo.HasExclusiveRoles(ctx, userID, roleIDs)
 	if err != nil {
-		return "", err
+		return err
+	}
+	if exclusive {
+		return userdomain.ErrExclusiveRoles
+	}
+	return nil
+}
+
+// checkExclusiveGrant checks roleID against the global and resource-scoped
+// roles the user already holds.
+func (s *Service) checkExclusiveGrant(ctx context.Context, userID, roleID string) error {
+	currentRoles, err := s.repo.ListUserRoles(ctx, userID)
+	if err != nil {
+		return err
+	}
+	roleIDs := make([]string, 0, len(currentRoles)+1)
+	for _, role := range currentRoles {
+		roleIDs = append(roleIDs, role.Role.ID)
+	}
+	return s.checkExclusiveRoles(ctx, userID, normalizeIDs(append(roleIDs, roleID)))
+}
+
+// ExpireUserRoles removes role assignments whose window has ended and
+// invalidates the affected users' access tokens. It returns how many
+// assignments were removed.
+func (s *Service) ExpireUserRoles(ctx context.Context) (int, error) {
+	expired, err := s.repo.DeleteExpiredUserRoles(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	byUser := make(map[string][]string)
+	for _, assignment := range expired {
+		byUser[assignment.UserID] = append(byUser[assignment.UserID], assignment.RoleID)
+	}
+	for userID, roleIDs := range byUser {
+		s.recordAudit(ctx, auditdomain.ActionUserRoleExpire, userID, roleIDsSnapshot(roleIDs), nil)
+	}
+	return len(expired), nil
+}
+
+// userSnapshot is the audited view of a user; the password hash is never
+// recorded, only whether it changed.
+type userSnapshot struct {
+	Email           string   `json:"email"`
+	IsActive        bool     `json:"is_active"`
+	RoleIDs         []string `json:"role_ids,omitempty"`
+	PasswordChanged bool     `json:"password_changed,omitempty"`
+}
+
+func newUserSnapshot(user userdomain.User) userSnapshot {
+	return userSnapshot{Email: user.Email, IsActive: user.IsActive}
+}
+
+func roleRequestSnapshot(request userdomain.RoleGrantRequest) map[string]any {
+	return map[string]any{
+		"request_id":  request.ID,
+		"role_id":     request.RoleID,
+		"status":      request.Status,
+		"valid_from":  request.ValidFrom,
+		"valid_until": request.ValidUntil,
+	}
+}
+
+func roleIDsSnapshot(ids []string) map[string]any {
+	sorted := append([]string{}, ids...)
+	sort.Strings(sorted)
+	return map[string]any{"role_ids": sorted}
+}
+
+func (s *Service) recordAudit(ctx context.Context, action, targetID string, before, after any) {
+	if s.audit == nil {
+		return
+	}
+	s.audit.Record(ctx, action, auditdomain.TargetUser, targetID, before, after)
+}
+
+func userStatusEvent(userID, email string, isActive bool) (eventdomain.Event, error) {
+	eventType := eventdomain.TypeUserDeactivated
+	if isActive {
+		eventType = eventdomain.TypeUserActivated
+	}
+	return eventdomain.New(eventType, eventdomain.AggregateUser, userID, eventdomain.UserStatusChanged{
+		UserID:   userID,
+		Email:    email,
+		IsActive: isActive,
+	})
+}
+
+// checkPassword applies the password policy, if one is set. For an existing
+// user (userID not empty) the recent passwords are checked too.
+func (s *Service) checkPassword(ctx context.Context, userID, email, password string) error {
+	if s.passwordPolicy == nil {
+		return nil
+	}
+	var history []string
+	if userID != "" && s.passwordPolicy.HistorySize() > 0 {
+		var err error
+		history, err = s.repo.ListPasswordHistory(ctx, userID, s.passwordPolicy.HistorySize())
+		if err != nil {
+			return err
+		}
 	}
-	return string(hashed), nil
+	return s.passwordPolicy.Check(password, email, history)
 }
 
 func normalizeEmail(email string) string {
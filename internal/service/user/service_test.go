@@ -0,0 +1,237 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+	userdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/user"
+)
+
+type fakeRepo struct {
+	Repository
+	held      []rbacdomain.UserRole
+	approval  map[string]bool
+	requests  map[string]userdomain.RoleGrantRequest
+	replaced  []string
+	granted   []string
+	decidedBy string
+}
+
+func (f *fakeRepo) ListUserRoles(_ context.Context, _ string) ([]rbacdomain.UserRole, error) {
+	return f.held, nil
+}
+
+func (f *fakeRepo) HasExclusiveRoles(_ context.Context, _ string, _ []string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeRepo) ListApprovalRoles(_ context.Context, roleIDs []string) ([]string, error) {
+	var ids []string
+	for _, id := range roleIDs {
+		if f.approval[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeRepo) ReplaceUserRoles(_ context.Context, _ string, roleIDs []string) error {
+	f.replaced = roleIDs
+	return nil
+}
+
+func (f *fakeRepo) GrantUserRole(_ context.Context, _, roleID string, _, _ *time.Time) error {
+	f.granted = append(f.granted, roleID)
+	return nil
+}
+
+func (f *fakeRepo) CreateRoleGrantRequest(_ context.Context, request userdomain.RoleGrantRequest) (userdomain.RoleGrantRequest, error) {
+	request.ID = "req-" + request.RoleID
+	request.Status = userdomain.RoleRequestPending
+	return request, nil
+}
+
+func (f *fakeRepo) GetRoleGrantRequest(_ context.Context, id string) (userdomain.RoleGrantRequest, error) {
+	request, ok := f.requests[id]
+	if !ok {
+		return userdomain.RoleGrantRequest{}, userdomain.ErrRequestNotFound
+	}
+	return request, nil
+}
+
+func (f *fakeRepo) DecideRoleGrantRequest(_ context.Context, id, deciderID string, approve bool) (userdomain.RoleGrantRequest, error) {
+	request := f.requests[id]
+	request.Status = userdomain.RoleRequestRejected
+	if approve {
+		request.Status = userdomain.RoleRequestApproved
+	}
+	request.DecidedBy = deciderID
+	f.decidedBy = deciderID
+	return request, nil
+}
+
+type fakeHasher struct{}
+
+func (fakeHasher) Hash(password string) (string, error) {
+	return "hashed:" + password, nil
+}
+
+func newTestService(t *testing.T, repo *fakeRepo, approval bool) *Service {
+	t.Helper()
+	service, err := NewService(repo, fakeHasher{})
+	if err != nil {
+		t.Fatalf("expected service, got error: %v", err)
+	}
+	service.SetRoleApproval(approval)
+	return service
+}
+
+func heldRoles(ids ...string) []rbacdomain.UserRole {
+	roles := make([]rbacdomain.UserRole, 0, len(ids))
+	for _, id := range ids {
+		roles = append(roles, rbacdomain.UserRole{Role: rbacdomain.Role{ID: id}})
+	}
+	return roles
+}
+
+func actorContext(userID, apiKeyID string) context.Context {
+	return auditdomain.WithActor(context.Background(), auditdomain.Actor{UserID: userID, APIKeyID: apiKeyID})
+}
+
+func TestReplaceUserRolesDefersApprovalRoles(t *testing.T) {
+	later := time.Now().Add(4 * time.Hour)
+	temporaryAdmin := append(heldRoles("viewer"), rbacdomain.UserRole{Role: rbacdomain.Role{ID: "admin"}, ValidUntil: &later})
+	scheduledAdmin := []rbacdomain.UserRole{{Role: rbacdomain.Role{ID: "admin"}, ValidFrom: &later}}
+
+	cases := []struct {
+		name         string
+		approval     bool
+		held         []rbacdomain.UserRole
+		desired      []string
+		wantApplied  []string
+		wantDeferred []string
+	}{
+		{"approval disabled", false, nil, []string{"admin", "viewer"}, []string{"admin", "viewer"}, nil},
+		{"no approval roles", true, nil, []string{"editor", "viewer"}, []string{"editor", "viewer"}, nil},
+		{"new approval role deferred", true, heldRoles("viewer"), []string{"admin", "viewer"}, []string{"viewer"}, []string{"admin"}},
+		{"held approval role kept", true, heldRoles("admin"), []string{"admin", "viewer"}, []string{"admin", "viewer"}, nil},
+		{"mixed", true, heldRoles("auditor"), []string{"admin", "auditor", "viewer"}, []string{"auditor", "viewer"}, []string{"admin"}},
+		{"only approval roles", true, nil, []string{"admin", "auditor"}, []string{}, []string{"admin", "auditor"}},
+		// A temporary grant is kept with its window, but replacing the role
+		// set must not make it permanent without approval.
+		{"temporary approval role", true, temporaryAdmin, []string{"admin", "viewer"}, []string{"admin", "viewer"}, []string{"admin"}},
+		{"scheduled approval role", true, scheduledAdmin, []string{"admin"}, []string{"admin"}, []string{"admin"}},
+		{"temporary approval role removed", true, temporaryAdmin, []string{"viewer"}, []string{"viewer"}, nil},
+	}
+	for _, tc := range cases {
+		repo := &fakeRepo{held: tc.held, approval: map[string]bool{"admin": true, "auditor": true}}
+		service := newTestService(t, repo, tc.approval)
+
+		requests, err := service.ReplaceUserRoles(actorContext("manager", ""), "grantee", tc.desired)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(repo.replaced, tc.wantApplied) {
+			t.Fatalf("%s: expected applied %v, got %v", tc.name, tc.wantApplied, repo.replaced)
+		}
+		var deferred []string
+		for _, request := range requests {
+			if request.UserID != "grantee" || request.RequestedBy != "manager" {
+				t.Fatalf("%s: unexpected request %+v", tc.name, request)
+			}
+			deferred = append(deferred, request.RoleID)
+		}
+		if !reflect.DeepEqual(deferred, tc.wantDeferred) {
+			t.Fatalf("%s: expected deferred %v, got %v", tc.name, tc.wantDeferred, deferred)
+		}
+	}
+}
+
+func TestGrantUserRoleDefersApprovalRole(t *testing.T) {
+	repo := &fakeRepo{approval: map[string]bool{"admin": true}}
+	service := newTestService(t, repo, true)
+
+	request, err := service.GrantUserRole(actorContext("manager", ""), "grantee", "admin", nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if request == nil || request.RoleID != "admin" || request.RequestedBy != "manager" {
+		t.Fatalf("expected pending request for admin, got %+v", request)
+	}
+	if len(repo.granted) != 0 {
+		t.Fatalf("expected no grant before approval, got %v", repo.granted)
+	}
+
+	request, err = service.GrantUserRole(actorContext("manager", ""), "grantee", "viewer", nil, nil)
+	if err != nil || request != nil {
+		t.Fatalf("expected viewer to be granted directly, got %+v (err %v)", request, err)
+	}
+	if !reflect.DeepEqual(repo.granted, []string{"viewer"}) {
+		t.Fatalf("expected viewer granted, got %v", repo.granted)
+	}
+}
+
+func TestRoleGrantRequestDecisions(t *testing.T) {
+	pending := userdomain.RoleGrantRequest{
+		ID:          "req-1",
+		UserID:      "grantee",
+		RoleID:      "admin",
+		RequestedBy: "requester",
+		Status:      userdomain.RoleRequestPending,
+	}
+	decided := pending
+	decided.ID = "req-2"
+	decided.Status = userdomain.RoleRequestApproved
+
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		id      string
+		wantErr error
+	}{
+		{"another user", actorContext("approver", ""), "req-1", nil},
+		{"requester", actorContext("requester", ""), "req-1", userdomain.ErrSelfApproval},
+		{"grantee", actorContext("grantee", ""), "req-1", userdomain.ErrSelfApproval},
+		{"no actor", context.Background(), "req-1", userdomain.ErrSelfApproval},
+		// API keys act as their owner, so a requester's key is still the
+		// requester, and a key without an owner cannot decide.
+		{"api key of requester", actorContext("requester", "key-1"), "req-1", userdomain.ErrSelfApproval},
+		{"api key of grantee", actorContext("grantee", "key-1"), "req-1", userdomain.ErrSelfApproval},
+		{"api key without user", actorContext("", "key-1"), "req-1", userdomain.ErrSelfApproval},
+		{"already decided", actorContext("approver", ""), "req-2", userdomain.ErrRequestNotPending},
+		{"unknown request", actorContext("approver", ""), "req-3", userdomain.ErrRequestNotFound},
+		{"blank id", actorContext("approver", ""), " ", userdomain.ErrInvalidInput},
+	}
+	for _, tc := range cases {
+		for _, approve := range []bool{true, false} {
+			repo := &fakeRepo{requests: map[string]userdomain.RoleGrantRequest{"req-1": pending, "req-2": decided}}
+			service := newTestService(t, repo, true)
+
+			decide := service.RejectRoleGrantRequest
+			if approve {
+				decide = service.ApproveRoleGrantRequest
+			}
+			result, err := decide(tc.ctx, tc.id)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("%s (approve=%v): expected %v, got %v", tc.name, approve, tc.wantErr, err)
+				}
+				if repo.decidedBy != "" {
+					t.Fatalf("%s (approve=%v): expected no decision, got one by %q", tc.name, approve, repo.decidedBy)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s (approve=%v): expected no error, got %v", tc.name, approve, err)
+			}
+			if result.DecidedBy != "approver" {
+				t.Fatalf("%s (approve=%v): expected decision by approver, got %+v", tc.name, approve, result)
+			}
+		}
+	}
+}
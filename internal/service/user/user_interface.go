@@ -2,7 +2,9 @@ package user
 
 import (
 	"context"
+	"time"
 
+	eventdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/event"
 	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 	userdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/user"
 )
@@ -11,8 +13,37 @@ type Repository interface {
 	ListUsers(ctx context.Context, filter userdomain.ListFilter) (userdomain.ListResult, error)
 	GetUser(ctx context.Context, id string) (userdomain.User, error)
 	CreateUser(ctx context.Context, email, passwordHash string, isActive bool, roleIDs []string) (userdomain.User, error)
-	UpdateUser(ctx context.Context, id, email, passwordHash string, isActive bool, bumpTokenVersion bool) (userdomain.User, error)
+	UpdateUser(ctx context.Context, id, email, passwordHash string, isActive bool, bumpTokenVersion bool, events ...eventdomain.Event) (userdomain.User, error)
 	DeleteUser(ctx context.Context, id string) error
-	ListUserRoles(ctx context.Context, userID string) ([]rbacdomain.Role, error)
+	ResetMFA(ctx context.Context, id string) error
+	ListUserRoles(ctx context.Context, userID string) ([]rbacdomain.UserRole, error)
 	ReplaceUserRoles(ctx context.Context, userID string, roleIDs []string) error
+	GrantUserRole(ctx context.Context, userID, roleID string, validFrom, validUntil *time.Time) error
+	DeleteExpiredUserRoles(ctx context.Context) ([]rbacdomain.RoleAssignment, error)
+
+	HasExclusiveRoles(ctx context.Context, userID string, roleIDs []string) (bool, error)
+	ListApprovalRoles(ctx context.Context, roleIDs []string) ([]string, error)
+	CreateRoleGrantRequest(ctx context.Context, request userdomain.RoleGrantRequest) (userdomain.RoleGrantRequest, error)
+	ListRoleGrantRequests(ctx context.Context, status string) ([]userdomain.RoleGrantRequest, error)
+	GetRoleGrantRequest(ctx context.Context, id string) (userdomain.RoleGrantRequest, error)
+	DecideRoleGrantRequest(ctx context.Context, id, deciderID string, approve bool) (userdomain.RoleGrantRequest, error)
+	ListPasswordHistory(ctx context.Context, userID string, limit int) ([]string, error)
+}
+
+// PasswordHasher hashes new passwords with the configured algorithm.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+}
+
+// PasswordPolicy checks new passwords. Check returns a
+// *authdomain.PasswordPolicyError listing the rules password fails;
+// previousHashes are the user's recent password hashes, newest first.
+type PasswordPolicy interface {
+	Check(password, email string, previousHashes []string) error
+	HistorySize() int
+}
+
+// AuditRecorder stores audit entries for completed changes.
+type AuditRecorder interface {
+	Record(ctx context.Context, action, targetType, targetID string, before, after any)
 }
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	auditusecase "github.com/mzulfanw/boilerplate-go-fiber/internal/service/audit"
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/query"
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/response"
+)
+
+type Handler struct {
+	service *auditusecase.Service
+}
+
+func NewHandler(service *auditusecase.Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListAuditLogs godoc
+// @Summary List audit log entries
+// @Tags Audit
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number"
+// @Param per_page query int false "Items per page"
+// @Param actor_id query string false "Filter by actor user id"
+// @Param action query string false "Filter by action, e.g. user.update"
+// @Param target_type query string false "Filter by target type"
+// @Param target_id query string false "Filter by target id"
+// @Param created_from query string false "Created date from (RFC3339 or YYYY-MM-DD)"
+// @Param created_to query string false "Created date to (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {object} response.Response{data=AuditLogListResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /audit-logs [get]
+func (h *Handler) ListAuditLogs(c *fiber.Ctx) error {
+	pagination, err := query.ParsePagination(c)
+	if err != nil {
+		return err
+	}
+	createdFrom, createdTo, err := query.ParseCreatedDateRange(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.service.List(c.UserContext(), auditdomain.ListFilter{
+		ActorUserID: query.ParseSearch(c, "actor_id"),
+		Action:      query.ParseSearch(c, "action"),
+		TargetType:  query.ParseSearch(c, "target_type"),
+		TargetID:    query.ParseSearch(c, "target_id"),
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		Pagination:  pagination,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: AuditLogListResponse{
+			Items: mapEntries(result.Entries),
+			Meta:  response.NewPageMeta(pagination.Page, pagination.PerPage, result.Total),
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+func mapEntries(entries []auditdomain.Entry) []AuditLogResponse {
+	result := make([]AuditLogResponse, 0, len(entries))
+	for _, entry := range entries {
+		changes := entry.Changes
+		if len(changes) == 0 {
+			changes = json.RawMessage("{}")
+		}
+		result = append(result, AuditLogResponse{
+			ID:            entry.ID,
+			ActorUserID:   entry.ActorUserID,
+			ActorAPIKeyID: entry.ActorAPIKeyID,
+			Action:        entry.Action,
+			TargetType:    entry.TargetType,
+			TargetID:      entry.TargetID,
+			Changes:       changes,
+			IP:            entry.IP,
+			UserAgent:     entry.UserAgent,
+			RequestID:     entry.RequestID,
+			CreatedAt:     entry.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return result
+}
@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"github.com/gofiber/fiber/v2"
+	httptransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http"
+)
+
+const permAuditRead = "audit.read"
+
+type Router struct {
+	handler *Handler
+	auth    *httptransport.AuthMiddleware
+}
+
+func NewRouter(handler *Handler, auth *httptransport.AuthMiddleware) *Router {
+	return &Router{handler: handler, auth: auth}
+}
+
+func (r *Router) Register(app *fiber.App) {
+	if r == nil || r.handler == nil || r.auth == nil || app == nil {
+		return
+	}
+
+	group := app.Group("/audit-logs", r.auth.RequireAuth())
+	r.auth.Handle(group, fiber.MethodGet, "/", permAuditRead, r.handler.ListAuditLogs)
+}
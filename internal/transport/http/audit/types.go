@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"encoding/json"
+
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/response"
+)
+
+type AuditLogResponse struct {
+	ID            string          `json:"id"`
+	ActorUserID   string          `json:"actor_user_id,omitempty"`
+	ActorAPIKeyID string          `json:"actor_api_key_id,omitempty"`
+	Action        string          `json:"action"`
+	TargetType    string          `json:"target_type"`
+	TargetID      string          `json:"target_id"`
+	Changes       json.RawMessage `json:"changes" swaggertype:"object"`
+	IP            string          `json:"ip,omitempty"`
+	UserAgent     string          `json:"user_agent,omitempty"`
+	RequestID     string          `json:"request_id,omitempty"`
+	CreatedAt     string          `json:"created_at"`
+}
+
+type AuditLogListResponse struct {
+	Items []AuditLogResponse `json:"items"`
+	Meta  response.PageMeta  `json:"meta"`
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -8,38 +9,46 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-
+	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 	authusecase "github.com/mzulfanw/boilerplate-go-fiber/internal/service/auth"
 	emailservice "github.com/mzulfanw/boilerplate-go-fiber/internal/service/email"
+	httptransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/response"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/validation"
+	"github.com/sirupsen/logrus"
 )
 
+const oauthStateCookie = "oauth_state"
+
 type Handler struct {
-	service  *authusecase.Service
-	email    *emailservice.Service
-	renderer *emailservice.Renderer
-	resetURL string
-	appName  string
+	service   *authusecase.Service
+	email     *emailservice.Service
+	renderer  *emailservice.Renderer
+	resetURL  string
+	verifyURL string
+	appName   string
 }
 
-func NewHandler(service *authusecase.Service, emailService *emailservice.Service, renderer *emailservice.Renderer, resetURL, appName string) *Handler {
+func NewHandler(service *authusecase.Service, emailService *emailservice.Service, renderer *emailservice.Renderer, resetURL, verifyURL, appName string) *Handler {
 	return &Handler{
-		service:  service,
-		email:    emailService,
-		renderer: renderer,
-		resetURL: strings.TrimSpace(resetURL),
-		appName:  strings.TrimSpace(appName),
+		service:   service,
+		email:     emailService,
+		renderer:  renderer,
+		resetURL:  strings.TrimSpace(resetURL),
+		verifyURL: strings.TrimSpace(verifyURL),
+		appName:   strings.TrimSpace(appName),
 	}
 }
 
 // Login godoc
 // @Summary Login
+// @Description Returns a token pair, or an MFA challenge token when two-factor authentication is enabled.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param payload body LoginRequest true "Login payload"
-// @Success 200 {object} response.Response{data=TokenResponse}
+// @Success 200 {object} response.Response{data=LoginResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
@@ -59,6 +68,131 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 		return mapAuthError(err)
 	}
 
+	return respondLogin(c, result)
+}
+
+// StartOAuth godoc
+// @Summary Start an external login
+// @Description Redirects to the OpenID Connect provider. The state is also set in an HttpOnly cookie that the callback checks.
+// @Tags Auth
+// @Param provider path string true "Provider name"
+// @Success 302
+// @Failure 404 {object} response.Response
+// @Router /auth/oauth/{provider}/start [get]
+func (h *Handler) StartOAuth(c *fiber.Ctx) error {
+	start, err := h.service.StartOAuthLogin(c.UserContext(), c.Params("provider"))
+	if err != nil {
+		return mapAuthError(err)
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthStateCookie,
+		Value:    start.State,
+		Path:     "/auth/oauth",
+		Expires:  start.ExpiresAt,
+		Secure:   c.Secure(),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.Redirect(start.URL, fiber.StatusFound)
+}
+
+// OAuthCallback godoc
+// @Summary Complete an external login
+// @Description Exchanges the authorization code and returns a token pair, or an MFA challenge token when two-factor authentication is enabled. An existing account is linked when the provider reports the same verified email and the account's email is verified too.
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param state query string true "State from the start redirect"
+// @Param code query string true "Authorization code"
+// @Success 200 {object} response.Response{data=LoginResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 423 {object} response.Response
+// @Router /auth/oauth/{provider}/callback [get]
+func (h *Handler) OAuthCallback(c *fiber.Ctx) error {
+	provider := c.Params("provider")
+	cookieState := c.Cookies(oauthStateCookie)
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthStateCookie,
+		Path:     "/auth/oauth",
+		Expires:  time.Unix(0, 0),
+		Secure:   c.Secure(),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	if c.Query("error") != "" {
+		return fiber.NewError(fiber.StatusBadRequest, "external login was cancelled or denied")
+	}
+	state := c.Query("state")
+	if cookieState == "" || cookieState != state {
+		return mapAuthError(authusecase.ErrInvalidOAuthState)
+	}
+
+	result, err := h.service.CompleteOAuthLogin(c.UserContext(), provider, state, c.Query("code"), c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		return mapAuthError(err)
+	}
+	return respondLogin(c, result)
+}
+
+// respondLogin writes a login result: the token pair, or the MFA challenge
+// when the user has two-factor authentication enabled.
+func respondLogin(c *fiber.Ctx, result authusecase.LoginResult) error {
+	if result.MFARequired {
+		resp := response.Response{
+			Code:    fiber.StatusOK,
+			Message: "mfa required",
+			Data: LoginResponse{
+				MFARequired:  true,
+				MFAToken:     result.MFAToken,
+				MFAExpiresIn: int64(time.Until(result.MFAExpiresAt).Seconds()),
+			},
+		}
+		return c.Status(resp.Code).JSON(resp)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: LoginResponse{
+			AccessToken:  result.AccessToken,
+			RefreshToken: result.RefreshToken,
+			TokenType:    result.TokenType,
+			ExpiresIn:    result.ExpiresIn,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// VerifyMFA godoc
+// @Summary Complete login with a TOTP or recovery code
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body MFAVerifyRequest true "MFA verify payload"
+// @Success 200 {object} response.Response{data=TokenResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /auth/mfa/verify [post]
+func (h *Handler) VerifyMFA(c *fiber.Ctx) error {
+	var req MFAVerifyRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	ip := c.IP()
+	ua := c.Get(fiber.HeaderUserAgent)
+	result, err := h.service.VerifyMFA(c.UserContext(), req.MFAToken, req.Code, ip, ua)
+	if err != nil {
+		return mapAuthError(err)
+	}
+
 	resp := response.Response{
 		Code:    fiber.StatusOK,
 		Message: "ok",
@@ -72,6 +206,128 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 	return c.Status(resp.Code).JSON(resp)
 }
 
+// SetupTOTP godoc
+// @Summary Start TOTP enrollment
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=MFASetupResponse}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /auth/mfa/totp/setup [post]
+func (h *Handler) SetupTOTP(c *fiber.Ctx) error {
+	authCtx, err := requireInteractiveAuth(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.service.SetupTOTP(c.UserContext(), authCtx.UserID)
+	if err != nil {
+		return mapAuthError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: MFASetupResponse{
+			Secret:          result.Secret,
+			ProvisioningURI: result.ProvisioningURI,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// ConfirmTOTP godoc
+// @Summary Confirm TOTP enrollment
+// @Description Enables two-factor authentication and returns single-use recovery codes.
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param payload body MFACodeRequest true "TOTP code payload"
+// @Success 200 {object} response.Response{data=MFAConfirmResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /auth/mfa/totp/confirm [post]
+func (h *Handler) ConfirmTOTP(c *fiber.Ctx) error {
+	authCtx, err := requireInteractiveAuth(c)
+	if err != nil {
+		return err
+	}
+
+	var req MFACodeRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	codes, err := h.service.ConfirmTOTP(c.UserContext(), authCtx.UserID, req.Code)
+	if err != nil {
+		return mapAuthError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: MFAConfirmResponse{
+			RecoveryCodes: codes,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// DisableTOTP godoc
+// @Summary Disable TOTP
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param payload body MFACodeRequest true "TOTP or recovery code payload"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /auth/mfa/totp/disable [post]
+func (h *Handler) DisableTOTP(c *fiber.Ctx) error {
+	authCtx, err := requireInteractiveAuth(c)
+	if err != nil {
+		return err
+	}
+
+	var req MFACodeRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.service.DisableTOTP(c.UserContext(), authCtx.UserID, req.Code); err != nil {
+		return mapAuthError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// JWKS godoc
+// @Summary JSON Web Key Set
+// @Description Public keys for verifying access tokens. Returned as a plain JWKS document.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} JWKSResponse
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) JWKS(c *fiber.Ctx) error {
+	keys := h.service.PublicKeys()
+	if keys == nil {
+		keys = []authusecase.JSONWebKey{}
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(JWKSResponse{Keys: keys})
+}
+
 // Refresh godoc
 // @Summary Refresh access token
 // @Tags Auth
@@ -161,40 +417,17 @@ func (h *Handler) ForgotPassword(c *fiber.Ctx) error {
 		if h.email == nil {
 			return fiber.NewError(fiber.StatusInternalServerError, "email service is not configured")
 		}
-		resetLink := buildResetLink(h.resetURL, result.Token)
-		subject := "Reset your password"
+		resetLink := buildTokenLink(h.resetURL, result.Token)
 		body := fmt.Sprintf("We received a request to reset your password.\n\nReset link: %s\n\nThis link expires at %s.\nIf you did not request a reset, you can ignore this email.",
 			resetLink,
 			result.ExpiresAt.Format(time.RFC1123),
 		)
-		contentType := "text/plain; charset=utf-8"
-
-		if h.renderer != nil {
-			rendered, err := h.renderer.Render("reset_password", emailservice.TemplateData{
-				AppName:        h.appName,
-				RecipientEmail: result.Email,
-				ActionURL:      resetLink,
-				ActionLabel:    "Reset Password",
-				ExpiresAt:      result.ExpiresAt.Format(time.RFC1123),
-			})
-			if err != nil {
-				return err
-			}
-			if strings.TrimSpace(rendered.Subject) != "" {
-				subject = rendered.Subject
-			}
-			if strings.TrimSpace(rendered.HTML) != "" {
-				body = rendered.HTML
-				contentType = "text/html; charset=utf-8"
-			} else if strings.TrimSpace(rendered.Text) != "" {
-				body = rendered.Text
-			}
-		}
-		if err := h.email.Enqueue(c.UserContext(), emailservice.Message{
-			To:          []string{result.Email},
-			Subject:     subject,
-			Body:        body,
-			ContentType: contentType,
+		if err := h.sendEmail(c.UserContext(), "reset_password", "Reset your password", body, emailservice.TemplateData{
+			AppName:        h.appName,
+			RecipientEmail: result.Email,
+			ActionURL:      resetLink,
+			ActionLabel:    "Reset Password",
+			ExpiresAt:      result.ExpiresAt.Format(time.RFC1123),
 		}); err != nil {
 			return err
 		}
@@ -234,7 +467,614 @@ func (h *Handler) ResetPassword(c *fiber.Ctx) error {
 	return c.Status(resp.Code).JSON(resp)
 }
 
+// ChangePassword godoc
+// @Summary Change password
+// @Description Requires the current password. Signs out every other session and emails a notification. The caller's access token stops working; refresh with the current refresh token to continue.
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param payload body ChangePasswordRequest true "Change password payload"
+// @Success 200 {object} response.Response{data=ChangePasswordResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 423 {object} response.Response
+// @Router /auth/change-password [post]
+func (h *Handler) ChangePassword(c *fiber.Ctx) error {
+	authCtx, err := requireInteractiveAuth(c)
+	if err != nil {
+		return err
+	}
+
+	var req ChangePasswordRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	result, err := h.service.ChangePassword(c.UserContext(), authCtx.UserID, authCtx.SessionID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		var policyErr *authdomain.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return validation.PasswordPolicyError("new_password", policyErr)
+		}
+		return mapAuthError(err)
+	}
+
+	// The password is already changed, so a failed notification is logged
+	// rather than failing the request.
+	if h.email != nil {
+		body := "Your password was just changed and your other sessions were signed out.\n\nIf you did not make this change, reset your password immediately."
+		if err := h.sendEmail(c.UserContext(), "password_changed", "Your password was changed", body, emailservice.TemplateData{
+			AppName:        h.appName,
+			RecipientEmail: result.Email,
+		}); err != nil {
+			logrus.WithError(err).WithField("user_id", authCtx.UserID).Warn("password change notification failed")
+		}
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: ChangePasswordResponse{
+			RevokedSessions: result.RevokedSessions,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// Register godoc
+// @Summary Register account
+// @Description Always answers 202 so the response does not reveal whether the email has an account. An existing owner is emailed instead of a verification link.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body RegisterRequest true "Register payload"
+// @Success 202 {object} response.Response{data=RegisterResponse}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /auth/register [post]
+func (h *Handler) Register(c *fiber.Ctx) error {
+	var req RegisterRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+	req.Email = strings.TrimSpace(req.Email)
+
+	if h.email == nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "email service is not configured")
+	}
+
+	email := strings.ToLower(req.Email)
+	result, err := h.service.Register(c.UserContext(), email, req.Password)
+	switch {
+	case errors.Is(err, authusecase.ErrEmailAlreadyRegistered):
+		// Answer as for a new account so registration cannot be used to
+		// find out which emails have one; tell the owner instead.
+		if err := h.sendAccountExistsEmail(c.UserContext(), email); err != nil {
+			return err
+		}
+	case err != nil:
+		return mapAuthError(err)
+	default:
+		if err := h.sendVerificationEmail(c.UserContext(), result); err != nil {
+			return err
+		}
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusAccepted,
+		Message: "accepted",
+		Data: RegisterResponse{
+			Email: email,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body VerifyEmailRequest true "Verify email payload"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /auth/verify-email [post]
+func (h *Handler) VerifyEmail(c *fiber.Ctx) error {
+	var req VerifyEmailRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.service.VerifyEmail(c.UserContext(), req.Token); err != nil {
+		return mapAuthError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// ResendVerification godoc
+// @Summary Resend email verification
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param payload body ResendVerificationRequest true "Resend verification payload"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /auth/resend-verification [post]
+func (h *Handler) ResendVerification(c *fiber.Ctx) error {
+	var req ResendVerificationRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	result, err := h.service.ResendEmailVerification(c.UserContext(), req.Email)
+	if err != nil {
+		return mapAuthError(err)
+	}
+
+	if result.ShouldSend {
+		if h.email == nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "email service is not configured")
+		}
+		if err := h.sendVerificationEmail(c.UserContext(), result); err != nil {
+			return err
+		}
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// ListSessions godoc
+// @Summary List active sessions
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]SessionResponse}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /auth/sessions [get]
+func (h *Handler) ListSessions(c *fiber.Ctx) error {
+	authCtx, err := requireInteractiveAuth(c)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := h.service.ListSessions(c.UserContext(), authCtx.UserID, authCtx.SessionID)
+	if err != nil {
+		return mapAuthError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data:    mapSessions(sessions),
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /auth/sessions/{id} [delete]
+func (h *Handler) RevokeSession(c *fiber.Ctx) error {
+	authCtx, err := requireInteractiveAuth(c)
+	if err != nil {
+		return err
+	}
+
+	sessionID, err := validation.RequireParam(c.Params("id"), "session id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.RevokeSession(c.UserContext(), authCtx.UserID, sessionID); err != nil {
+		return mapAuthError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// RevokeOtherSessions godoc
+// @Summary Revoke all other sessions
+// @Description Signs out every device except the one making the request.
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=RevokeSessionsResponse}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /auth/sessions [delete]
+func (h *Handler) RevokeOtherSessions(c *fiber.Ctx) error {
+	authCtx, err := requireInteractiveAuth(c)
+	if err != nil {
+		return err
+	}
+	if authCtx.SessionID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "access token has no session")
+	}
+
+	revoked, err := h.service.RevokeOtherSessions(c.UserContext(), authCtx.UserID, authCtx.SessionID)
+	if err != nil {
+		return mapAuthError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: RevokeSessionsResponse{
+			Revoked: revoked,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+func mapSessions(sessions []authusecase.Session) []SessionResponse {
+	result := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, SessionResponse{
+			ID:         session.ID,
+			StartedAt:  session.StartedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+			Current:    session.Current,
+		})
+	}
+	return result
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description The key is only returned once. Send it as `Authorization: ApiKey <key>`.
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param payload body CreateAPIKeyRequest true "API key payload"
+// @Success 201 {object} response.Response{data=CreateAPIKeyResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /auth/api-keys [post]
+func (h *Handler) CreateAPIKey(c *fiber.Ctx) error {
+	authCtx, err := requireInteractiveAuth(c)
+	if err != nil {
+		return err
+	}
+
+	var req CreateAPIKeyRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	created, err := h.service.CreateAPIKey(c.UserContext(), authCtx.UserID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		return mapAuthError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusCreated,
+		Message: "created",
+		Data: CreateAPIKeyResponse{
+			APIKeyResponse: mapAPIKey(created.APIKey),
+			Key:            created.Key,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]APIKeyResponse}
+// @Failure 401 {object} response.Response
+// @Router /auth/api-keys [get]
+func (h *Handler) ListAPIKeys(c *fiber.Ctx) error {
+	authCtx, ok := httptransport.GetAuthContext(c)
+	if !ok {
+		return fiber.NewError(fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	keys, err := h.service.ListAPIKeys(c.UserContext(), authCtx.UserID)
+	if err != nil {
+		return mapAuthError(err)
+	}
+
+	data := make([]APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		data = append(data, mapAPIKey(key))
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data:    data,
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /auth/api-keys/{id} [delete]
+func (h *Handler) RevokeAPIKey(c *fiber.Ctx) error {
+	authCtx, err := requireInteractiveAuth(c)
+	if err != nil {
+		return err
+	}
+
+	keyID, err := validation.RequireParam(c.Params("id"), "api key id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.RevokeAPIKey(c.UserContext(), authCtx.UserID, keyID); err != nil {
+		return mapAuthError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// Me godoc
+// @Summary Current principal
+// @Description Profile, roles and effective permissions read from the database, so changes show up before the access token is refreshed. API keys report their own scoped permissions and no roles.
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=MeResponse}
+// @Failure 401 {object} response.Response
+// @Router /auth/me [get]
+func (h *Handler) Me(c *fiber.Ctx) error {
+	authCtx, access, err := h.callerAccess(c)
+	if err != nil {
+		return err
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: MeResponse{
+			ID:            access.User.ID,
+			Email:         access.User.Email,
+			EmailVerified: access.User.EmailVerifiedAt != nil,
+			MFAEnabled:    access.User.MFAEnabledAt != nil,
+			APIKeyID:      authCtx.APIKeyID,
+			Roles:         nonNilStrings(access.Roles),
+			Permissions:   nonNilStrings(access.Permissions),
+			Grants:        mapResourceGrants(access.Grants),
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// Authorize godoc
+// @Summary Check permissions
+// @Description Evaluates each check against the caller's current permissions in the database. A check with resource_type and resource_id also passes through a role granted on that resource. allowed is true only if every check passes.
+// @Tags Auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param payload body AuthorizeRequest true "Permission checks"
+// @Success 200 {object} response.Response{data=AuthorizeResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/authorize [post]
+func (h *Handler) Authorize(c *fiber.Ctx) error {
+	var req AuthorizeRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	_, access, err := h.callerAccess(c)
+	if err != nil {
+		return err
+	}
+
+	checks := make([]authusecase.PermissionCheck, 0, len(req.Checks))
+	for _, check := range req.Checks {
+		checks = append(checks, authusecase.PermissionCheck{
+			Permission:   check.Permission,
+			ResourceType: check.ResourceType,
+			ResourceID:   check.ResourceID,
+		})
+	}
+
+	allowed := true
+	results := make([]PermissionDecisionResponse, 0, len(checks))
+	for _, decision := range access.Check(checks) {
+		allowed = allowed && decision.Allowed
+		results = append(results, PermissionDecisionResponse{
+			Permission:   decision.Permission,
+			ResourceType: decision.ResourceType,
+			ResourceID:   decision.ResourceID,
+			Allowed:      decision.Allowed,
+		})
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: AuthorizeResponse{
+			Allowed: allowed,
+			Results: results,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// callerAccess loads the caller's live access. API keys keep the
+// permissions resolved when the key authenticated on this request, which are
+// already read from the database and narrowed to the key's scopes.
+func (h *Handler) callerAccess(c *fiber.Ctx) (httptransport.AuthContext, authusecase.EffectiveAccess, error) {
+	authCtx, ok := httptransport.GetAuthContext(c)
+	if !ok {
+		return httptransport.AuthContext{}, authusecase.EffectiveAccess{}, fiber.NewError(fiber.StatusUnauthorized, "unauthorized")
+	}
+
+	access, err := h.service.EffectiveAccess(c.UserContext(), authCtx.UserID)
+	if err != nil {
+		return httptransport.AuthContext{}, authusecase.EffectiveAccess{}, mapAuthError(err)
+	}
+	if authCtx.APIKeyID != "" {
+		access.Roles = nil
+		access.Permissions = authCtx.Permissions
+		access.Grants = authCtx.Grants
+	}
+	return authCtx, access, nil
+}
+
+func mapResourceGrants(grants []rbacdomain.ResourceGrant) []ResourceGrantResponse {
+	result := make([]ResourceGrantResponse, 0, len(grants))
+	for _, grant := range grants {
+		result = append(result, ResourceGrantResponse{
+			ResourceType: grant.ResourceType,
+			ResourceID:   grant.ResourceID,
+			Permissions:  grant.Permissions,
+		})
+	}
+	return result
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// requireInteractiveAuth rejects callers authenticated with an API key, so a
+// leaked key cannot be used to mint or revoke other keys, change the
+// password, enroll or remove MFA, or see and revoke the owner's sessions.
+func requireInteractiveAuth(c *fiber.Ctx) (httptransport.AuthContext, error) {
+	authCtx, ok := httptransport.GetAuthContext(c)
+	if !ok {
+		return httptransport.AuthContext{}, fiber.NewError(fiber.StatusUnauthorized, "unauthorized")
+	}
+	if authCtx.APIKeyID != "" {
+		return httptransport.AuthContext{}, fiber.NewError(fiber.StatusForbidden, "api keys cannot be used for this action")
+	}
+	return authCtx, nil
+}
+
+func mapAPIKey(key authdomain.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// SendEmailVerification sends a new verification link after a user's email
+// changed. The previous link stops working even when email is disabled.
+func (h *Handler) SendEmailVerification(ctx context.Context, userID string) error {
+	result, err := h.service.RestartEmailVerification(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !result.ShouldSend || h.email == nil {
+		return nil
+	}
+	return h.sendVerificationEmail(ctx, result)
+}
+
+func (h *Handler) sendVerificationEmail(ctx context.Context, result authusecase.EmailVerificationRequest) error {
+	verifyLink := buildTokenLink(h.verifyURL, result.Token)
+	body := fmt.Sprintf("Welcome! Please verify your email to complete registration.\n\nVerification link: %s\n\nThis link expires at %s.\nIf you did not create this account, you can ignore this email.",
+		verifyLink,
+		result.ExpiresAt.Format(time.RFC1123),
+	)
+	return h.sendEmail(ctx, "registration", "Verify your email", body, emailservice.TemplateData{
+		AppName:        h.appName,
+		RecipientEmail: result.Email,
+		ActionURL:      verifyLink,
+		ActionLabel:    "Verify Email",
+		ExpiresAt:      result.ExpiresAt.Format(time.RFC1123),
+	})
+}
+
+func (h *Handler) sendAccountExistsEmail(ctx context.Context, email string) error {
+	body := "Someone tried to create an account with this email, but you already have one. Sign in instead, or reset your password if you have forgotten it.\n\nIf this was not you, you can ignore this email. Your account has not been changed."
+	return h.sendEmail(ctx, "account_exists", "Sign-up attempt for your account", body, emailservice.TemplateData{
+		AppName:        h.appName,
+		RecipientEmail: email,
+	})
+}
+
+func (h *Handler) sendEmail(ctx context.Context, templateName, subject, body string, data emailservice.TemplateData) error {
+	if h.email == nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "email service is not configured")
+	}
+	contentType := "text/plain; charset=utf-8"
+
+	if h.renderer != nil {
+		rendered, err := h.renderer.Render(templateName, data)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(rendered.Subject) != "" {
+			subject = rendered.Subject
+		}
+		if strings.TrimSpace(rendered.HTML) != "" {
+			body = rendered.HTML
+			contentType = "text/html; charset=utf-8"
+		} else if strings.TrimSpace(rendered.Text) != "" {
+			body = rendered.Text
+		}
+	}
+
+	return h.email.Enqueue(ctx, emailservice.Message{
+		To:          []string{data.RecipientEmail},
+		Subject:     subject,
+		Body:        body,
+		ContentType: contentType,
+	})
+}
+
 func mapAuthError(err error) error {
+	var policyErr *authdomain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return validation.PasswordPolicyError("password", policyErr)
+	}
 	switch {
 	case errors.Is(err, authusecase.ErrInvalidCredentials):
 		return fiber.NewError(fiber.StatusUnauthorized, "invalid credentials")
@@ -248,12 +1088,58 @@ func mapAuthError(err error) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid reset token")
 	case errors.Is(err, authusecase.ErrInvalidPassword):
 		return fiber.NewError(fiber.StatusBadRequest, "invalid password")
+	case errors.Is(err, authusecase.ErrInvalidCurrentPassword):
+		return fiber.NewError(fiber.StatusBadRequest, "current password is incorrect")
+	case errors.Is(err, authusecase.ErrEmailAlreadyRegistered):
+		return fiber.NewError(fiber.StatusConflict, "email already registered")
+	case errors.Is(err, authusecase.ErrEmailNotVerified):
+		return fiber.NewError(fiber.StatusForbidden, "email is not verified")
+	case errors.Is(err, authusecase.ErrInvalidVerificationToken):
+		return fiber.NewError(fiber.StatusBadRequest, "invalid verification token")
+	case errors.Is(err, authusecase.ErrInvalidAccessToken):
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid access token")
+	case errors.Is(err, authusecase.ErrMFAAlreadyEnabled):
+		return fiber.NewError(fiber.StatusConflict, "mfa is already enabled")
+	case errors.Is(err, authusecase.ErrMFANotEnabled):
+		return fiber.NewError(fiber.StatusBadRequest, "mfa is not enabled")
+	case errors.Is(err, authusecase.ErrMFASetupRequired):
+		return fiber.NewError(fiber.StatusBadRequest, "mfa setup has not been started")
+	case errors.Is(err, authusecase.ErrInvalidMFACode):
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid mfa code")
+	case errors.Is(err, authusecase.ErrInvalidMFAChallenge):
+		return fiber.NewError(fiber.StatusUnauthorized, "invalid or expired mfa token")
+	case errors.Is(err, authusecase.ErrUserNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "user not found")
+	case errors.Is(err, authusecase.ErrSessionNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "session not found")
+	case errors.Is(err, authusecase.ErrAPIKeyNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "api key not found")
+	case errors.Is(err, authusecase.ErrInvalidAPIKeyName):
+		return fiber.NewError(fiber.StatusBadRequest, "invalid api key name")
+	case errors.Is(err, authusecase.ErrInvalidAPIKeyExpiry):
+		return fiber.NewError(fiber.StatusBadRequest, "expires_at must be in the future")
+	case errors.Is(err, authusecase.ErrAPIKeyScopeDenied):
+		return fiber.NewError(fiber.StatusForbidden, "api key scopes exceed your permissions")
+	case errors.Is(err, authusecase.ErrUnknownIdentityProvider):
+		return fiber.NewError(fiber.StatusNotFound, "identity provider not found")
+	case errors.Is(err, authusecase.ErrInvalidOAuthState):
+		return fiber.NewError(fiber.StatusBadRequest, "invalid or expired oauth state")
+	case errors.Is(err, authusecase.ErrExternalLoginFailed):
+		return fiber.NewError(fiber.StatusUnauthorized, "external login failed")
+	case errors.Is(err, authusecase.ErrExternalEmailUnverified):
+		return fiber.NewError(fiber.StatusForbidden, "external email is not verified")
+	case errors.Is(err, authusecase.ErrIdentityNotLinked):
+		return fiber.NewError(fiber.StatusForbidden, "no account is linked to this external identity")
+	case errors.Is(err, authusecase.ErrIdentityConflict):
+		return fiber.NewError(fiber.StatusConflict, "account is linked to another external identity")
+	case errors.Is(err, authusecase.ErrIdentityLinkUnverified):
+		return fiber.NewError(fiber.StatusConflict, "an account with this email exists; verify its email before signing in with this provider")
 	default:
 		return err
 	}
 }
 
-func buildResetLink(baseURL, token string) string {
+func buildTokenLink(baseURL, token string) string {
 	escapedToken := url.QueryEscape(token)
 	trimmed := strings.TrimSpace(baseURL)
 	if trimmed == "" {
@@ -2,33 +2,35 @@ package auth
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/limiter"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
+	httptransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http"
 )
 
 type Router struct {
 	handler      *Handler
+	auth         *httptransport.AuthMiddleware
 	loginLimiter fiber.Handler
+	emailLimiter fiber.Handler
 }
 
-func NewRouter(handler *Handler, cfg config.Config) *Router {
-	var loginLimiter fiber.Handler
-	if cfg.AuthLoginRateLimit > 0 && cfg.AuthLoginRateWindow > 0 {
-		loginLimiter = limiter.New(limiter.Config{
-			Max:        cfg.AuthLoginRateLimit,
-			Expiration: cfg.AuthLoginRateWindow,
-			KeyGenerator: func(c *fiber.Ctx) string {
-				return c.IP()
-			},
-			LimitReached: func(c *fiber.Ctx) error {
-				return fiber.NewError(fiber.StatusTooManyRequests, "too many login attempts")
-			},
-		})
-	}
-
+func NewRouter(handler *Handler, auth *httptransport.AuthMiddleware, limiter *httptransport.RateLimiter, cfg config.Config) *Router {
 	return &Router{
-		handler:      handler,
-		loginLimiter: loginLimiter,
+		handler: handler,
+		auth:    auth,
+		loginLimiter: limiter.Middleware(httptransport.RateLimitPolicy{
+			Name:    "auth_login_ip",
+			Limit:   cfg.AuthLoginRateLimit,
+			Window:  cfg.AuthLoginRateWindow,
+			Key:     httptransport.RateLimitByIP,
+			Message: "too many login attempts",
+		}),
+		emailLimiter: limiter.Middleware(httptransport.RateLimitPolicy{
+			Name:    "auth_login_email",
+			Limit:   cfg.AuthLoginEmailRateLimit,
+			Window:  cfg.AuthLoginRateWindow,
+			Key:     httptransport.RateLimitByEmail,
+			Message: "too many login attempts",
+		}),
 	}
 }
 
@@ -37,14 +39,33 @@ func (r *Router) Register(app *fiber.App) {
 		return
 	}
 
+	app.Get("/.well-known/jwks.json", r.handler.JWKS)
+
 	group := app.Group("/auth")
-	if r.loginLimiter != nil {
-		group.Post("/login", r.loginLimiter, r.handler.Login)
-	} else {
-		group.Post("/login", r.handler.Login)
-	}
-	group.Post("/refresh", r.handler.Refresh)
+	group.Post("/login", r.loginLimiter, r.emailLimiter, r.handler.Login)
+	group.Post("/mfa/verify", r.loginLimiter, r.handler.VerifyMFA)
+	group.Post("/refresh", r.loginLimiter, r.handler.Refresh)
 	group.Post("/logout", r.handler.Logout)
-	group.Post("/forgot-password", r.handler.ForgotPassword)
-	group.Post("/reset-password", r.handler.ResetPassword)
+	group.Post("/forgot-password", r.loginLimiter, r.emailLimiter, r.handler.ForgotPassword)
+	group.Post("/reset-password", r.loginLimiter, r.handler.ResetPassword)
+	group.Post("/register", r.loginLimiter, r.emailLimiter, r.handler.Register)
+	group.Post("/verify-email", r.loginLimiter, r.handler.VerifyEmail)
+	group.Post("/resend-verification", r.loginLimiter, r.emailLimiter, r.handler.ResendVerification)
+	group.Get("/oauth/:provider/start", r.loginLimiter, r.handler.StartOAuth)
+	group.Get("/oauth/:provider/callback", r.loginLimiter, r.handler.OAuthCallback)
+
+	if r.auth != nil {
+		group.Get("/me", r.auth.RequireAuth(), r.handler.Me)
+		group.Post("/authorize", r.auth.RequireAuth(), r.handler.Authorize)
+		group.Post("/change-password", r.auth.RequireAuth(), r.loginLimiter, r.handler.ChangePassword)
+		group.Post("/mfa/totp/setup", r.auth.RequireAuth(), r.handler.SetupTOTP)
+		group.Post("/mfa/totp/confirm", r.auth.RequireAuth(), r.handler.ConfirmTOTP)
+		group.Post("/mfa/totp/disable", r.auth.RequireAuth(), r.handler.DisableTOTP)
+		group.Get("/sessions", r.auth.RequireAuth(), r.handler.ListSessions)
+		group.Delete("/sessions", r.auth.RequireAuth(), r.handler.RevokeOtherSessions)
+		group.Delete("/sessions/:id", r.auth.RequireAuth(), r.handler.RevokeSession)
+		group.Post("/api-keys", r.auth.RequireAuth(), r.handler.CreateAPIKey)
+		group.Get("/api-keys", r.auth.RequireAuth(), r.handler.ListAPIKeys)
+		group.Delete("/api-keys/:id", r.auth.RequireAuth(), r.handler.RevokeAPIKey)
+	}
 }
@@ -1,5 +1,11 @@
 package auth
 
+import (
+	"time"
+
+	authusecase "github.com/mzulfanw/boilerplate-go-fiber/internal/service/auth"
+)
+
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,notblank"`
 	Password string `json:"password" validate:"required,notblank"`
@@ -19,7 +25,61 @@ type ForgotPasswordRequest struct {
 
 type ResetPasswordRequest struct {
 	Token    string `json:"token" validate:"required,notblank"`
-	Password string `json:"password" validate:"required,notblank,min=8"`
+	Password string `json:"password" validate:"required,notblank"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required,notblank"`
+	NewPassword     string `json:"new_password" validate:"required,notblank"`
+}
+
+type ChangePasswordResponse struct {
+	RevokedSessions int `json:"revoked_sessions"`
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,notblank"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required,notblank"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type RegisterResponse struct {
+	Email string `json:"email"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required,notblank"`
+	Code     string `json:"code" validate:"required,notblank"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,notblank"`
+}
+
+type MFASetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type MFAConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type LoginResponse struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	MFARequired  bool   `json:"mfa_required"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+	MFAExpiresIn int64  `json:"mfa_expires_in,omitempty"`
 }
 
 type TokenResponse struct {
@@ -28,3 +88,81 @@ type TokenResponse struct {
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int64  `json:"expires_in"`
 }
+
+type JWKSResponse struct {
+	Keys []authusecase.JSONWebKey `json:"keys"`
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	StartedAt  time.Time `json:"started_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	Current    bool      `json:"current"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,notblank,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,required,notblank"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type ResourceGrantResponse struct {
+	ResourceType string   `json:"resource_type"`
+	ResourceID   string   `json:"resource_id"`
+	Permissions  []string `json:"permissions"`
+}
+
+type MeResponse struct {
+	ID            string                  `json:"id"`
+	Email         string                  `json:"email"`
+	EmailVerified bool                    `json:"email_verified"`
+	MFAEnabled    bool                    `json:"mfa_enabled"`
+	APIKeyID      string                  `json:"api_key_id,omitempty"`
+	Roles         []string                `json:"roles"`
+	Permissions   []string                `json:"permissions"`
+	Grants        []ResourceGrantResponse `json:"grants"`
+}
+
+type PermissionCheckRequest struct {
+	Permission   string `json:"permission" validate:"required,notblank"`
+	ResourceType string `json:"resource_type,omitempty" validate:"required_with=ResourceID"`
+	ResourceID   string `json:"resource_id,omitempty" validate:"required_with=ResourceType"`
+}
+
+type AuthorizeRequest struct {
+	Checks []PermissionCheckRequest `json:"checks" validate:"required,min=1,max=100,dive"`
+}
+
+type PermissionDecisionResponse struct {
+	Permission   string `json:"permission"`
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	Allowed      bool   `json:"allowed"`
+}
+
+type AuthorizeResponse struct {
+	Allowed bool                         `json:"allowed"`
+	Results []PermissionDecisionResponse `json:"results"`
+}
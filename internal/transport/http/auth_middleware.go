@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 	authusecase "github.com/mzulfanw/boilerplate-go-fiber/internal/service/auth"
 )
 
@@ -12,18 +14,37 @@ const authContextKey = "auth_context"
 
 type AuthContext struct {
 	UserID      string
+	SessionID   string
+	APIKeyID    string
 	Roles       []string
 	Permissions []string
+	Grants      []rbacdomain.ResourceGrant
 }
 
 type AuthMiddleware struct {
-	service *authusecase.Service
+	service     *authusecase.Service
+	limiter     *RateLimiter
+	limitPolicy RateLimitPolicy
+	catalog     *PermissionCatalog
 }
 
 func NewAuthMiddleware(service *authusecase.Service) *AuthMiddleware {
 	return &AuthMiddleware{service: service}
 }
 
+// SetRateLimit applies policy once per request right after authentication,
+// so every protected route shares a budget per user or API key.
+func (m *AuthMiddleware) SetRateLimit(limiter *RateLimiter, policy RateLimitPolicy) {
+	m.limiter = limiter
+	m.limitPolicy = policy
+}
+
+// SetPermissionCatalog records the permissions of routes registered through
+// Handle and HandlePolicy.
+func (m *AuthMiddleware) SetPermissionCatalog(catalog *PermissionCatalog) {
+	m.catalog = catalog
+}
+
 func (m *AuthMiddleware) RequireAuth() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if m == nil || m.service == nil {
@@ -36,8 +57,24 @@ func (m *AuthMiddleware) RequireAuth() fiber.Handler {
 	}
 }
 
+// RequirePermissions allows principals holding any of permissions.
 func (m *AuthMiddleware) RequirePermissions(permissions ...string) fiber.Handler {
-	required := normalizePermissions(permissions)
+	return m.Require(AnyPermission(permissions...))
+}
+
+// RequireAllPermissions allows principals holding every one of permissions.
+func (m *AuthMiddleware) RequireAllPermissions(permissions ...string) fiber.Handler {
+	return m.Require(AllPermissions(permissions...))
+}
+
+// RequireRoles allows users holding any of roles.
+func (m *AuthMiddleware) RequireRoles(roles ...string) fiber.Handler {
+	return m.Require(AnyRole(roles...))
+}
+
+// Require authenticates the request and rejects it with 403 unless policy
+// allows it.
+func (m *AuthMiddleware) Require(policy Policy) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		if m == nil || m.service == nil {
 			return fiber.NewError(fiber.StatusInternalServerError, "auth middleware not configured")
@@ -46,16 +83,40 @@ func (m *AuthMiddleware) RequirePermissions(permissions ...string) fiber.Handler
 		if err != nil {
 			return err
 		}
-		if len(required) == 0 {
-			return c.Next()
-		}
-		if !hasAnyPermission(ctx.Permissions, required) {
+		if policy != nil && !policy(c, ctx) {
 			return fiber.NewError(fiber.StatusForbidden, "insufficient permissions")
 		}
 		return c.Next()
 	}
 }
 
+// Handle registers handlers for method and path on router behind
+// RequirePermissions(permission) and declares the route in the permission
+// catalog.
+func (m *AuthMiddleware) Handle(router fiber.Router, method, path, permission string, handlers ...fiber.Handler) {
+	m.HandlePolicy(router, method, path, permission, AnyPermission(permission), handlers...)
+}
+
+// HandlePolicy is Handle for routes guarded by a custom policy. permission is
+// declared in the catalog; it should be the one that lets any caller through
+// policy, e.g. user.read for Or(AnyPermission("user.read"), Self("id")).
+func (m *AuthMiddleware) HandlePolicy(router fiber.Router, method, path, permission string, policy Policy, handlers ...fiber.Handler) {
+	m.catalog.Declare(method, routePath(router, path), permission)
+	router.Add(method, path, append([]fiber.Handler{m.Require(policy)}, handlers...)...)
+}
+
+// Can reports whether the principal holds permission, directly or through a
+// wildcard grant such as "user.*".
+func (a AuthContext) Can(permission string) bool {
+	return rbacdomain.Can(a.Permissions, permission)
+}
+
+// CanOn reports whether the principal holds permission on one resource,
+// either globally or through a role granted for that resource only.
+func (a AuthContext) CanOn(permission, resourceType, resourceID string) bool {
+	return rbacdomain.CanOn(a.Permissions, a.Grants, permission, resourceType, resourceID)
+}
+
 func GetAuthContext(c *fiber.Ctx) (AuthContext, bool) {
 	if c == nil {
 		return AuthContext{}, false
@@ -72,7 +133,14 @@ func (m *AuthMiddleware) ensureAuthContext(c *fiber.Ctx) (AuthContext, error) {
 	if ctx, ok := GetAuthContext(c); ok {
 		return ctx, nil
 	}
-	return m.authenticate(c)
+	ctx, err := m.authenticate(c)
+	if err != nil {
+		return AuthContext{}, err
+	}
+	if err := m.limiter.check(c, m.limitPolicy); err != nil {
+		return AuthContext{}, err
+	}
+	return ctx, nil
 }
 
 func (m *AuthMiddleware) authenticate(c *fiber.Ctx) (AuthContext, error) {
@@ -84,6 +152,9 @@ func (m *AuthMiddleware) authenticate(c *fiber.Ctx) (AuthContext, error) {
 		return AuthContext{}, fiber.NewError(fiber.StatusUnauthorized, "missing authorization header")
 	}
 	parts := strings.Fields(authHeader)
+	if len(parts) == 2 && strings.EqualFold(parts[0], "ApiKey") {
+		return m.authenticateAPIKey(c, parts[1])
+	}
 	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
 		return AuthContext{}, fiber.NewError(fiber.StatusUnauthorized, "invalid authorization header")
 	}
@@ -101,20 +172,55 @@ func (m *AuthMiddleware) authenticate(c *fiber.Ctx) (AuthContext, error) {
 
 	ctx := AuthContext{
 		UserID:      claims.Subject,
+		SessionID:   claims.SessionID,
 		Roles:       claims.Roles,
 		Permissions: claims.Permissions,
+		Grants:      claims.Grants,
 	}
 	c.Locals(authContextKey, ctx)
+	setAuditActor(c, ctx)
 	return ctx, nil
 }
 
+func (m *AuthMiddleware) authenticateAPIKey(c *fiber.Ctx, rawKey string) (AuthContext, error) {
+	principal, err := m.service.AuthenticateAPIKey(c.UserContext(), rawKey)
+	if err != nil {
+		if errors.Is(err, authusecase.ErrUserDisabled) {
+			return AuthContext{}, fiber.NewError(fiber.StatusForbidden, "user is disabled")
+		}
+		if errors.Is(err, authusecase.ErrInvalidAPIKey) {
+			return AuthContext{}, fiber.NewError(fiber.StatusUnauthorized, "invalid or expired api key")
+		}
+		return AuthContext{}, err
+	}
+
+	ctx := AuthContext{
+		UserID:      principal.UserID,
+		APIKeyID:    principal.KeyID,
+		Permissions: principal.Permissions,
+		Grants:      principal.Grants,
+	}
+	c.Locals(authContextKey, ctx)
+	setAuditActor(c, ctx)
+	return ctx, nil
+}
+
+// setAuditActor adds the authenticated principal to the actor stored by
+// auditContext so services record who made a change.
+func setAuditActor(c *fiber.Ctx, ctx AuthContext) {
+	actor := auditdomain.ActorFromContext(c.UserContext())
+	actor.UserID = ctx.UserID
+	actor.APIKeyID = ctx.APIKeyID
+	c.SetUserContext(auditdomain.WithActor(c.UserContext(), actor))
+}
+
 func normalizePermissions(permissions []string) []string {
 	if len(permissions) == 0 {
 		return nil
 	}
 	seen := make(map[string]struct{}, len(permissions))
 	for _, perm := range permissions {
-		normalized := strings.ToLower(strings.TrimSpace(perm))
+		normalized := rbacdomain.NormalizePermission(perm)
 		if normalized == "" {
 			continue
 		}
@@ -129,26 +235,3 @@ func normalizePermissions(permissions []string) []string {
 	}
 	return result
 }
-
-func hasAnyPermission(granted, required []string) bool {
-	if len(required) == 0 {
-		return true
-	}
-	if len(granted) == 0 {
-		return false
-	}
-	allowed := make(map[string]struct{}, len(granted))
-	for _, perm := range granted {
-		normalized := strings.ToLower(strings.TrimSpace(perm))
-		if normalized == "" {
-			continue
-		}
-		allowed[normalized] = struct{}{}
-	}
-	for _, perm := range required {
-		if _, ok := allowed[strings.ToLower(strings.TrimSpace(perm))]; ok {
-			return true
-		}
-	}
-	return false
-}
@@ -1,9 +1,12 @@
 package http
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/utils"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/response"
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/validation"
 	"github.com/sirupsen/logrus"
 )
 
@@ -12,6 +15,16 @@ func errorHandler() fiber.ErrorHandler {
 		code := fiber.StatusInternalServerError
 		message := utils.StatusMessage(code)
 
+		var validationErr *validation.Error
+		if errors.As(err, &validationErr) {
+			resp := response.Response{
+				Code:    fiber.StatusBadRequest,
+				Message: validationErr.Message,
+				Errors:  validationErr.Fields,
+			}
+			return c.Status(resp.Code).JSON(resp)
+		}
+
 		if err != nil {
 			if fiberErr, ok := err.(*fiber.Error); ok {
 				code = fiberErr.Code
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"strings"
@@ -8,24 +9,25 @@ import (
 
 	"github.com/gofiber/contrib/otelfiber"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/helmet"
-	"github.com/gofiber/fiber/v2/middleware/monitor"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/gofiber/fiber/v2/utils"
+	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/metrics"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
+	auditdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/audit"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
 )
 
 const requestIDKey = "requestid"
 
-func setupMiddlewares(app *fiber.App, cfg config.Config) {
-	if cfg.MetricsEnabled && strings.TrimSpace(cfg.MetricsPath) != "" {
-		app.Get(cfg.MetricsPath, monitor.New(monitor.Config{
-			Title: "Metrics",
-		}))
+func setupMiddlewares(app *fiber.App, cfg config.Config, registry *metrics.Registry) {
+	if registry != nil && cfg.MetricsEnabled && strings.TrimSpace(cfg.MetricsPath) != "" {
+		app.Get(cfg.MetricsPath, adaptor.HTTPHandler(registry.Handler()))
+		app.Use(requestMetrics(registry))
 	}
 
 	if cfg.TracingEnabled {
@@ -44,6 +46,7 @@ func setupMiddlewares(app *fiber.App, cfg config.Config) {
 	}))
 	app.Use(helmet.New())
 	app.Use(requestid.New())
+	app.Use(auditContext())
 	app.Use(requestLogger())
 	app.Use(recover.New(recover.Config{
 		EnableStackTrace: true,
@@ -90,6 +93,46 @@ func requestLogger() fiber.Handler {
 	}
 }
 
+// requestMetrics records every request under its route template, e.g.
+// /users/:id, so path parameters do not explode label cardinality.
+func requestMetrics(registry *metrics.Registry) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		route := c.Route()
+		routePath := route.Path
+		if route.Method == "USE" {
+			routePath = "unmatched"
+		}
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		registry.ObserveHTTPRequest(c.Method(), routePath, status, time.Since(start))
+		return err
+	}
+}
+
+// auditContext stores the request metadata audit entries need in the user
+// context. The auth middleware adds the actor once a request is authenticated.
+func auditContext() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(auditdomain.WithActor(c.UserContext(), auditdomain.Actor{
+			IP:        utils.CopyString(c.IP()),
+			UserAgent: utils.CopyString(c.Get(fiber.HeaderUserAgent)),
+			RequestID: utils.CopyString(getRequestID(c)),
+		}))
+		return c.Next()
+	}
+}
+
 func getRequestID(c *fiber.Ctx) string {
 	if c == nil {
 		return ""
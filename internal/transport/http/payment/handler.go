@@ -83,6 +83,78 @@ func (h *Handler) GetInvoiceById(c *fiber.Ctx) error {
 	return c.Status(resp.Code).JSON(resp)
 }
 
+// Get Invoice Status godoc
+// @Summary Get stored invoice status
+// @Description Returns the locally persisted invoice and its status transitions without calling Xendit.
+// @Tags Payment
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} response.Response{data=InvoiceStatusResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /payment/invoice/{id}/status [get]
+func (h *Handler) GetInvoiceStatus(c *fiber.Ctx) error {
+	invoiceID, err := validation.RequireParam(c.Params("id"), "invoice id")
+	if err != nil {
+		return err
+	}
+
+	stored, transitions, err := h.service.GetStoredInvoice(c.UserContext(), invoiceID)
+	if err != nil {
+		return mapPaymentError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: InvoiceStatusResponse{
+			Invoice:     toStoredInvoiceResponse(stored),
+			Transitions: toStatusTransitionResponses(transitions),
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// List Invoices By External Id godoc
+// @Summary List stored invoices by external id
+// @Tags Payment
+// @Security BearerAuth
+// @Produce json
+// @Param externalId path string true "External ID"
+// @Success 200 {object} response.Response{data=[]StoredInvoiceResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /payment/invoices/external/{externalId} [get]
+func (h *Handler) ListInvoicesByExternalID(c *fiber.Ctx) error {
+	externalID, err := validation.RequireParam(c.Params("externalId"), "external id")
+	if err != nil {
+		return err
+	}
+
+	invoices, err := h.service.ListInvoicesByExternalID(c.UserContext(), externalID)
+	if err != nil {
+		return mapPaymentError(err)
+	}
+
+	data := make([]StoredInvoiceResponse, 0, len(invoices))
+	for _, stored := range invoices {
+		data = append(data, toStoredInvoiceResponse(stored))
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data:    data,
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
 // Create Invoice godoc
 // @Summary Create Invoice
 // @Tags Payment
@@ -148,10 +220,13 @@ func (h *Handler) CreateInvoice(c *fiber.Ctx) error {
 // Expire Invoice godoc
 // @Summary Expire Invoice
 // @Tags Payment
+// @Security BearerAuth
 // @Produce json
 // @Param id path string true "Invoice ID"
 // @Success 200 {object} response.Response{data=invoice.Invoice}
 // @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /payment/invoice/{id}/expire [post]
 func (h *Handler) ExpireInvoice(c *fiber.Ctx) error {
@@ -216,6 +291,15 @@ func (h *Handler) InvoiceWebhook(c *fiber.Ctx) error {
 			logrus.WithFields(fields).WithError(err).Warn("xendit invoice webhook invalid payload")
 			return mapPaymentError(err)
 		}
+		if errors.Is(err, paymentdomain.ErrInvalidTransition) {
+			// Acknowledge so Xendit stops redelivering an out-of-order status.
+			logrus.WithFields(fields).WithError(err).Warn("xendit invoice webhook status transition rejected")
+			resp := response.Response{
+				Code:    fiber.StatusOK,
+				Message: "ignored",
+			}
+			return c.Status(resp.Code).JSON(resp)
+		}
 		logrus.WithFields(fields).WithError(err).Error("xendit invoice webhook processing failed")
 		return fiber.NewError(fiber.StatusInternalServerError, "webhook processing failed")
 	}
@@ -259,10 +343,51 @@ func mapInvoiceFees(fees []InvoiceFeeRequest) []paymentdomain.InvoiceFee {
 	return result
 }
 
+func toStoredInvoiceResponse(stored paymentdomain.Invoice) StoredInvoiceResponse {
+	return StoredInvoiceResponse{
+		ID:              stored.ID,
+		XenditInvoiceID: stored.XenditInvoiceID,
+		ExternalID:      stored.ExternalID,
+		Amount:          stored.Amount,
+		PaidAmount:      stored.PaidAmount,
+		Currency:        stored.Currency,
+		Status:          string(stored.Status),
+		PayerEmail:      stored.PayerEmail,
+		Description:     stored.Description,
+		InvoiceURL:      stored.InvoiceURL,
+		PaymentMethod:   stored.PaymentMethod,
+		PaymentChannel:  stored.PaymentChannel,
+		ExpiresAt:       stored.ExpiresAt,
+		PaidAt:          stored.PaidAt,
+		CreatedAt:       stored.CreatedAt,
+		UpdatedAt:       stored.UpdatedAt,
+	}
+}
+
+func toStatusTransitionResponses(transitions []paymentdomain.StatusTransition) []InvoiceStatusTransitionResponse {
+	result := make([]InvoiceStatusTransitionResponse, 0, len(transitions))
+	for _, transition := range transitions {
+		result = append(result, InvoiceStatusTransitionResponse{
+			FromStatus:  string(transition.FromStatus),
+			ToStatus:    string(transition.ToStatus),
+			Source:      transition.Source,
+			PayloadHash: transition.PayloadHash,
+			CreatedAt:   transition.CreatedAt,
+		})
+	}
+	return result
+}
+
 func mapPaymentError(err error) error {
 	if errors.Is(err, paymentdomain.ErrInvalidInput) {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid input")
 	}
+	if errors.Is(err, paymentdomain.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "invoice not found")
+	}
+	if errors.Is(err, paymentdomain.ErrInvalidTransition) {
+		return fiber.NewError(fiber.StatusConflict, "invalid invoice status transition")
+	}
 	if errors.Is(err, paymentdomain.ErrIdempotencyInProgress) {
 		return fiber.NewError(fiber.StatusConflict, "request is still being processed")
 	}
@@ -307,7 +432,7 @@ func (h *Handler) handleInvoiceWebhookWithRetry(ctx context.Context, payload inv
 	var lastErr error
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
 		if err := h.service.HandleInvoiceWebhook(ctx, payload, payloadHash); err != nil {
-			if errors.Is(err, paymentdomain.ErrInvalidInput) {
+			if errors.Is(err, paymentdomain.ErrInvalidInput) || errors.Is(err, paymentdomain.ErrInvalidTransition) {
 				return err
 			}
 			lastErr = err
@@ -1,17 +1,26 @@
 package payment
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"github.com/gofiber/fiber/v2"
+	httptransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http"
+)
+
+const (
+	permInvoiceRead   = "invoice.read"
+	permInvoiceExpire = "invoice.expire"
+)
 
 type Router struct {
 	handler *Handler
+	auth    *httptransport.AuthMiddleware
 }
 
-func NewRouter(handler *Handler) *Router {
-	return &Router{handler: handler}
+func NewRouter(handler *Handler, auth *httptransport.AuthMiddleware) *Router {
+	return &Router{handler: handler, auth: auth}
 }
 
 func (r *Router) Register(app *fiber.App) {
-	if r == nil || r.handler == nil || app == nil {
+	if r == nil || r.handler == nil || r.auth == nil || app == nil {
 		return
 	}
 
@@ -19,6 +28,8 @@ func (r *Router) Register(app *fiber.App) {
 	group.Get("/balance", r.handler.CheckBalance)
 	group.Post("/invoice", r.handler.CreateInvoice)
 	group.Get("/invoice/:id", r.handler.GetInvoiceById)
-	group.Post("/invoice/:id/expire", r.handler.ExpireInvoice)
+	r.auth.Handle(group, fiber.MethodGet, "/invoice/:id/status", permInvoiceRead, r.handler.GetInvoiceStatus)
+	r.auth.Handle(group, fiber.MethodGet, "/invoices/external/:externalId", permInvoiceRead, r.handler.ListInvoicesByExternalID)
+	r.auth.Handle(group, fiber.MethodPost, "/invoice/:id/expire", permInvoiceExpire, r.handler.ExpireInvoice)
 	group.Post("/webhook/xendit/invoice", r.handler.InvoiceWebhook)
 }
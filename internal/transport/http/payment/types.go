@@ -1,5 +1,7 @@
 package payment
 
+import "time"
+
 type BalanceResponse struct {
 	Balance float64 `json:"balance"`
 }
@@ -39,3 +41,35 @@ type InvoiceFeeRequest struct {
 	Type  string  `json:"type" validate:"required,notblank"`
 	Value float64 `json:"value" validate:"required,gte=0"`
 }
+
+type StoredInvoiceResponse struct {
+	ID              string     `json:"id"`
+	XenditInvoiceID string     `json:"xendit_invoice_id"`
+	ExternalID      string     `json:"external_id"`
+	Amount          float64    `json:"amount"`
+	PaidAmount      *float64   `json:"paid_amount,omitempty"`
+	Currency        string     `json:"currency,omitempty"`
+	Status          string     `json:"status"`
+	PayerEmail      string     `json:"payer_email,omitempty"`
+	Description     string     `json:"description,omitempty"`
+	InvoiceURL      string     `json:"invoice_url,omitempty"`
+	PaymentMethod   string     `json:"payment_method,omitempty"`
+	PaymentChannel  string     `json:"payment_channel,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	PaidAt          *time.Time `json:"paid_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type InvoiceStatusTransitionResponse struct {
+	FromStatus  string    `json:"from_status,omitempty"`
+	ToStatus    string    `json:"to_status"`
+	Source      string    `json:"source"`
+	PayloadHash string    `json:"payload_hash,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type InvoiceStatusResponse struct {
+	Invoice     StoredInvoiceResponse             `json:"invoice"`
+	Transitions []InvoiceStatusTransitionResponse `json:"transitions"`
+}
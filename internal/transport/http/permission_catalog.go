@@ -0,0 +1,94 @@
+package http
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
+
+// RoutePermission is a route and the permission it requires.
+type RoutePermission struct {
+	Method     string
+	Path       string
+	Permission string
+}
+
+// PermissionCatalog collects the permissions routes require. Routers declare
+// them while registering through AuthMiddleware.Handle and HandlePolicy, so
+// the catalog always matches what is enforced.
+type PermissionCatalog struct {
+	mu     sync.RWMutex
+	routes []RoutePermission
+}
+
+func NewPermissionCatalog() *PermissionCatalog {
+	return &PermissionCatalog{}
+}
+
+// Declare records that method and path require permission.
+func (c *PermissionCatalog) Declare(method, path, permission string) {
+	if c == nil {
+		return
+	}
+	permission = rbacdomain.NormalizePermission(permission)
+	if permission == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.routes = append(c.routes, RoutePermission{
+		Method:     strings.ToUpper(method),
+		Path:       path,
+		Permission: permission,
+	})
+}
+
+// Routes returns every declared route, ordered by permission, path and
+// method.
+func (c *PermissionCatalog) Routes() []RoutePermission {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	routes := append([]RoutePermission(nil), c.routes...)
+	c.mu.RUnlock()
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Permission != routes[j].Permission {
+			return routes[i].Permission < routes[j].Permission
+		}
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// Permissions returns the distinct declared permission names, sorted.
+func (c *PermissionCatalog) Permissions() []string {
+	var names []string
+	for _, route := range c.Routes() {
+		if len(names) == 0 || names[len(names)-1] != route.Permission {
+			names = append(names, route.Permission)
+		}
+	}
+	return names
+}
+
+// routePath joins path to the prefix of router, which is a *fiber.App or a
+// group created from one.
+func routePath(router fiber.Router, path string) string {
+	group, ok := router.(*fiber.Group)
+	if !ok {
+		return path
+	}
+	joined := strings.TrimRight(group.Prefix, "/") + "/" + strings.TrimLeft(path, "/")
+	if joined != "/" {
+		joined = strings.TrimRight(joined, "/")
+	}
+	return joined
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
+)
+
+// Policy decides whether an authenticated request may proceed. Policies are
+// plain predicates so routes can combine them with And, Or and Not.
+type Policy func(c *fiber.Ctx, auth AuthContext) bool
+
+// AnyPermission allows the request if the principal holds at least one of
+// permissions. Wildcard grants are honoured.
+func AnyPermission(permissions ...string) Policy {
+	required := normalizePermissions(permissions)
+	return func(_ *fiber.Ctx, auth AuthContext) bool {
+		return rbacdomain.CanAny(auth.Permissions, required)
+	}
+}
+
+// AllPermissions allows the request only if the principal holds every one of
+// permissions.
+func AllPermissions(permissions ...string) Policy {
+	required := normalizePermissions(permissions)
+	return func(_ *fiber.Ctx, auth AuthContext) bool {
+		for _, permission := range required {
+			if !rbacdomain.Can(auth.Permissions, permission) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// ResourcePermission allows the request if the principal holds permission
+// globally or on the resourceType whose ID is in the route parameter param,
+// e.g. ResourcePermission("invoice.read", "merchant", "merchantId").
+func ResourcePermission(permission, resourceType, param string) Policy {
+	required := rbacdomain.NormalizePermission(permission)
+	return func(c *fiber.Ctx, auth AuthContext) bool {
+		return auth.CanOn(required, resourceType, c.Params(param))
+	}
+}
+
+// AnyRole allows the request if the principal holds at least one of roles.
+// API keys carry no roles, so they never pass a role check.
+func AnyRole(roles ...string) Policy {
+	required := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		if normalized := strings.ToLower(strings.TrimSpace(role)); normalized != "" {
+			required[normalized] = struct{}{}
+		}
+	}
+	return func(_ *fiber.Ctx, auth AuthContext) bool {
+		for _, role := range auth.Roles {
+			if _, ok := required[strings.ToLower(strings.TrimSpace(role))]; ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Self allows the request when the route parameter param is the
+// authenticated user's own ID, e.g. Self("id") on /users/:id. API keys are
+// excluded so a narrowly scoped key cannot act on its owner's account.
+func Self(param string) Policy {
+	return func(c *fiber.Ctx, auth AuthContext) bool {
+		if auth.UserID == "" || auth.APIKeyID != "" {
+			return false
+		}
+		return strings.TrimSpace(c.Params(param)) == auth.UserID
+	}
+}
+
+// And allows the request only if every policy does.
+func And(policies ...Policy) Policy {
+	return func(c *fiber.Ctx, auth AuthContext) bool {
+		for _, policy := range policies {
+			if policy == nil || !policy(c, auth) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or allows the request if any policy does.
+func Or(policies ...Policy) Policy {
+	return func(c *fiber.Ctx, auth AuthContext) bool {
+		for _, policy := range policies {
+			if policy != nil && policy(c, auth) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not inverts policy. A nil policy denies, as it does in And and Or, so a
+// missing policy never turns into an allow.
+func Not(policy Policy) Policy {
+	return func(c *fiber.Ctx, auth AuthContext) bool {
+		return policy != nil && !policy(c, auth)
+	}
+}
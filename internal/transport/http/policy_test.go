@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// evaluate runs policy for a request to path, matched against /users/:id.
+func evaluate(t *testing.T, policy Policy, auth AuthContext, path string) bool {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		if policy(c, auth) {
+			return c.SendStatus(fiber.StatusOK)
+		}
+		return c.SendStatus(fiber.StatusForbidden)
+	})
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	return resp.StatusCode == fiber.StatusOK
+}
+
+func TestPolicies(t *testing.T) {
+	reader := AuthContext{UserID: "u1", Permissions: []string{"user.read"}, Roles: []string{"support"}}
+	wildcard := AuthContext{UserID: "u2", Permissions: []string{"user.*"}}
+	nobody := AuthContext{UserID: "u1"}
+	key := AuthContext{UserID: "u1", APIKeyID: "k1", Permissions: []string{"user.read"}}
+
+	cases := []struct {
+		name   string
+		policy Policy
+		auth   AuthContext
+		path   string
+		want   bool
+	}{
+		{"any permission held", AnyPermission("user.update", "user.read"), reader, "/users/u9", true},
+		{"any permission missing", AnyPermission("user.update"), reader, "/users/u9", false},
+		{"any permission wildcard", AnyPermission("user.update"), wildcard, "/users/u9", true},
+		{"all permissions held", AllPermissions("user.read", "user.update"), wildcard, "/users/u9", true},
+		{"all permissions partly held", AllPermissions("user.read", "user.update"), reader, "/users/u9", false},
+		{"all permissions empty", AllPermissions(), nobody, "/users/u9", true},
+		{"any role held", AnyRole(" Support "), reader, "/users/u9", true},
+		{"any role missing", AnyRole("admin"), reader, "/users/u9", false},
+
+		{"self own id", Self("id"), nobody, "/users/u1", true},
+		{"self other id", Self("id"), nobody, "/users/u2", false},
+		{"self without user", Self("id"), AuthContext{}, "/users/u1", false},
+		{"self api key on own id", Self("id"), key, "/users/u1", false},
+		{"self missing param", Self("userId"), nobody, "/users/u1", false},
+
+		{"or first", Or(Self("id"), AnyPermission("user.update")), nobody, "/users/u1", true},
+		{"or second", Or(Self("id"), AnyPermission("user.read")), reader, "/users/u9", true},
+		{"or none", Or(Self("id"), AnyPermission("user.update")), reader, "/users/u9", false},
+		{"or empty", Or(), reader, "/users/u9", false},
+		{"or skips nil", Or(nil, Self("id")), nobody, "/users/u1", true},
+		{"or api key falls back to permission", Or(Self("id"), AnyPermission("user.read")), key, "/users/u1", true},
+		{"or api key without permission", Or(Self("id"), AnyPermission("user.update")), key, "/users/u1", false},
+
+		{"and all", And(AnyPermission("user.read"), AnyRole("support")), reader, "/users/u9", true},
+		{"and one fails", And(AnyPermission("user.read"), AnyRole("admin")), reader, "/users/u9", false},
+		{"and empty", And(), nobody, "/users/u9", true},
+		{"and nil denies", And(AnyPermission("user.read"), nil), reader, "/users/u9", false},
+
+		{"not allows", Not(Self("id")), nobody, "/users/u2", true},
+		{"not denies", Not(Self("id")), nobody, "/users/u1", false},
+		{"not nil denies", Not(nil), nobody, "/users/u1", false},
+		{"not nil denies for any principal", Not(nil), wildcard, "/users/u9", false},
+		{"or with not nil", Or(Not(nil), AnyPermission("user.update")), reader, "/users/u9", false},
+		{"nested", And(AnyPermission("user.read"), Not(Self("id"))), reader, "/users/u1", false},
+	}
+	for _, tc := range cases {
+		if got := evaluate(t, tc.policy, tc.auth, tc.path); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestSelfNeverMatchesAPIKey(t *testing.T) {
+	for _, auth := range []AuthContext{
+		{UserID: "u1", APIKeyID: "k1"},
+		{UserID: "u1", APIKeyID: "k1", Permissions: []string{"*"}},
+	} {
+		if evaluate(t, Self("id"), auth, "/users/u1") {
+			t.Fatalf("expected api key principal %+v not to satisfy Self", auth)
+		}
+		if evaluate(t, Or(Self("id"), AnyRole("admin")), auth, "/users/u1") {
+			t.Fatalf("expected api key principal %+v not to satisfy Self through Or", auth)
+		}
+	}
+}
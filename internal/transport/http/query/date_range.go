@@ -0,0 +1,77 @@
+package query
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ParseCreatedDateRange reads created_from and created_to. Plain dates cover
+// the whole UTC day; RFC3339 values are used as given.
+func ParseCreatedDateRange(c *fiber.Ctx) (*time.Time, *time.Time, error) {
+	fromRaw := strings.TrimSpace(c.Query("created_from"))
+	toRaw := strings.TrimSpace(c.Query("created_to"))
+	if fromRaw == "" && toRaw == "" {
+		return nil, nil, nil
+	}
+
+	var createdFrom *time.Time
+	if fromRaw != "" {
+		from, hasTime, err := parseDateQuery(fromRaw, "created_from")
+		if err != nil {
+			return nil, nil, err
+		}
+		if !hasTime {
+			from = startOfDayUTC(from)
+		} else {
+			from = from.UTC()
+		}
+		createdFrom = &from
+	}
+
+	var createdTo *time.Time
+	if toRaw != "" {
+		to, hasTime, err := parseDateQuery(toRaw, "created_to")
+		if err != nil {
+			return nil, nil, err
+		}
+		if !hasTime {
+			to = endOfDayUTC(to)
+		} else {
+			to = to.UTC()
+		}
+		createdTo = &to
+	}
+
+	if createdFrom != nil && createdTo != nil && createdFrom.After(*createdTo) {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "created_from must be before or equal to created_to")
+	}
+
+	return createdFrom, createdTo, nil
+}
+
+func parseDateQuery(raw, label string) (time.Time, bool, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, false, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339Nano, raw); err == nil {
+		return parsed, true, nil
+	}
+
+	if parsed, err := time.Parse("2006-01-02", raw); err == nil {
+		return parsed, false, nil
+	}
+
+	return time.Time{}, false, fiber.NewError(fiber.StatusBadRequest, label+" must be RFC3339 or YYYY-MM-DD")
+}
+
+func startOfDayUTC(value time.Time) time.Time {
+	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func endOfDayUTC(value time.Time) time.Time {
+	return startOfDayUTC(value).Add(24*time.Hour - time.Nanosecond)
+}
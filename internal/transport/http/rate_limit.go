@@ -0,0 +1,163 @@
+package http
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/ratelimit"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	headerRateLimitLimit     = "RateLimit-Limit"
+	headerRateLimitRemaining = "RateLimit-Remaining"
+	headerRateLimitReset     = "RateLimit-Reset"
+)
+
+// RateLimitKeyFunc returns the identity a policy counts requests against.
+// An empty key skips the policy for that request.
+type RateLimitKeyFunc func(c *fiber.Ctx) string
+
+// RateLimitPolicy allows Limit requests per Window for each key. Name
+// separates the counters of policies that share a key function.
+type RateLimitPolicy struct {
+	Name    string
+	Limit   int
+	Window  time.Duration
+	Key     RateLimitKeyFunc
+	Message string
+}
+
+func (p RateLimitPolicy) enabled() bool {
+	return p.Limit > 0 && p.Window > 0 && p.Key != nil
+}
+
+// RateLimiter turns policies into middleware backed by a shared Redis
+// limiter, so limits hold across replicas and restarts. A nil RateLimiter
+// produces middleware that lets every request through.
+type RateLimiter struct {
+	limiter *ratelimit.Limiter
+}
+
+func NewRateLimiter(limiter *ratelimit.Limiter) *RateLimiter {
+	if limiter == nil {
+		return nil
+	}
+	return &RateLimiter{limiter: limiter}
+}
+
+// Middleware enforces policy on the routes it is attached to.
+func (l *RateLimiter) Middleware(policy RateLimitPolicy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := l.check(c, policy); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// Use returns a Router that enforces policy on every route registered after
+// it, which lets the app apply a default limit by its position in the router
+// list.
+func (l *RateLimiter) Use(policy RateLimitPolicy) Router {
+	return rateLimitRouter{limiter: l, policy: policy}
+}
+
+type rateLimitRouter struct {
+	limiter *RateLimiter
+	policy  RateLimitPolicy
+}
+
+func (r rateLimitRouter) Register(app *fiber.App) {
+	if r.limiter == nil || !r.policy.enabled() || app == nil {
+		return
+	}
+	app.Use(r.limiter.Middleware(r.policy))
+}
+
+// check counts the request against policy and sets the RateLimit-* headers.
+// Redis errors are logged and the request is allowed, so an outage degrades
+// to no limiting rather than to failing every request.
+func (l *RateLimiter) check(c *fiber.Ctx, policy RateLimitPolicy) error {
+	if l == nil || l.limiter == nil || !policy.enabled() {
+		return nil
+	}
+	key := policy.Key(c)
+	if key == "" {
+		return nil
+	}
+
+	result, err := l.limiter.Allow(c.UserContext(), policy.Name+":"+key, policy.Limit, policy.Window)
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{
+			"policy":     policy.Name,
+			"request_id": getRequestID(c),
+		}).Warn("rate limit check failed")
+		return nil
+	}
+
+	resetSeconds := strconv.Itoa(ceilSeconds(result.ResetAfter))
+	c.Set(headerRateLimitLimit, strconv.Itoa(result.Limit))
+	c.Set(headerRateLimitRemaining, strconv.Itoa(result.Remaining))
+	c.Set(headerRateLimitReset, resetSeconds)
+	if result.Allowed {
+		return nil
+	}
+
+	c.Set(fiber.HeaderRetryAfter, resetSeconds)
+	message := policy.Message
+	if message == "" {
+		message = "too many requests"
+	}
+	return fiber.NewError(fiber.StatusTooManyRequests, message)
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
+
+// RateLimitByIP keys requests by client IP.
+func RateLimitByIP(c *fiber.Ctx) string {
+	return "ip:" + c.IP()
+}
+
+// RateLimitByUser keys requests by the authenticated user, or by IP before
+// authentication has run.
+func RateLimitByUser(c *fiber.Ctx) string {
+	if ctx, ok := GetAuthContext(c); ok && ctx.UserID != "" {
+		return "user:" + ctx.UserID
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitByPrincipal keys API key requests by key and everything else like
+// RateLimitByUser, so each machine client has its own budget.
+func RateLimitByPrincipal(c *fiber.Ctx) string {
+	if ctx, ok := GetAuthContext(c); ok && ctx.APIKeyID != "" {
+		return "api_key:" + ctx.APIKeyID
+	}
+	return RateLimitByUser(c)
+}
+
+// RateLimitByEmail keys requests by the email field of a JSON body. Requests
+// without one are skipped; pair it with an IP policy.
+func RateLimitByEmail(c *fiber.Ctx) string {
+	var body struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return ""
+	}
+	email := strings.ToLower(strings.TrimSpace(body.Email))
+	if email == "" {
+		return ""
+	}
+	return "email:" + email
+}
@@ -8,17 +8,21 @@ import (
 	"github.com/gofiber/fiber/v2"
 	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 	rbacusecase "github.com/mzulfanw/boilerplate-go-fiber/internal/service/rbac"
+	httptransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/query"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/response"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/validation"
 )
 
+const mimeApplicationYAML = "application/yaml"
+
 type Handler struct {
 	service *rbacusecase.Service
+	catalog *httptransport.PermissionCatalog
 }
 
-func NewHandler(service *rbacusecase.Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *rbacusecase.Service, catalog *httptransport.PermissionCatalog) *Handler {
+	return &Handler{service: service, catalog: catalog}
 }
 
 // ListRoles godoc
@@ -41,7 +45,7 @@ func (h *Handler) ListRoles(c *fiber.Ctx) error {
 	}
 
 	search := query.ParseSearch(c, "search")
-	createdFrom, createdTo, err := parseCreatedDateRange(c)
+	createdFrom, createdTo, err := query.ParseCreatedDateRange(c)
 	if err != nil {
 		return err
 	}
@@ -137,6 +141,7 @@ func (h *Handler) CreateRole(c *fiber.Ctx) error {
 // @Success 200 {object} response.Response{data=RoleResponse}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /rbac/roles/{id} [put]
 func (h *Handler) UpdateRole(c *fiber.Ctx) error {
 	roleID, err := validation.RequireParam(c.Params("id"), "role id")
@@ -172,6 +177,7 @@ func (h *Handler) UpdateRole(c *fiber.Ctx) error {
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /rbac/roles/{id} [delete]
 func (h *Handler) DeleteRole(c *fiber.Ctx) error {
 	roleID, err := validation.RequireParam(c.Params("id"), "role id")
@@ -209,7 +215,7 @@ func (h *Handler) ListPermissions(c *fiber.Ctx) error {
 		return err
 	}
 	search := query.ParseSearch(c, "search")
-	createdFrom, createdTo, err := parseCreatedDateRange(c)
+	createdFrom, createdTo, err := query.ParseCreatedDateRange(c)
 	if err != nil {
 		return err
 	}
@@ -304,6 +310,7 @@ func (h *Handler) CreatePermission(c *fiber.Ctx) error {
 // @Success 200 {object} response.Response{data=PermissionResponse}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /rbac/permissions/{id} [put]
 func (h *Handler) UpdatePermission(c *fiber.Ctx) error {
 	permissionID, err := validation.RequireParam(c.Params("id"), "permission id")
@@ -339,6 +346,7 @@ func (h *Handler) UpdatePermission(c *fiber.Ctx) error {
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /rbac/permissions/{id} [delete]
 func (h *Handler) DeletePermission(c *fiber.Ctx) error {
 	permissionID, err := validation.RequireParam(c.Params("id"), "permission id")
@@ -400,6 +408,7 @@ func (h *Handler) ListRolePermissions(c *fiber.Ctx) error {
 // @Success 200 {object} response.Response{data=RolePermissionsResponse}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /rbac/roles/{id}/permissions [put]
 func (h *Handler) UpdateRolePermissions(c *fiber.Ctx) error {
 	roleID, err := validation.RequireParam(c.Params("id"), "role id")
@@ -432,84 +441,478 @@ func (h *Handler) UpdateRolePermissions(c *fiber.Ctx) error {
 	return c.Status(resp.Code).JSON(resp)
 }
 
-func mapRBACError(err error) error {
-	switch {
-	case errors.Is(err, rbacdomain.ErrInvalidInput):
-		return fiber.NewError(fiber.StatusBadRequest, "invalid input")
-	case errors.Is(err, rbacdomain.ErrNotFound):
-		return fiber.NewError(fiber.StatusNotFound, "resource not found")
-	case errors.Is(err, rbacdomain.ErrConflict):
-		return fiber.NewError(fiber.StatusConflict, "resource already exists")
-	default:
+// ListRoleParents godoc
+// @Summary List role parents
+// @Description Roles this role inherits permissions from.
+// @Tags RBAC
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} response.Response{data=RoleParentsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /rbac/roles/{id}/parents [get]
+func (h *Handler) ListRoleParents(c *fiber.Ctx) error {
+	roleID, err := validation.RequireParam(c.Params("id"), "role id")
+	if err != nil {
 		return err
 	}
+	return h.respondRoleParents(c, roleID)
 }
 
-func parseCreatedDateRange(c *fiber.Ctx) (*time.Time, *time.Time, error) {
-	fromRaw := strings.TrimSpace(c.Query("created_from"))
-	toRaw := strings.TrimSpace(c.Query("created_to"))
-	if fromRaw == "" && toRaw == "" {
-		return nil, nil, nil
+// UpdateRoleParents godoc
+// @Summary Replace role parents
+// @Tags RBAC
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param payload body RoleParentsRequest true "Role parents payload"
+// @Success 200 {object} response.Response{data=RoleParentsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /rbac/roles/{id}/parents [put]
+func (h *Handler) UpdateRoleParents(c *fiber.Ctx) error {
+	roleID, err := validation.RequireParam(c.Params("id"), "role id")
+	if err != nil {
+		return err
 	}
 
-	var createdFrom *time.Time
-	if fromRaw != "" {
-		from, hasTime, err := parseDateQuery(fromRaw, "created_from")
-		if err != nil {
-			return nil, nil, err
-		}
-		if !hasTime {
-			from = startOfDayUTC(from)
-		} else {
-			from = from.UTC()
-		}
-		createdFrom = &from
+	var req RoleParentsRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
 	}
 
-	var createdTo *time.Time
-	if toRaw != "" {
-		to, hasTime, err := parseDateQuery(toRaw, "created_to")
-		if err != nil {
-			return nil, nil, err
-		}
-		if !hasTime {
-			to = endOfDayUTC(to)
-		} else {
-			to = to.UTC()
-		}
-		createdTo = &to
+	if err := h.service.ReplaceRoleParents(c.UserContext(), roleID, req.ParentIDs); err != nil {
+		return mapRBACError(err)
+	}
+	return h.respondRoleParents(c, roleID)
+}
+
+// AddRoleParent godoc
+// @Summary Add role parent
+// @Tags RBAC
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param parentId path string true "Parent role ID"
+// @Success 200 {object} response.Response{data=RoleParentsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /rbac/roles/{id}/parents/{parentId} [post]
+func (h *Handler) AddRoleParent(c *fiber.Ctx) error {
+	roleID, err := validation.RequireParam(c.Params("id"), "role id")
+	if err != nil {
+		return err
+	}
+	parentID, err := validation.RequireParam(c.Params("parentId"), "parent role id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.AddRoleParent(c.UserContext(), roleID, parentID); err != nil {
+		return mapRBACError(err)
+	}
+	return h.respondRoleParents(c, roleID)
+}
+
+// RemoveRoleParent godoc
+// @Summary Remove role parent
+// @Tags RBAC
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param parentId path string true "Parent role ID"
+// @Success 200 {object} response.Response{data=RoleParentsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /rbac/roles/{id}/parents/{parentId} [delete]
+func (h *Handler) RemoveRoleParent(c *fiber.Ctx) error {
+	roleID, err := validation.RequireParam(c.Params("id"), "role id")
+	if err != nil {
+		return err
+	}
+	parentID, err := validation.RequireParam(c.Params("parentId"), "parent role id")
+	if err != nil {
+		return err
 	}
 
-	if createdFrom != nil && createdTo != nil && createdFrom.After(*createdTo) {
-		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "created_from must be before or equal to created_to")
+	if err := h.service.RemoveRoleParent(c.UserContext(), roleID, parentID); err != nil {
+		return mapRBACError(err)
+	}
+	return h.respondRoleParents(c, roleID)
+}
+
+func (h *Handler) respondRoleParents(c *fiber.Ctx, roleID string) error {
+	parents, err := h.service.ListRoleParents(c.UserContext(), roleID)
+	if err != nil {
+		return mapRBACError(err)
 	}
 
-	return createdFrom, createdTo, nil
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: RoleParentsResponse{
+			RoleID:  roleID,
+			Parents: mapRoles(parents),
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
 }
 
-func parseDateQuery(raw, label string) (time.Time, bool, error) {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return time.Time{}, false, nil
+// GetRoleConstraints godoc
+// @Summary Get role constraints
+// @Description Whether grants of the role need a second administrator's approval, and the roles a user may not hold together with it.
+// @Tags RBAC
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Role ID"
+// @Success 200 {object} response.Response{data=RoleConstraintsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /rbac/roles/{id}/constraints [get]
+func (h *Handler) GetRoleConstraints(c *fiber.Ctx) error {
+	roleID, err := validation.RequireParam(c.Params("id"), "role id")
+	if err != nil {
+		return err
 	}
+	return h.respondRoleConstraints(c, roleID)
+}
 
-	if parsed, err := time.Parse(time.RFC3339Nano, raw); err == nil {
-		return parsed, true, nil
+// UpdateRoleConstraints godoc
+// @Summary Replace role constraints
+// @Description Exclusions are symmetric. Fails with 409 if a user already holds the role together with one it would exclude.
+// @Tags RBAC
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Role ID"
+// @Param payload body RoleConstraintsRequest true "Role constraints payload"
+// @Success 200 {object} response.Response{data=RoleConstraintsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /rbac/roles/{id}/constraints [put]
+func (h *Handler) UpdateRoleConstraints(c *fiber.Ctx) error {
+	roleID, err := validation.RequireParam(c.Params("id"), "role id")
+	if err != nil {
+		return err
 	}
 
-	if parsed, err := time.Parse("2006-01-02", raw); err == nil {
-		return parsed, false, nil
+	var req RoleConstraintsRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
 	}
 
-	return time.Time{}, false, fiber.NewError(fiber.StatusBadRequest, label+" must be RFC3339 or YYYY-MM-DD")
+	if err := h.service.ReplaceRoleConstraints(c.UserContext(), roleID, req.RequiresApproval, req.ExcludedRoleIDs); err != nil {
+		return mapRBACError(err)
+	}
+	return h.respondRoleConstraints(c, roleID)
 }
 
-func startOfDayUTC(value time.Time) time.Time {
-	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+func (h *Handler) respondRoleConstraints(c *fiber.Ctx, roleID string) error {
+	constraints, err := h.service.GetRoleConstraints(c.UserContext(), roleID)
+	if err != nil {
+		return mapRBACError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: RoleConstraintsResponse{
+			RoleID:           roleID,
+			RequiresApproval: constraints.RequiresApproval,
+			ExcludedRoles:    mapRoles(constraints.ExcludedRoles),
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// ListUserResourceRoles godoc
+// @Summary List user resource roles
+// @Description Roles the user holds on individual resources. Global roles are listed under /users/{id}/roles.
+// @Tags RBAC
+// @Security BearerAuth
+// @Produce json
+// @Param userId path string true "User ID"
+// @Success 200 {object} response.Response{data=UserResourceRolesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /rbac/users/{userId}/resource-roles [get]
+func (h *Handler) ListUserResourceRoles(c *fiber.Ctx) error {
+	userID, err := validation.RequireParam(c.Params("userId"), "user id")
+	if err != nil {
+		return err
+	}
+	return h.respondUserResourceRoles(c, userID)
 }
 
-func endOfDayUTC(value time.Time) time.Time {
-	return startOfDayUTC(value).Add(24*time.Hour - time.Nanosecond)
+// GrantResourceRole godoc
+// @Summary Grant resource role
+// @Description Gives the user the role's permissions on one resource only.
+// @Tags RBAC
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param payload body ResourceRoleRequest true "Resource role payload"
+// @Success 200 {object} response.Response{data=UserResourceRolesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /rbac/users/{userId}/resource-roles [post]
+func (h *Handler) GrantResourceRole(c *fiber.Ctx) error {
+	userID, err := validation.RequireParam(c.Params("userId"), "user id")
+	if err != nil {
+		return err
+	}
+
+	var req ResourceRoleRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.service.GrantResourceRole(c.UserContext(), userID, req.RoleID, req.ResourceType, req.ResourceID); err != nil {
+		return mapRBACError(err)
+	}
+	return h.respondUserResourceRoles(c, userID)
+}
+
+// RevokeResourceRole godoc
+// @Summary Revoke resource role
+// @Tags RBAC
+// @Security BearerAuth
+// @Produce json
+// @Param userId path string true "User ID"
+// @Param roleId path string true "Role ID"
+// @Param resourceType path string true "Resource type"
+// @Param resourceId path string true "Resource ID"
+// @Success 200 {object} response.Response{data=UserResourceRolesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /rbac/users/{userId}/resource-roles/{roleId}/{resourceType}/{resourceId} [delete]
+func (h *Handler) RevokeResourceRole(c *fiber.Ctx) error {
+	userID, err := validation.RequireParam(c.Params("userId"), "user id")
+	if err != nil {
+		return err
+	}
+	roleID, err := validation.RequireParam(c.Params("roleId"), "role id")
+	if err != nil {
+		return err
+	}
+	resourceType, err := validation.RequireParam(c.Params("resourceType"), "resource type")
+	if err != nil {
+		return err
+	}
+	resourceID, err := validation.RequireParam(c.Params("resourceId"), "resource id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.RevokeResourceRole(c.UserContext(), userID, roleID, resourceType, resourceID); err != nil {
+		return mapRBACError(err)
+	}
+	return h.respondUserResourceRoles(c, userID)
+}
+
+func (h *Handler) respondUserResourceRoles(c *fiber.Ctx, userID string) error {
+	assignments, err := h.service.ListUserResourceRoles(c.UserContext(), userID)
+	if err != nil {
+		return mapRBACError(err)
+	}
+
+	roles := make([]ResourceRoleResponse, 0, len(assignments))
+	for _, assignment := range assignments {
+		roles = append(roles, ResourceRoleResponse{
+			Role:         mapRole(assignment.Role),
+			ResourceType: assignment.ResourceType,
+			ResourceID:   assignment.ResourceID,
+			CreatedAt:    assignment.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: UserResourceRolesResponse{
+			UserID: userID,
+			Roles:  roles,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// GetPermissionCatalog godoc
+// @Summary Permission catalog
+// @Description Every permission a route requires, with the routes requiring it and whether it is stored, plus stored permissions that no route requires (orphaned), even through a wildcard.
+// @Tags RBAC
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=PermissionCatalogResponse}
+// @Failure 401 {object} response.Response
+// @Router /rbac/permissions/catalog [get]
+func (h *Handler) GetPermissionCatalog(c *fiber.Ctx) error {
+	required := h.catalog.Permissions()
+	status, err := h.service.CatalogStatus(c.UserContext(), required)
+	if err != nil {
+		return mapRBACError(err)
+	}
+	missing := make(map[string]struct{}, len(status.Missing))
+	for _, name := range status.Missing {
+		missing[name] = struct{}{}
+	}
+
+	permissions := make([]CatalogPermissionResponse, 0, len(required))
+	for _, route := range h.catalog.Routes() {
+		if n := len(permissions); n == 0 || permissions[n-1].Name != route.Permission {
+			_, isMissing := missing[route.Permission]
+			permissions = append(permissions, CatalogPermissionResponse{
+				Name:   route.Permission,
+				Stored: !isMissing,
+				Routes: []CatalogRouteResponse{},
+			})
+		}
+		last := &permissions[len(permissions)-1]
+		last.Routes = append(last.Routes, CatalogRouteResponse{Method: route.Method, Path: route.Path})
+	}
+
+	orphaned := status.Orphaned
+	if orphaned == nil {
+		orphaned = []string{}
+	}
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: PermissionCatalogResponse{
+			Permissions: permissions,
+			Orphaned:    orphaned,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// ExportPolicy godoc
+// @Summary Export RBAC policy
+// @Description Every role and permission with their grants and parents, identified by name. The document is returned without the response envelope so it can be passed to POST /rbac/import as is.
+// @Tags RBAC
+// @Security BearerAuth
+// @Produce json
+// @Produce application/yaml
+// @Param format query string false "Document format" Enums(json, yaml)
+// @Success 200 {object} rbacdomain.Policy
+// @Failure 400 {object} response.Response
+// @Router /rbac/export [get]
+func (h *Handler) ExportPolicy(c *fiber.Ctx) error {
+	format := strings.ToLower(strings.TrimSpace(c.Query("format", rbacdomain.PolicyFormatJSON)))
+	if format != rbacdomain.PolicyFormatJSON && format != rbacdomain.PolicyFormatYAML {
+		return fiber.NewError(fiber.StatusBadRequest, "format must be json or yaml")
+	}
+
+	policy, err := h.service.ExportPolicy(c.UserContext())
+	if err != nil {
+		return mapRBACError(err)
+	}
+	data, err := rbacdomain.EncodePolicy(policy, format)
+	if err != nil {
+		return err
+	}
+
+	contentType := fiber.MIMEApplicationJSONCharsetUTF8
+	if format == rbacdomain.PolicyFormatYAML {
+		contentType = mimeApplicationYAML
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="rbac-policy.`+format+`"`)
+	return c.Status(fiber.StatusOK).Send(data)
+}
+
+// ImportPolicy godoc
+// @Summary Import RBAC policy
+// @Description Makes roles and permissions match the document in one transaction. Send YAML with a Content-Type containing "yaml", JSON otherwise. Roles may only reference permissions and parents declared in the document. With dry_run nothing is changed; with prune, roles and permissions missing from the document are deleted. System entries cannot be pruned and system roles must keep their system permissions.
+// @Tags RBAC
+// @Security BearerAuth
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Param dry_run query bool false "Only report the changes"
+// @Param prune query bool false "Delete roles and permissions missing from the document"
+// @Param payload body rbacdomain.Policy true "Policy document"
+// @Success 200 {object} response.Response{data=PolicyImportResponse}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /rbac/import [post]
+func (h *Handler) ImportPolicy(c *fiber.Ctx) error {
+	dryRun, err := query.ParseOptionalBool(c, "dry_run")
+	if err != nil {
+		return err
+	}
+	prune, err := query.ParseOptionalBool(c, "prune")
+	if err != nil {
+		return err
+	}
+
+	format := rbacdomain.PolicyFormatJSON
+	if strings.Contains(strings.ToLower(c.Get(fiber.HeaderContentType)), "yaml") {
+		format = rbacdomain.PolicyFormatYAML
+	}
+	policy, err := rbacdomain.ParsePolicy(c.Body(), format)
+	if err != nil {
+		return mapRBACError(err)
+	}
+
+	opts := rbacusecase.PolicyImportOptions{
+		DryRun: dryRun != nil && *dryRun,
+		Prune:  prune != nil && *prune,
+	}
+	result, err := h.service.ImportPolicy(c.UserContext(), policy, opts)
+	if err != nil {
+		return mapRBACError(err)
+	}
+
+	changes := make([]PolicyChangeResponse, 0, len(result.Changes))
+	for _, change := range result.Changes {
+		changes = append(changes, PolicyChangeResponse{
+			Action: change.Action,
+			Kind:   change.Kind,
+			Name:   change.Name,
+			Target: change.Target,
+		})
+	}
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: PolicyImportResponse{
+			DryRun:  opts.DryRun,
+			Applied: result.Applied,
+			Changes: changes,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+func mapRBACError(err error) error {
+	switch {
+	case errors.Is(err, rbacdomain.ErrInvalidInput):
+		return fiber.NewError(fiber.StatusBadRequest, "invalid input")
+	case errors.Is(err, rbacdomain.ErrNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "resource not found")
+	case errors.Is(err, rbacdomain.ErrConflict):
+		return fiber.NewError(fiber.StatusConflict, "resource already exists")
+	case errors.Is(err, rbacdomain.ErrCycle):
+		return fiber.NewError(fiber.StatusConflict, "role hierarchy would contain a cycle")
+	case errors.Is(err, rbacdomain.ErrInvalidPolicy):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, rbacdomain.ErrProtected):
+		return fiber.NewError(fiber.StatusConflict, "system roles and permissions cannot be removed or renamed")
+	case errors.Is(err, rbacdomain.ErrExclusionHeld):
+		return fiber.NewError(fiber.StatusConflict, "a user would hold mutually exclusive roles")
+	case errors.Is(err, rbacdomain.ErrApprovalRequired):
+		return fiber.NewError(fiber.StatusConflict, "roles that require approval cannot be inherited or granted on a resource")
+	default:
+		return err
+	}
 }
 
 func mapRoles(roles []rbacdomain.Role) []RoleResponse {
@@ -525,6 +928,7 @@ func mapRole(role rbacdomain.Role) RoleResponse {
 		ID:          role.ID,
 		Name:        role.Name,
 		Description: role.Description,
+		IsSystem:    role.IsSystem,
 		CreatedAt:   role.CreatedAt.UTC().Format(time.RFC3339),
 	}
 }
@@ -542,6 +946,7 @@ func mapPermission(permission rbacdomain.Permission) PermissionResponse {
 		ID:          permission.ID,
 		Name:        permission.Name,
 		Description: permission.Description,
+		IsSystem:    permission.IsSystem,
 		CreatedAt:   permission.CreatedAt.UTC().Format(time.RFC3339),
 	}
 }
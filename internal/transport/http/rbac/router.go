@@ -16,6 +16,14 @@ const (
 	permPermissionDelete     = "permission.delete"
 	permRolePermissionRead   = "role.permission.read"
 	permRolePermissionUpdate = "role.permission.update"
+	permRoleParentRead       = "role.parent.read"
+	permRoleParentUpdate     = "role.parent.update"
+	permRoleConstraintRead   = "role.constraint.read"
+	permRoleConstraintUpdate = "role.constraint.update"
+	permUserRoleRead         = "user.role.read"
+	permUserRoleUpdate       = "user.role.update"
+	permPolicyExport         = "rbac.policy.export"
+	permPolicyImport         = "rbac.policy.import"
 )
 
 type Router struct {
@@ -34,18 +42,34 @@ func (r *Router) Register(app *fiber.App) {
 
 	group := app.Group("/rbac", r.auth.RequireAuth())
 
-	group.Get("/roles", r.auth.RequirePermissions(permRoleRead), r.handler.ListRoles)
-	group.Get("/roles/:id", r.auth.RequirePermissions(permRoleRead), r.handler.GetRole)
-	group.Post("/roles", r.auth.RequirePermissions(permRoleCreate), r.handler.CreateRole)
-	group.Put("/roles/:id", r.auth.RequirePermissions(permRoleUpdate), r.handler.UpdateRole)
-	group.Delete("/roles/:id", r.auth.RequirePermissions(permRoleDelete), r.handler.DeleteRole)
+	r.auth.Handle(group, fiber.MethodGet, "/roles", permRoleRead, r.handler.ListRoles)
+	r.auth.Handle(group, fiber.MethodGet, "/roles/:id", permRoleRead, r.handler.GetRole)
+	r.auth.Handle(group, fiber.MethodPost, "/roles", permRoleCreate, r.handler.CreateRole)
+	r.auth.Handle(group, fiber.MethodPut, "/roles/:id", permRoleUpdate, r.handler.UpdateRole)
+	r.auth.Handle(group, fiber.MethodDelete, "/roles/:id", permRoleDelete, r.handler.DeleteRole)
 
-	group.Get("/roles/:id/permissions", r.auth.RequirePermissions(permRolePermissionRead), r.handler.ListRolePermissions)
-	group.Put("/roles/:id/permissions", r.auth.RequirePermissions(permRolePermissionUpdate), r.handler.UpdateRolePermissions)
+	r.auth.Handle(group, fiber.MethodGet, "/roles/:id/permissions", permRolePermissionRead, r.handler.ListRolePermissions)
+	r.auth.Handle(group, fiber.MethodPut, "/roles/:id/permissions", permRolePermissionUpdate, r.handler.UpdateRolePermissions)
 
-	group.Get("/permissions", r.auth.RequirePermissions(permPermissionRead), r.handler.ListPermissions)
-	group.Get("/permissions/:id", r.auth.RequirePermissions(permPermissionRead), r.handler.GetPermission)
-	group.Post("/permissions", r.auth.RequirePermissions(permPermissionCreate), r.handler.CreatePermission)
-	group.Put("/permissions/:id", r.auth.RequirePermissions(permPermissionUpdate), r.handler.UpdatePermission)
-	group.Delete("/permissions/:id", r.auth.RequirePermissions(permPermissionDelete), r.handler.DeletePermission)
+	r.auth.Handle(group, fiber.MethodGet, "/roles/:id/parents", permRoleParentRead, r.handler.ListRoleParents)
+	r.auth.Handle(group, fiber.MethodPut, "/roles/:id/parents", permRoleParentUpdate, r.handler.UpdateRoleParents)
+	r.auth.Handle(group, fiber.MethodPost, "/roles/:id/parents/:parentId", permRoleParentUpdate, r.handler.AddRoleParent)
+	r.auth.Handle(group, fiber.MethodDelete, "/roles/:id/parents/:parentId", permRoleParentUpdate, r.handler.RemoveRoleParent)
+
+	r.auth.Handle(group, fiber.MethodGet, "/roles/:id/constraints", permRoleConstraintRead, r.handler.GetRoleConstraints)
+	r.auth.Handle(group, fiber.MethodPut, "/roles/:id/constraints", permRoleConstraintUpdate, r.handler.UpdateRoleConstraints)
+
+	r.auth.Handle(group, fiber.MethodGet, "/users/:userId/resource-roles", permUserRoleRead, r.handler.ListUserResourceRoles)
+	r.auth.Handle(group, fiber.MethodPost, "/users/:userId/resource-roles", permUserRoleUpdate, r.handler.GrantResourceRole)
+	r.auth.Handle(group, fiber.MethodDelete, "/users/:userId/resource-roles/:roleId/:resourceType/:resourceId", permUserRoleUpdate, r.handler.RevokeResourceRole)
+
+	r.auth.Handle(group, fiber.MethodGet, "/export", permPolicyExport, r.handler.ExportPolicy)
+	r.auth.Handle(group, fiber.MethodPost, "/import", permPolicyImport, r.handler.ImportPolicy)
+
+	r.auth.Handle(group, fiber.MethodGet, "/permissions", permPermissionRead, r.handler.ListPermissions)
+	r.auth.Handle(group, fiber.MethodGet, "/permissions/catalog", permPermissionRead, r.handler.GetPermissionCatalog)
+	r.auth.Handle(group, fiber.MethodGet, "/permissions/:id", permPermissionRead, r.handler.GetPermission)
+	r.auth.Handle(group, fiber.MethodPost, "/permissions", permPermissionCreate, r.handler.CreatePermission)
+	r.auth.Handle(group, fiber.MethodPut, "/permissions/:id", permPermissionUpdate, r.handler.UpdatePermission)
+	r.auth.Handle(group, fiber.MethodDelete, "/permissions/:id", permPermissionDelete, r.handler.DeletePermission)
 }
@@ -20,6 +20,7 @@ type RoleResponse struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
+	IsSystem    bool   `json:"is_system"`
 	CreatedAt   string `json:"created_at"`
 }
 
@@ -27,6 +28,7 @@ type PermissionResponse struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
+	IsSystem    bool   `json:"is_system"`
 	CreatedAt   string `json:"created_at"`
 }
 
@@ -35,6 +37,73 @@ type RolePermissionsResponse struct {
 	Permissions []PermissionResponse `json:"permissions"`
 }
 
+type RoleParentsRequest struct {
+	ParentIDs []string `json:"parent_ids"`
+}
+
+type RoleParentsResponse struct {
+	RoleID  string         `json:"role_id"`
+	Parents []RoleResponse `json:"parents"`
+}
+
+type RoleConstraintsRequest struct {
+	RequiresApproval bool     `json:"requires_approval"`
+	ExcludedRoleIDs  []string `json:"excluded_role_ids"`
+}
+
+type RoleConstraintsResponse struct {
+	RoleID           string         `json:"role_id"`
+	RequiresApproval bool           `json:"requires_approval"`
+	ExcludedRoles    []RoleResponse `json:"excluded_roles"`
+}
+
+type ResourceRoleRequest struct {
+	RoleID       string `json:"role_id" validate:"required,notblank"`
+	ResourceType string `json:"resource_type" validate:"required,notblank"`
+	ResourceID   string `json:"resource_id" validate:"required,notblank"`
+}
+
+type ResourceRoleResponse struct {
+	Role         RoleResponse `json:"role"`
+	ResourceType string       `json:"resource_type"`
+	ResourceID   string       `json:"resource_id"`
+	CreatedAt    string       `json:"created_at"`
+}
+
+type UserResourceRolesResponse struct {
+	UserID string                 `json:"user_id"`
+	Roles  []ResourceRoleResponse `json:"roles"`
+}
+
+type CatalogRouteResponse struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+type CatalogPermissionResponse struct {
+	Name   string                 `json:"name"`
+	Stored bool                   `json:"stored"`
+	Routes []CatalogRouteResponse `json:"routes"`
+}
+
+type PermissionCatalogResponse struct {
+	Permissions []CatalogPermissionResponse `json:"permissions"`
+	Orphaned    []string                    `json:"orphaned"`
+}
+
+type PolicyChangeResponse struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Target string `json:"target,omitempty"`
+}
+
+type PolicyImportResponse struct {
+	DryRun  bool                   `json:"dry_run"`
+	Applied bool                   `json:"applied"`
+	Changes []PolicyChangeResponse `json:"changes"`
+}
+
 type RoleListResponse struct {
 	Items []RoleResponse `json:"items"`
 	Meta  response.PageMeta `json:"meta"`
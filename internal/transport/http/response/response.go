@@ -1,7 +1,16 @@
 package response
 
 type Response struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Data    interface{}  `json:"data,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError is one problem with a request field. Code is a stable,
+// machine-readable identifier such as "required" or "too_short".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
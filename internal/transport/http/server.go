@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/mzulfanw/boilerplate-go-fiber/infrastructure/metrics"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/config"
 	"github.com/sirupsen/logrus"
 )
@@ -13,7 +14,7 @@ type Server struct {
 	addr string
 }
 
-func NewServer(cfg config.Config, routers ...Router) *Server {
+func NewServer(cfg config.Config, registry *metrics.Registry, routers ...Router) *Server {
 	app := fiber.New(fiber.Config{
 		IdleTimeout:  cfg.HTTPIdleTimeout,
 		ReadTimeout:  cfg.HTTPReadTimeout,
@@ -21,7 +22,7 @@ func NewServer(cfg config.Config, routers ...Router) *Server {
 		ErrorHandler: errorHandler(),
 	})
 
-	setupMiddlewares(app, cfg)
+	setupMiddlewares(app, cfg, registry)
 	for _, r := range routers {
 		if r == nil {
 			continue
@@ -1,25 +1,42 @@
 package user
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
 	rbacdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/rbac"
 	userdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/user"
+	authusecase "github.com/mzulfanw/boilerplate-go-fiber/internal/service/auth"
 	userusecase "github.com/mzulfanw/boilerplate-go-fiber/internal/service/user"
+	httptransport "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/query"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/response"
 	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/validation"
+	"github.com/sirupsen/logrus"
 )
 
+// EmailVerifier sends a new verification link after a user's email changed.
+type EmailVerifier interface {
+	SendEmailVerification(ctx context.Context, userID string) error
+}
+
 type Handler struct {
-	service *userusecase.Service
+	service     *userusecase.Service
+	authService *authusecase.Service
+	verifier    EmailVerifier
+}
+
+func NewHandler(service *userusecase.Service, authService *authusecase.Service) *Handler {
+	return &Handler{service: service, authService: authService}
 }
 
-func NewHandler(service *userusecase.Service) *Handler {
-	return &Handler{service: service}
+// SetEmailVerifier sends a verification link when an email is changed.
+func (h *Handler) SetEmailVerifier(verifier EmailVerifier) {
+	h.verifier = verifier
 }
 
 // ListUsers godoc
@@ -128,6 +145,7 @@ func (h *Handler) CreateUser(c *fiber.Ctx) error {
 
 // UpdateUser godoc
 // @Summary Update user
+// @Description Users without user.update may change the email of their own account after confirming current_password. A changed email must be verified again.
 // @Tags Users
 // @Security BearerAuth
 // @Accept json
@@ -136,7 +154,10 @@ func (h *Handler) CreateUser(c *fiber.Ctx) error {
 // @Param payload body UpdateUserRequest true "Update user payload"
 // @Success 200 {object} response.Response{data=UserResponse}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 423 {object} response.Response
 // @Router /users/{id} [put]
 func (h *Handler) UpdateUser(c *fiber.Ctx) error {
 	userID, err := validation.RequireParam(c.Params("id"), "user id")
@@ -148,6 +169,23 @@ func (h *Handler) UpdateUser(c *fiber.Ctx) error {
 	if err := validation.ParseAndValidate(c, &req); err != nil {
 		return err
 	}
+	// Users without user.update reach this handler only for their own
+	// account, and may change nothing but their email, after confirming
+	// their password so a stolen access token cannot move the account.
+	if auth, _ := httptransport.GetAuthContext(c); !auth.Can(permUserUpdate) {
+		if req.Password != nil || req.IsActive != nil {
+			return fiber.NewError(fiber.StatusForbidden, "only email can be changed on your own account")
+		}
+		if req.CurrentPassword == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "current_password is required")
+		}
+		if h.authService == nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "auth service is not configured")
+		}
+		if err := h.authService.VerifyPassword(c.UserContext(), userID, req.CurrentPassword); err != nil {
+			return mapUserError(err)
+		}
+	}
 	if req.Email != nil {
 		value := strings.TrimSpace(*req.Email)
 		req.Email = &value
@@ -157,6 +195,13 @@ func (h *Handler) UpdateUser(c *fiber.Ctx) error {
 	if err != nil {
 		return mapUserError(err)
 	}
+	// The update is already saved, so a failed verification email is logged
+	// rather than failing the request; the user can ask for another one.
+	if req.Email != nil && h.verifier != nil {
+		if err := h.verifier.SendEmailVerification(c.UserContext(), user.ID); err != nil {
+			logrus.WithError(err).WithField("user_id", user.ID).Warn("email verification after email change failed")
+		}
+	}
 
 	resp := response.Response{
 		Code:    fiber.StatusOK,
@@ -175,6 +220,7 @@ func (h *Handler) UpdateUser(c *fiber.Ctx) error {
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /users/{id} [delete]
 func (h *Handler) DeleteUser(c *fiber.Ctx) error {
 	userID, err := validation.RequireParam(c.Params("id"), "user id")
@@ -193,6 +239,151 @@ func (h *Handler) DeleteUser(c *fiber.Ctx) error {
 	return c.Status(resp.Code).JSON(resp)
 }
 
+// ResetUserMFA godoc
+// @Summary Reset user two-factor authentication
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /users/{id}/mfa [delete]
+func (h *Handler) ResetUserMFA(c *fiber.Ctx) error {
+	userID, err := validation.RequireParam(c.Params("id"), "user id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.ResetMFA(c.UserContext(), userID); err != nil {
+		return mapUserError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// ListUserSessions godoc
+// @Summary List user sessions
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=[]SessionResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /users/{id}/sessions [get]
+func (h *Handler) ListUserSessions(c *fiber.Ctx) error {
+	userID, err := h.requireExistingUser(c)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := h.authService.ListSessions(c.UserContext(), userID, "")
+	if err != nil {
+		return mapSessionError(err)
+	}
+
+	data := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, SessionResponse{
+			ID:         session.ID,
+			StartedAt:  session.StartedAt.UTC().Format(time.RFC3339),
+			LastUsedAt: session.LastUsedAt.UTC().Format(time.RFC3339),
+			ExpiresAt:  session.ExpiresAt.UTC().Format(time.RFC3339),
+			IPAddress:  session.IPAddress,
+			UserAgent:  session.UserAgent,
+		})
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data:    data,
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// RevokeUserSession godoc
+// @Summary Revoke a user session
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Param sessionId path string true "Session ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /users/{id}/sessions/{sessionId} [delete]
+func (h *Handler) RevokeUserSession(c *fiber.Ctx) error {
+	userID, err := h.requireExistingUser(c)
+	if err != nil {
+		return err
+	}
+	sessionID, err := validation.RequireParam(c.Params("sessionId"), "session id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.authService.RevokeSession(c.UserContext(), userID, sessionID); err != nil {
+		return mapSessionError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// RevokeUserSessions godoc
+// @Summary Revoke all user sessions
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=RevokeSessionsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /users/{id}/sessions [delete]
+func (h *Handler) RevokeUserSessions(c *fiber.Ctx) error {
+	userID, err := h.requireExistingUser(c)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := h.authService.RevokeOtherSessions(c.UserContext(), userID, "")
+	if err != nil {
+		return mapSessionError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: RevokeSessionsResponse{
+			Revoked: revoked,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+func (h *Handler) requireExistingUser(c *fiber.Ctx) (string, error) {
+	if h.authService == nil {
+		return "", fiber.NewError(fiber.StatusInternalServerError, "auth service is not configured")
+	}
+	userID, err := validation.RequireParam(c.Params("id"), "user id")
+	if err != nil {
+		return "", err
+	}
+	if _, err := h.service.GetUser(c.UserContext(), userID); err != nil {
+		return "", mapUserError(err)
+	}
+	return userID, nil
+}
+
 // ListUserRoles godoc
 // @Summary List user roles
 // @Tags Users
@@ -227,6 +418,7 @@ func (h *Handler) ListUserRoles(c *fiber.Ctx) error {
 
 // UpdateUserRoles godoc
 // @Summary Replace user roles
+// @Description Sets the user's global roles. Sets containing mutually exclusive roles are rejected. With role approval enabled, roles that require approval are not assigned yet; they are returned as pending_requests.
 // @Tags Users
 // @Security BearerAuth
 // @Accept json
@@ -236,6 +428,7 @@ func (h *Handler) ListUserRoles(c *fiber.Ctx) error {
 // @Success 200 {object} response.Response{data=UserRolesResponse}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /users/{id}/roles [put]
 func (h *Handler) UpdateUserRoles(c *fiber.Ctx) error {
 	userID, err := validation.RequireParam(c.Params("id"), "user id")
@@ -248,9 +441,138 @@ func (h *Handler) UpdateUserRoles(c *fiber.Ctx) error {
 		return err
 	}
 
-	if err := h.service.ReplaceUserRoles(c.UserContext(), userID, req.RoleIDs); err != nil {
+	pending, err := h.service.ReplaceUserRoles(c.UserContext(), userID, req.RoleIDs)
+	if err != nil {
+		return mapUserError(err)
+	}
+
+	roles, err := h.service.ListUserRoles(c.UserContext(), userID)
+	if err != nil {
+		return mapUserError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: UserRolesResponse{
+			UserID:          userID,
+			Roles:           mapRoles(roles),
+			PendingRequests: mapRoleGrantRequests(pending),
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// GetEffectivePermissions godoc
+// @Summary Get effective permissions
+// @Description Roles currently in effect, every permission they grant including inherited ones, and resource-scoped grants, as the next issued token would carry them.
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=EffectivePermissionsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /users/{id}/effective-permissions [get]
+func (h *Handler) GetEffectivePermissions(c *fiber.Ctx) error {
+	userID, err := h.requireExistingUser(c)
+	if err != nil {
+		return err
+	}
+
+	access, err := h.authService.EffectiveAccess(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, authusecase.ErrUserNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "user not found")
+		}
+		return err
+	}
+
+	grants := make([]ResourceGrantResponse, 0, len(access.Grants))
+	for _, grant := range access.Grants {
+		grants = append(grants, ResourceGrantResponse{
+			ResourceType: grant.ResourceType,
+			ResourceID:   grant.ResourceID,
+			Permissions:  grant.Permissions,
+		})
+	}
+	roles := access.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	permissions := access.Permissions
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data: EffectivePermissionsResponse{
+			UserID:      userID,
+			Roles:       roles,
+			Permissions: permissions,
+			Grants:      grants,
+		},
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// GrantUserRole godoc
+// @Summary Grant user role
+// @Description Adds one role, optionally limited to a time window. Set valid_until, or duration (e.g. "4h") counted from valid_from or now; omit both for a permanent grant. Lapsed grants are removed automatically and the user's access tokens are invalidated. With role approval enabled, a role that requires approval is not assigned; a pending request is returned with status 202 instead.
+// @Tags Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param payload body GrantUserRoleRequest true "Role grant"
+// @Success 200 {object} response.Response{data=UserRolesResponse}
+// @Success 202 {object} response.Response{data=RoleGrantRequestResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /users/{id}/roles [post]
+func (h *Handler) GrantUserRole(c *fiber.Ctx) error {
+	userID, err := validation.RequireParam(c.Params("id"), "user id")
+	if err != nil {
+		return err
+	}
+
+	var req GrantUserRoleRequest
+	if err := validation.ParseAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	validUntil := req.ValidUntil
+	if duration := strings.TrimSpace(req.Duration); duration != "" {
+		if validUntil != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "use either valid_until or duration")
+		}
+		parsed, err := time.ParseDuration(duration)
+		if err != nil || parsed <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid duration")
+		}
+		start := time.Now()
+		if req.ValidFrom != nil {
+			start = *req.ValidFrom
+		}
+		until := start.Add(parsed)
+		validUntil = &until
+	}
+
+	request, err := h.service.GrantUserRole(c.UserContext(), userID, req.RoleID, req.ValidFrom, validUntil)
+	if err != nil {
 		return mapUserError(err)
 	}
+	if request != nil {
+		resp := response.Response{
+			Code:    fiber.StatusAccepted,
+			Message: "approval required",
+			Data:    mapRoleGrantRequest(*request),
+		}
+		return c.Status(resp.Code).JSON(resp)
+	}
 
 	roles, err := h.service.ListUserRoles(c.UserContext(), userID)
 	if err != nil {
@@ -268,7 +590,98 @@ func (h *Handler) UpdateUserRoles(c *fiber.Ctx) error {
 	return c.Status(resp.Code).JSON(resp)
 }
 
+// ListRoleGrantRequests godoc
+// @Summary List role grant requests
+// @Description Grants of roles that require approval, newest first. Status defaults to pending; use "all" for every request.
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "pending, approved, rejected or all"
+// @Success 200 {object} response.Response{data=[]RoleGrantRequestResponse}
+// @Failure 400 {object} response.Response
+// @Router /users/role-requests [get]
+func (h *Handler) ListRoleGrantRequests(c *fiber.Ctx) error {
+	requests, err := h.service.ListRoleGrantRequests(c.UserContext(), c.Query("status"))
+	if err != nil {
+		return mapUserError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data:    mapRoleGrantRequests(requests),
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// ApproveRoleGrantRequest godoc
+// @Summary Approve role grant request
+// @Description Assigns the requested role. The approver must be a user other than the requester and the grantee.
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param requestId path string true "Request ID"
+// @Success 200 {object} response.Response{data=RoleGrantRequestResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /users/role-requests/{requestId}/approve [post]
+func (h *Handler) ApproveRoleGrantRequest(c *fiber.Ctx) error {
+	requestID, err := validation.RequireParam(c.Params("requestId"), "request id")
+	if err != nil {
+		return err
+	}
+
+	request, err := h.service.ApproveRoleGrantRequest(c.UserContext(), requestID)
+	if err != nil {
+		return mapUserError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data:    mapRoleGrantRequest(request),
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
+// RejectRoleGrantRequest godoc
+// @Summary Reject role grant request
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param requestId path string true "Request ID"
+// @Success 200 {object} response.Response{data=RoleGrantRequestResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /users/role-requests/{requestId}/reject [post]
+func (h *Handler) RejectRoleGrantRequest(c *fiber.Ctx) error {
+	requestID, err := validation.RequireParam(c.Params("requestId"), "request id")
+	if err != nil {
+		return err
+	}
+
+	request, err := h.service.RejectRoleGrantRequest(c.UserContext(), requestID)
+	if err != nil {
+		return mapUserError(err)
+	}
+
+	resp := response.Response{
+		Code:    fiber.StatusOK,
+		Message: "ok",
+		Data:    mapRoleGrantRequest(request),
+	}
+	return c.Status(resp.Code).JSON(resp)
+}
+
 func mapUserError(err error) error {
+	var policyErr *authdomain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return validation.PasswordPolicyError("password", policyErr)
+	}
 	switch {
 	case errors.Is(err, userdomain.ErrInvalidInput):
 		return fiber.NewError(fiber.StatusBadRequest, "invalid input")
@@ -276,11 +689,38 @@ func mapUserError(err error) error {
 		return fiber.NewError(fiber.StatusNotFound, "user not found")
 	case errors.Is(err, userdomain.ErrConflict):
 		return fiber.NewError(fiber.StatusConflict, "user already exists")
+	case errors.Is(err, userdomain.ErrLastAdmin):
+		return fiber.NewError(fiber.StatusConflict, "change would leave no active admin")
+	case errors.Is(err, userdomain.ErrExclusiveRoles):
+		return fiber.NewError(fiber.StatusConflict, "roles are mutually exclusive")
+	case errors.Is(err, userdomain.ErrApprovalRequired):
+		return fiber.NewError(fiber.StatusConflict, "role requires approval")
+	case errors.Is(err, userdomain.ErrSelfApproval):
+		return fiber.NewError(fiber.StatusForbidden, "request must be decided by another user")
+	case errors.Is(err, userdomain.ErrRequestNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "role request not found")
+	case errors.Is(err, userdomain.ErrRequestNotPending):
+		return fiber.NewError(fiber.StatusConflict, "role request already decided")
+	case errors.Is(err, authusecase.ErrInvalidCurrentPassword):
+		return fiber.NewError(fiber.StatusBadRequest, "current password is incorrect")
+	case errors.Is(err, authusecase.ErrUserLocked):
+		return fiber.NewError(fiber.StatusLocked, "user is locked")
+	case errors.Is(err, authusecase.ErrUserDisabled):
+		return fiber.NewError(fiber.StatusForbidden, "user is disabled")
+	case errors.Is(err, authusecase.ErrUserNotFound):
+		return fiber.NewError(fiber.StatusNotFound, "user not found")
 	default:
 		return err
 	}
 }
 
+func mapSessionError(err error) error {
+	if errors.Is(err, authusecase.ErrSessionNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "session not found")
+	}
+	return err
+}
+
 func mapUsers(users []userdomain.User) []UserResponse {
 	result := make([]UserResponse, 0, len(users))
 	for _, user := range users {
@@ -299,7 +739,7 @@ func mapUser(user userdomain.User) UserResponse {
 	}
 }
 
-func mapRoles(roles []rbacdomain.Role) []RoleResponse {
+func mapRoles(roles []rbacdomain.UserRole) []RoleResponse {
 	result := make([]RoleResponse, 0, len(roles))
 	for _, role := range roles {
 		result = append(result, mapRole(role))
@@ -307,11 +747,49 @@ func mapRoles(roles []rbacdomain.Role) []RoleResponse {
 	return result
 }
 
-func mapRole(role rbacdomain.Role) RoleResponse {
-	return RoleResponse{
-		ID:          role.ID,
-		Name:        role.Name,
-		Description: role.Description,
-		CreatedAt:   role.CreatedAt.UTC().Format(time.RFC3339),
+func mapRole(assignment rbacdomain.UserRole) RoleResponse {
+	role := RoleResponse{
+		ID:          assignment.Role.ID,
+		Name:        assignment.Role.Name,
+		Description: assignment.Role.Description,
+		CreatedAt:   assignment.Role.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if assignment.ValidFrom != nil {
+		role.ValidFrom = assignment.ValidFrom.UTC().Format(time.RFC3339)
+	}
+	if assignment.ValidUntil != nil {
+		role.ValidUntil = assignment.ValidUntil.UTC().Format(time.RFC3339)
+	}
+	return role
+}
+
+func mapRoleGrantRequests(requests []userdomain.RoleGrantRequest) []RoleGrantRequestResponse {
+	result := make([]RoleGrantRequestResponse, 0, len(requests))
+	for _, request := range requests {
+		result = append(result, mapRoleGrantRequest(request))
+	}
+	return result
+}
+
+func mapRoleGrantRequest(request userdomain.RoleGrantRequest) RoleGrantRequestResponse {
+	resp := RoleGrantRequestResponse{
+		ID:          request.ID,
+		UserID:      request.UserID,
+		RoleID:      request.RoleID,
+		RoleName:    request.RoleName,
+		RequestedBy: request.RequestedBy,
+		Status:      request.Status,
+		DecidedBy:   request.DecidedBy,
+		CreatedAt:   request.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if request.ValidFrom != nil {
+		resp.ValidFrom = request.ValidFrom.UTC().Format(time.RFC3339)
+	}
+	if request.ValidUntil != nil {
+		resp.ValidUntil = request.ValidUntil.UTC().Format(time.RFC3339)
+	}
+	if request.DecidedAt != nil {
+		resp.DecidedAt = request.DecidedAt.UTC().Format(time.RFC3339)
 	}
+	return resp
 }
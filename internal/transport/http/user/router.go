@@ -6,12 +6,16 @@ import (
 )
 
 const (
-	permUserRead       = "user.read"
-	permUserCreate     = "user.create"
-	permUserUpdate     = "user.update"
-	permUserDelete     = "user.delete"
-	permUserRoleRead   = "user.role.read"
-	permUserRoleUpdate = "user.role.update"
+	permUserRead        = "user.read"
+	permUserCreate      = "user.create"
+	permUserUpdate      = "user.update"
+	permUserDelete      = "user.delete"
+	permUserRoleRead    = "user.role.read"
+	permUserRoleUpdate  = "user.role.update"
+	permUserRoleApprove = "user.role.approve"
+	permUserMFAReset    = "user.mfa.reset"
+	permSessionRead     = "user.session.read"
+	permSessionRevoke   = "user.session.revoke"
 )
 
 type Router struct {
@@ -29,11 +33,20 @@ func (r *Router) Register(app *fiber.App) {
 	}
 
 	group := app.Group("/users", r.auth.RequireAuth())
-	group.Get("/", r.auth.RequirePermissions(permUserRead), r.handler.ListUsers)
-	group.Get("/:id", r.auth.RequirePermissions(permUserRead), r.handler.GetUser)
-	group.Post("/", r.auth.RequirePermissions(permUserCreate), r.handler.CreateUser)
-	group.Put("/:id", r.auth.RequirePermissions(permUserUpdate), r.handler.UpdateUser)
-	group.Delete("/:id", r.auth.RequirePermissions(permUserDelete), r.handler.DeleteUser)
-	group.Get("/:id/roles", r.auth.RequirePermissions(permUserRoleRead), r.handler.ListUserRoles)
-	group.Put("/:id/roles", r.auth.RequirePermissions(permUserRoleUpdate), r.handler.UpdateUserRoles)
+	r.auth.Handle(group, fiber.MethodGet, "/", permUserRead, r.handler.ListUsers)
+	r.auth.Handle(group, fiber.MethodGet, "/role-requests", permUserRoleRead, r.handler.ListRoleGrantRequests)
+	r.auth.Handle(group, fiber.MethodPost, "/role-requests/:requestId/approve", permUserRoleApprove, r.handler.ApproveRoleGrantRequest)
+	r.auth.Handle(group, fiber.MethodPost, "/role-requests/:requestId/reject", permUserRoleApprove, r.handler.RejectRoleGrantRequest)
+	r.auth.HandlePolicy(group, fiber.MethodGet, "/:id", permUserRead, httptransport.Or(httptransport.AnyPermission(permUserRead), httptransport.Self("id")), r.handler.GetUser)
+	r.auth.Handle(group, fiber.MethodPost, "/", permUserCreate, r.handler.CreateUser)
+	r.auth.HandlePolicy(group, fiber.MethodPut, "/:id", permUserUpdate, httptransport.Or(httptransport.AnyPermission(permUserUpdate), httptransport.Self("id")), r.handler.UpdateUser)
+	r.auth.Handle(group, fiber.MethodDelete, "/:id", permUserDelete, r.handler.DeleteUser)
+	r.auth.Handle(group, fiber.MethodGet, "/:id/roles", permUserRoleRead, r.handler.ListUserRoles)
+	r.auth.Handle(group, fiber.MethodPut, "/:id/roles", permUserRoleUpdate, r.handler.UpdateUserRoles)
+	r.auth.Handle(group, fiber.MethodPost, "/:id/roles", permUserRoleUpdate, r.handler.GrantUserRole)
+	r.auth.Handle(group, fiber.MethodGet, "/:id/effective-permissions", permUserRoleRead, r.handler.GetEffectivePermissions)
+	r.auth.Handle(group, fiber.MethodDelete, "/:id/mfa", permUserMFAReset, r.handler.ResetUserMFA)
+	r.auth.Handle(group, fiber.MethodGet, "/:id/sessions", permSessionRead, r.handler.ListUserSessions)
+	r.auth.Handle(group, fiber.MethodDelete, "/:id/sessions", permSessionRevoke, r.handler.RevokeUserSessions)
+	r.auth.Handle(group, fiber.MethodDelete, "/:id/sessions/:sessionId", permSessionRevoke, r.handler.RevokeUserSession)
 }
@@ -1,6 +1,10 @@
 package user
 
-import "github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/response"
+import (
+	"time"
+
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/response"
+)
 
 type CreateUserRequest struct {
 	Email    string   `json:"email" validate:"required,notblank"`
@@ -13,6 +17,8 @@ type UpdateUserRequest struct {
 	Email    *string `json:"email" validate:"required_without_all=Password IsActive,notblank"`
 	Password *string `json:"password" validate:"required_without_all=Email IsActive,notblank"`
 	IsActive *bool   `json:"is_active" validate:"required_without_all=Email Password"`
+	// CurrentPassword is required when users change their own email.
+	CurrentPassword string `json:"current_password"`
 }
 
 type UserResponse struct {
@@ -32,14 +38,64 @@ type UserRolesRequest struct {
 	RoleIDs []string `json:"role_ids"`
 }
 
+type GrantUserRoleRequest struct {
+	RoleID     string     `json:"role_id" validate:"required,notblank"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+	Duration   string     `json:"duration,omitempty"`
+}
+
 type RoleResponse struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
 	CreatedAt   string `json:"created_at"`
+	ValidFrom   string `json:"valid_from,omitempty"`
+	ValidUntil  string `json:"valid_until,omitempty"`
 }
 
 type UserRolesResponse struct {
-	UserID string         `json:"user_id"`
-	Roles  []RoleResponse `json:"roles"`
+	UserID          string                     `json:"user_id"`
+	Roles           []RoleResponse             `json:"roles"`
+	PendingRequests []RoleGrantRequestResponse `json:"pending_requests,omitempty"`
+}
+
+type RoleGrantRequestResponse struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	RoleID      string `json:"role_id"`
+	RoleName    string `json:"role_name"`
+	ValidFrom   string `json:"valid_from,omitempty"`
+	ValidUntil  string `json:"valid_until,omitempty"`
+	RequestedBy string `json:"requested_by,omitempty"`
+	Status      string `json:"status"`
+	DecidedBy   string `json:"decided_by,omitempty"`
+	DecidedAt   string `json:"decided_at,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type ResourceGrantResponse struct {
+	ResourceType string   `json:"resource_type"`
+	ResourceID   string   `json:"resource_id"`
+	Permissions  []string `json:"permissions"`
+}
+
+type EffectivePermissionsResponse struct {
+	UserID      string                  `json:"user_id"`
+	Roles       []string                `json:"roles"`
+	Permissions []string                `json:"permissions"`
+	Grants      []ResourceGrantResponse `json:"grants"`
+}
+
+type SessionResponse struct {
+	ID         string `json:"id"`
+	StartedAt  string `json:"started_at"`
+	LastUsedAt string `json:"last_used_at"`
+	ExpiresAt  string `json:"expires_at"`
+	IPAddress  string `json:"ip_address,omitempty"`
+	UserAgent  string `json:"user_agent,omitempty"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
 }
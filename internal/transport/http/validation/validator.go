@@ -6,8 +6,40 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	authdomain "github.com/mzulfanw/boilerplate-go-fiber/internal/domain/auth"
+	"github.com/mzulfanw/boilerplate-go-fiber/internal/transport/http/response"
 )
 
+// Error is a 400 response that lists every invalid field. Message repeats
+// the first problem for clients that only read it.
+type Error struct {
+	Message string
+	Fields  []response.FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap lets code that inspects *fiber.Error, such as request metrics, see
+// the 400 status.
+func (e *Error) Unwrap() error {
+	return fiber.NewError(fiber.StatusBadRequest, e.Message)
+}
+
+// PasswordPolicyError reports the rules a password failed as errors on field.
+func PasswordPolicyError(field string, err *authdomain.PasswordPolicyError) *Error {
+	fields := make([]response.FieldError, 0, len(err.Violations))
+	for _, violation := range err.Violations {
+		fields = append(fields, response.FieldError{Field: field, Code: violation.Code, Message: violation.Message})
+	}
+	message := "password does not meet policy"
+	if len(fields) > 0 {
+		message = fields[0].Message
+	}
+	return &Error{Message: message, Fields: fields}
+}
+
 var validatorInstance = newValidator()
 
 func newValidator() *validator.Validate {
@@ -79,21 +111,32 @@ func mapValidationError(err error) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid request")
 	}
 
-	fieldError := validationErrors[0]
+	fields := make([]response.FieldError, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		fields = append(fields, response.FieldError{
+			Field:   fieldError.Field(),
+			Code:    fieldError.Tag(),
+			Message: fieldErrorMessage(fieldError),
+		})
+	}
+	return &Error{Message: fields[0].Message, Fields: fields}
+}
+
+func fieldErrorMessage(fieldError validator.FieldError) string {
 	field := fieldError.Field()
 
 	switch fieldError.Tag() {
 	case "required":
-		return fiber.NewError(fiber.StatusBadRequest, field+" is required")
+		return field + " is required"
 	case "notblank":
-		return fiber.NewError(fiber.StatusBadRequest, field+" cannot be empty")
+		return field + " cannot be empty"
 	case "email":
-		return fiber.NewError(fiber.StatusBadRequest, field+" must be a valid email")
+		return field + " must be a valid email"
 	case "min":
-		return fiber.NewError(fiber.StatusBadRequest, field+" is too short")
+		return field + " is too short"
 	case "required_without_all":
-		return fiber.NewError(fiber.StatusBadRequest, "no fields to update")
+		return "no fields to update"
 	default:
-		return fiber.NewError(fiber.StatusBadRequest, "invalid request")
+		return "invalid request"
 	}
 }
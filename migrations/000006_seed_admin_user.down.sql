@@ -1,5 +1 @@
-DELETE FROM user_roles
-WHERE user_id = (SELECT id FROM users WHERE email = 'admin@boiler.com');
-
-DELETE FROM users
-WHERE email = 'admin@boiler.com';
+-- Nothing to undo: the up migration no longer seeds an account.
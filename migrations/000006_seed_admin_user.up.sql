@@ -1,11 +1,4 @@
--- Seed system admin user
-INSERT INTO users (email, password_hash, is_active)
-VALUES ('admin@boiler.com', crypt('abcd5dasar', gen_salt('bf', 12)), true)
-ON CONFLICT (email) DO NOTHING;
-
-INSERT INTO user_roles (user_id, role_id)
-SELECT u.id, r.id
-FROM users u
-JOIN roles r ON r.name = 'admin'
-WHERE u.email = 'admin@boiler.com'
-ON CONFLICT DO NOTHING;
+-- This migration used to seed admin@boiler.com with a published password.
+-- It is kept as a no-op so version numbers stay stable; create the first
+-- admin with the `create-admin` or `seed` command instead. Databases that
+-- already ran the old version are cleaned up by 000023.
@@ -0,0 +1,3 @@
+-- Revert email verification
+ALTER TABLE users
+  DROP COLUMN IF EXISTS email_verified_at;
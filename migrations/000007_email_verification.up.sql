@@ -0,0 +1,8 @@
+-- Email verification for self-service registration
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS email_verified_at timestamptz;
+
+-- Accounts created before verification existed are treated as verified
+UPDATE users
+SET email_verified_at = created_at
+WHERE email_verified_at IS NULL;
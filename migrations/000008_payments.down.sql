@@ -0,0 +1,3 @@
+-- Reverse payment invoices + status transition history
+DROP TABLE IF EXISTS invoice_status_transitions;
+DROP TABLE IF EXISTS invoices;
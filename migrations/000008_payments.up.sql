@@ -0,0 +1,36 @@
+-- Payment invoices + status transition history
+CREATE TABLE IF NOT EXISTS invoices (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  xendit_invoice_id text UNIQUE NOT NULL,
+  external_id text NOT NULL,
+  amount numeric(20, 2) NOT NULL,
+  paid_amount numeric(20, 2),
+  currency text,
+  status text NOT NULL CHECK (status IN ('PENDING', 'PAID', 'SETTLED', 'EXPIRED')),
+  payer_email text,
+  description text,
+  invoice_url text,
+  payment_method text,
+  payment_channel text,
+  expires_at timestamptz,
+  paid_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS invoice_status_transitions (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  invoice_id uuid NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+  from_status text,
+  to_status text NOT NULL,
+  source text NOT NULL,
+  payload_hash char(64),
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_external_id ON invoices(external_id);
+CREATE INDEX IF NOT EXISTS idx_invoices_status ON invoices(status);
+CREATE INDEX IF NOT EXISTS idx_invoice_status_transitions_invoice_id ON invoice_status_transitions(invoice_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_invoice_status_transitions_payload
+  ON invoice_status_transitions(invoice_id, payload_hash)
+  WHERE payload_hash IS NOT NULL;
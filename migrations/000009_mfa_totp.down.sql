@@ -0,0 +1,17 @@
+-- Remove TOTP two-factor authentication
+DELETE FROM role_permissions
+WHERE permission_id IN (
+  SELECT id
+  FROM permissions
+  WHERE name = 'user.mfa.reset'
+);
+
+DELETE FROM permissions
+WHERE name = 'user.mfa.reset';
+
+DROP TABLE IF EXISTS mfa_recovery_codes;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS mfa_last_used_step,
+  DROP COLUMN IF EXISTS mfa_enabled_at,
+  DROP COLUMN IF EXISTS mfa_totp_secret;
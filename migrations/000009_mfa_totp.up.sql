@@ -0,0 +1,28 @@
+-- TOTP two-factor authentication
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS mfa_totp_secret text,
+  ADD COLUMN IF NOT EXISTS mfa_enabled_at timestamptz,
+  ADD COLUMN IF NOT EXISTS mfa_last_used_step bigint;
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  code_hash char(64) NOT NULL,
+  used_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  UNIQUE (user_id, code_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+
+INSERT INTO permissions (name, description)
+VALUES
+  ('user.mfa.reset', 'Reset user two-factor authentication')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'user.mfa.reset'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,15 @@
+-- Remove refresh token sessions
+DELETE FROM role_permissions
+WHERE permission_id IN (
+  SELECT id
+  FROM permissions
+  WHERE name IN ('user.session.read', 'user.session.revoke')
+);
+
+DELETE FROM permissions
+WHERE name IN ('user.session.read', 'user.session.revoke');
+
+DROP INDEX IF EXISTS idx_refresh_tokens_session_id;
+
+ALTER TABLE refresh_tokens
+  DROP COLUMN IF EXISTS session_id;
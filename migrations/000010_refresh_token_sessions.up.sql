@@ -0,0 +1,49 @@
+-- Group refresh token rotation chains into sessions
+ALTER TABLE refresh_tokens
+  ADD COLUMN IF NOT EXISTS session_id uuid;
+
+-- Every token in a chain shares the id of the chain's first token
+WITH RECURSIVE chain AS (
+  SELECT t.id AS session_id, t.token_hash, t.replaced_by_hash
+  FROM refresh_tokens t
+  WHERE NOT EXISTS (
+    SELECT 1
+    FROM refresh_tokens p
+    WHERE p.replaced_by_hash = t.token_hash
+  )
+  UNION ALL
+  SELECT c.session_id, n.token_hash, n.replaced_by_hash
+  FROM chain c
+  JOIN refresh_tokens n ON n.token_hash = c.replaced_by_hash
+)
+UPDATE refresh_tokens t
+SET session_id = c.session_id
+FROM chain c
+WHERE t.token_hash = c.token_hash
+  AND t.session_id IS NULL;
+
+UPDATE refresh_tokens
+SET session_id = id
+WHERE session_id IS NULL;
+
+ALTER TABLE refresh_tokens
+  ALTER COLUMN session_id SET DEFAULT gen_random_uuid(),
+  ALTER COLUMN session_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+
+INSERT INTO permissions (name, description)
+VALUES
+  ('user.session.read', 'Read user sessions'),
+  ('user.session.revoke', 'Revoke user sessions')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name IN (
+  'user.session.read',
+  'user.session.revoke'
+)
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+-- Remove audit log
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE name = 'audit.read');
+
+DELETE FROM permissions
+WHERE name = 'audit.read';
+
+DROP INDEX IF EXISTS idx_audit_logs_target;
+DROP INDEX IF EXISTS idx_audit_logs_actor_user_id;
+DROP INDEX IF EXISTS idx_audit_logs_created_at;
+DROP TABLE IF EXISTS audit_logs;
@@ -0,0 +1,29 @@
+-- Audit log for security-sensitive actions
+CREATE TABLE IF NOT EXISTS audit_logs (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  actor_user_id uuid REFERENCES users(id) ON DELETE SET NULL,
+  actor_api_key_id uuid REFERENCES api_keys(id) ON DELETE SET NULL,
+  action text NOT NULL,
+  target_type text NOT NULL,
+  target_id text NOT NULL,
+  changes jsonb NOT NULL DEFAULT '{}',
+  ip text,
+  user_agent text,
+  request_id text,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_user_id ON audit_logs(actor_user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target ON audit_logs(target_type, target_id);
+
+INSERT INTO permissions (name, description)
+VALUES ('audit.read', 'Read audit logs')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = 'audit.read'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+-- Remove role hierarchy
+DELETE FROM role_permissions
+WHERE permission_id IN (
+  SELECT id FROM permissions WHERE name IN ('role.parent.read', 'role.parent.update')
+);
+
+DELETE FROM permissions
+WHERE name IN ('role.parent.read', 'role.parent.update');
+
+DROP INDEX IF EXISTS idx_role_parents_parent_role_id;
+DROP TABLE IF EXISTS role_parents;
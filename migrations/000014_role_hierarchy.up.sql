@@ -0,0 +1,23 @@
+-- Role hierarchy: a role inherits every permission of its parent roles
+CREATE TABLE IF NOT EXISTS role_parents (
+  role_id uuid NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+  parent_role_id uuid NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (role_id, parent_role_id),
+  CHECK (role_id <> parent_role_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_role_parents_parent_role_id ON role_parents(parent_role_id);
+
+INSERT INTO permissions (name, description)
+VALUES
+  ('role.parent.read', 'Read role parents'),
+  ('role.parent.update', 'Update role parents')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name IN ('role.parent.read', 'role.parent.update')
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+-- Remove wildcard permission
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT id FROM permissions WHERE name = '*');
+
+DELETE FROM permissions
+WHERE name = '*';
@@ -0,0 +1,11 @@
+-- Wildcard permission: "*" matches every permission, "user.*" every user permission
+INSERT INTO permissions (name, description)
+VALUES ('*', 'All permissions')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name = '*'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
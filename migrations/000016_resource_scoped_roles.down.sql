@@ -0,0 +1,11 @@
+-- Remove resource-scoped role assignments
+DROP INDEX IF EXISTS idx_user_roles_resource;
+
+DELETE FROM user_roles WHERE resource_type <> '';
+
+ALTER TABLE user_roles DROP CONSTRAINT IF EXISTS user_roles_resource_scope_check;
+ALTER TABLE user_roles DROP CONSTRAINT IF EXISTS user_roles_pkey;
+ALTER TABLE user_roles ADD PRIMARY KEY (user_id, role_id);
+
+ALTER TABLE user_roles DROP COLUMN IF EXISTS resource_id;
+ALTER TABLE user_roles DROP COLUMN IF EXISTS resource_type;
@@ -0,0 +1,14 @@
+-- Resource-scoped role assignments: a user_roles row with a resource_type and
+-- resource_id grants the role's permissions for that resource only
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS resource_type text NOT NULL DEFAULT '';
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS resource_id text NOT NULL DEFAULT '';
+
+ALTER TABLE user_roles DROP CONSTRAINT IF EXISTS user_roles_pkey;
+ALTER TABLE user_roles ADD PRIMARY KEY (user_id, role_id, resource_type, resource_id);
+
+ALTER TABLE user_roles ADD CONSTRAINT user_roles_resource_scope_check
+  CHECK ((resource_type = '') = (resource_id = ''));
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_resource
+  ON user_roles(resource_type, resource_id)
+  WHERE resource_type <> '';
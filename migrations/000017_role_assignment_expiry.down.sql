@@ -0,0 +1,9 @@
+-- Remove time-bound role assignments; temporary grants are dropped rather
+-- than silently becoming permanent
+DROP INDEX IF EXISTS idx_user_roles_valid_until;
+
+DELETE FROM user_roles WHERE valid_from IS NOT NULL OR valid_until IS NOT NULL;
+
+ALTER TABLE user_roles DROP CONSTRAINT IF EXISTS user_roles_validity_check;
+ALTER TABLE user_roles DROP COLUMN IF EXISTS valid_until;
+ALTER TABLE user_roles DROP COLUMN IF EXISTS valid_from;
@@ -0,0 +1,11 @@
+-- Time-bound role assignments: a user_roles row only counts between
+-- valid_from and valid_until; NULL means unbounded on that side
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS valid_from timestamptz;
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS valid_until timestamptz;
+
+ALTER TABLE user_roles ADD CONSTRAINT user_roles_validity_check
+  CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_until > valid_from);
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_valid_until
+  ON user_roles(valid_until)
+  WHERE valid_until IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Remove system role and permission flags
+ALTER TABLE permissions DROP COLUMN IF EXISTS is_system;
+ALTER TABLE roles DROP COLUMN IF EXISTS is_system;
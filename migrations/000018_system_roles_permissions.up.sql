@@ -0,0 +1,34 @@
+-- System roles and permissions: seeded entries the application depends on.
+-- They cannot be deleted or renamed, and system roles keep their system
+-- permissions
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS is_system boolean NOT NULL DEFAULT false;
+ALTER TABLE permissions ADD COLUMN IF NOT EXISTS is_system boolean NOT NULL DEFAULT false;
+
+UPDATE roles SET is_system = true WHERE name = 'admin';
+
+UPDATE permissions SET is_system = true
+WHERE name IN (
+  '*',
+  'audit.read',
+  'permission.create',
+  'permission.delete',
+  'permission.read',
+  'permission.update',
+  'role.create',
+  'role.delete',
+  'role.parent.read',
+  'role.parent.update',
+  'role.permission.read',
+  'role.permission.update',
+  'role.read',
+  'role.update',
+  'user.create',
+  'user.delete',
+  'user.mfa.reset',
+  'user.read',
+  'user.role.read',
+  'user.role.update',
+  'user.session.read',
+  'user.session.revoke',
+  'user.update'
+);
@@ -0,0 +1,8 @@
+-- Remove RBAC policy permissions
+DELETE FROM role_permissions
+WHERE permission_id IN (
+  SELECT id FROM permissions WHERE name IN ('rbac.policy.export', 'rbac.policy.import')
+);
+
+DELETE FROM permissions
+WHERE name IN ('rbac.policy.export', 'rbac.policy.import');
@@ -0,0 +1,13 @@
+-- Permissions for exporting and importing the declarative RBAC policy
+INSERT INTO permissions (name, description, is_system)
+VALUES
+  ('rbac.policy.export', 'Export the RBAC policy', true),
+  ('rbac.policy.import', 'Import the RBAC policy', true)
+ON CONFLICT (name) DO UPDATE SET is_system = true;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON p.name IN ('rbac.policy.export', 'rbac.policy.import')
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+-- Remove role constraints and grant requests
+DROP INDEX IF EXISTS idx_role_grant_requests_status_created_at;
+DROP INDEX IF EXISTS idx_role_grant_requests_pending;
+DROP TABLE IF EXISTS role_grant_requests;
+
+DROP INDEX IF EXISTS idx_role_exclusions_excluded_role_id;
+DROP TABLE IF EXISTS role_exclusions;
+
+ALTER TABLE roles DROP COLUMN IF EXISTS requires_approval;
@@ -0,0 +1,34 @@
+-- Separation of duties and approval of privileged role grants.
+-- requires_approval only takes effect when ROLE_APPROVAL_ENABLED is set.
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS requires_approval boolean NOT NULL DEFAULT false;
+
+UPDATE roles SET requires_approval = true WHERE name = 'admin';
+
+-- Pairs of roles a user may not hold together, stored once per pair
+CREATE TABLE IF NOT EXISTS role_exclusions (
+  role_id uuid NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+  excluded_role_id uuid NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (role_id, excluded_role_id),
+  CHECK (role_id < excluded_role_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_role_exclusions_excluded_role_id ON role_exclusions(excluded_role_id);
+
+CREATE TABLE IF NOT EXISTS role_grant_requests (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role_id uuid NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+  valid_from timestamptz,
+  valid_until timestamptz,
+  requested_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  decided_by uuid REFERENCES users(id) ON DELETE SET NULL,
+  decided_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_grant_requests_pending
+  ON role_grant_requests(user_id, role_id)
+  WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_role_grant_requests_status_created_at ON role_grant_requests(status, created_at);
@@ -0,0 +1,2 @@
+-- Remove external identities
+DROP TABLE IF EXISTS user_identities;
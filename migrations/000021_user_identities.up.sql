@@ -0,0 +1,14 @@
+-- External identities (OpenID Connect) linked to local users.
+-- Users created through an external login have an empty password_hash and
+-- cannot sign in with a password until they set one.
+CREATE TABLE IF NOT EXISTS user_identities (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider text NOT NULL,
+  subject text NOT NULL,
+  email citext,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  last_login_at timestamptz,
+  UNIQUE (provider, subject),
+  UNIQUE (user_id, provider)
+);
@@ -0,0 +1,15 @@
+-- Previous password hashes, newest first, so a password cannot be reused.
+-- The repository keeps the latest 24 per user (the PASSWORD_HISTORY maximum).
+CREATE TABLE IF NOT EXISTS password_history (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  password_hash text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_history_user_id_created_at ON password_history(user_id, created_at DESC);
+
+INSERT INTO password_history (user_id, password_hash)
+SELECT id, password_hash
+FROM users
+WHERE password_hash <> '';
@@ -0,0 +1 @@
+-- The default admin account is not restored: it had a published password.
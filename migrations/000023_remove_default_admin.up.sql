@@ -0,0 +1,10 @@
+-- Remove the admin@boiler.com account seeded by the old 000006 while it
+-- still has the published password. Accounts whose password was changed
+-- are left alone. Only bcrypt hashes can be checked here; the CASE keeps
+-- crypt() away from hashes in other formats.
+DELETE FROM users
+WHERE email = 'admin@boiler.com'
+  AND CASE
+    WHEN password_hash LIKE '$2%' THEN password_hash = crypt('abcd5dasar', password_hash)
+    ELSE false
+  END;